@@ -179,6 +179,54 @@ func (c *Cluster) ClientCertificate() (dynamic.NamespaceableResourceInterface, e
 	return dynamicClient.Resource(gvr), nil
 }
 
+// ClientTraefikMiddleware returns a dynamic namespaced client for the traefik
+// middleware resource
+func (c *Cluster) ClientTraefikMiddleware() (dynamic.NamespaceableResourceInterface, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    "traefik.containo.us",
+		Version:  "v1alpha1",
+		Resource: "middlewares",
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	return dynamicClient.Resource(gvr), nil
+}
+
+// ClientTraefikIngressRoute returns a dynamic namespaced client for the traefik
+// ingress route resource
+func (c *Cluster) ClientTraefikIngressRoute() (dynamic.NamespaceableResourceInterface, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    "traefik.containo.us",
+		Version:  "v1alpha1",
+		Resource: "ingressroutes",
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	return dynamicClient.Resource(gvr), nil
+}
+
+// ClientHTTPRoute returns a dynamic namespaced client for the gateway api
+// http route resource
+func (c *Cluster) ClientHTTPRoute() (dynamic.NamespaceableResourceInterface, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    "gateway.networking.k8s.io",
+		Version:  "v1",
+		Resource: "httproutes",
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	return dynamicClient.Resource(gvr), nil
+}
+
 // IsPodRunning returns a condition function that indicates whether the given pod is
 // currently running
 func (c *Cluster) IsPodRunning(ctx context.Context, podName, namespace string) wait.ConditionFunc {
@@ -632,16 +680,19 @@ func (c *Cluster) CreateSecret(ctx context.Context, namespace string, secret v1.
 }
 
 // CreateLabeledSecret posts a new secret to the cluster. The secret
-// is constructed from name and a key/value dictionary for labels.
+// is constructed from name and key/value dictionaries for labels and
+// annotations.
 func (c *Cluster) CreateLabeledSecret(ctx context.Context, namespace, name string,
 	data map[string][]byte,
-	label map[string]string) error {
+	label map[string]string,
+	annotation map[string]string) error {
 
 	secret := &v1.Secret{
 		Data: data,
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   name,
-			Labels: label,
+			Name:        name,
+			Labels:      label,
+			Annotations: annotation,
 		},
 	}
 	_, err := c.Kubectl.CoreV1().Secrets(namespace).Create(ctx,
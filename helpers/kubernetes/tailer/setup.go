@@ -42,6 +42,7 @@ type ContainerLogLine struct {
 	ContainerName string
 	PodName       string
 	Namespace     string
+	AppName       string
 }
 
 // FetchLogs writes all the logs of the matching containers to the logChan.
@@ -64,7 +65,7 @@ func FetchLogs(ctx context.Context, logChan chan ContainerLogLine, wg *sync.Wait
 
 	tails := []*Tail{}
 	newTail := func(pod corev1.Pod, c corev1.Container) *Tail {
-		return NewTail(pod.Namespace, pod.Name, c.Name,
+		return NewTail(pod.Namespace, pod.Name, c.Name, pod.Labels[AppLabel],
 			requestctx.Logger(ctx).WithName("log-tracing").V(4),
 			cluster.Kubectl,
 			&TailOptions{
@@ -142,7 +143,8 @@ func FetchLogs(ctx context.Context, logChan chan ContainerLogLine, wg *sync.Wait
 
 // StreamLogs writes the logs of all matching containers to the
 // logChan.  The containers are determined by an internal watcher
-// polling the cluster for pod __changes__.
+// polling the cluster for pod __changes__. It returns when the context
+// is done, or with an error when the watch cannot be re-established.
 func StreamLogs(ctx context.Context, logChan chan ContainerLogLine, wg *sync.WaitGroup, config *Config, cluster *kubernetes.Cluster) error {
 	logger := requestctx.Logger(ctx).WithName("tail-handling").V(3)
 
@@ -175,7 +177,10 @@ func StreamLogs(ctx context.Context, logChan chan ContainerLogLine, wg *sync.Wai
 	}()
 	for {
 		select {
-		case p := <-added:
+		case p, ok := <-added:
+			if !ok {
+				return watchEnded(ctx)
+			}
 			id := p.GetID()
 			if tails[id] != nil {
 				break
@@ -183,7 +188,7 @@ func StreamLogs(ctx context.Context, logChan chan ContainerLogLine, wg *sync.Wai
 
 			logger.Info("tailer add", "id", id)
 
-			tail := NewTail(p.Namespace, p.Pod, p.Container,
+			tail := NewTail(p.Namespace, p.Pod, p.Container, p.App,
 				requestctx.Logger(ctx).WithName("log-tracing"),
 				cluster.Kubectl,
 				&TailOptions{
@@ -206,7 +211,10 @@ func StreamLogs(ctx context.Context, logChan chan ContainerLogLine, wg *sync.Wai
 				logger.Info("tailer done", "id", id)
 				wg.Done()
 			}(id)
-		case p := <-removed:
+		case p, ok := <-removed:
+			if !ok {
+				return watchEnded(ctx)
+			}
 			id := p.GetID()
 			if tails[id] == nil {
 				break
@@ -221,3 +229,12 @@ func StreamLogs(ctx context.Context, logChan chan ContainerLogLine, wg *sync.Wai
 		}
 	}
 }
+
+// watchEnded returns the error of StreamLogs for the end of the pod watch. This is
+// no error if the context is done, i.e. the watch was stopped.
+func watchEnded(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return errors.New("lost the watch of the pods")
+}
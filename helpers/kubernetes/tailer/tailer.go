@@ -13,10 +13,14 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// AppLabel is the label of the pods naming the application they belong to
+const AppLabel = "app.kubernetes.io/name"
+
 type Tail struct {
 	Namespace     string
 	PodName       string
 	ContainerName string
+	AppName       string
 	Options       *TailOptions
 	logger        logr.Logger
 	clientSet     *kubernetes.Clientset
@@ -33,12 +37,14 @@ type TailOptions struct {
 	Logger       logr.Logger
 }
 
-// NewTail returns a new tail for a Kubernetes container inside a pod
-func NewTail(namespace, podName, containerName string, logger logr.Logger, clientSet *kubernetes.Clientset, options *TailOptions) *Tail {
+// NewTail returns a new tail for a Kubernetes container inside a pod, belonging
+// to the named application
+func NewTail(namespace, podName, containerName, appName string, logger logr.Logger, clientSet *kubernetes.Clientset, options *TailOptions) *Tail {
 	return &Tail{
 		Namespace:     namespace,
 		PodName:       podName,
 		ContainerName: containerName,
+		AppName:       appName,
 		Options:       options,
 		logger:        logger,
 		clientSet:     clientSet,
@@ -112,6 +118,7 @@ OUTER:
 			ContainerName: t.ContainerName,
 			PodName:       t.PodName,
 			Namespace:     t.Namespace,
+			AppName:       t.AppName,
 		}
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/pkg/errors"
@@ -15,11 +16,15 @@ import (
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+// rewatchDelay is the time to wait before re-establishing a lost watch.
+var rewatchDelay = time.Second
+
 // Target is a thing to watch the logs of. It is specified by namespace, pod, and container
 type Target struct {
 	Namespace string
 	Pod       string
 	Container string
+	App       string
 }
 
 // GetID returns the ID of the object
@@ -29,17 +34,19 @@ func (t *Target) GetID() string {
 
 // Watch starts listening to Kubernetes events and emits modified
 // containers/pods. The first result is targets added, the second is targets
-// removed
+// removed. The API server ends watches after a while, or on errors, these are
+// re-established. When that fails, or the context is done, both channels are
+// closed.
 func Watch(ctx context.Context, i v1.PodInterface, podFilter *regexp.Regexp,
 	containerFilter *regexp.Regexp, containerExcludeFilter *regexp.Regexp,
 	containerState ContainerState, labelSelector labels.Selector) (chan *Target, chan *Target, error) {
 
 	logger := requestctx.Logger(ctx).WithName("pod-watch").V(4)
+	listOptions := metav1.ListOptions{Watch: true, LabelSelector: labelSelector.String()}
 
 	logger.Info("create")
-	watcher, err := i.Watch(ctx, metav1.ListOptions{Watch: true, LabelSelector: labelSelector.String()})
+	watcher, err := i.Watch(ctx, listOptions)
 	if err != nil {
-		fmt.Printf("err.Error() = %+v\n", err.Error())
 		return nil, nil, errors.Wrap(err, "failed to set up watch")
 	}
 
@@ -49,24 +56,34 @@ func Watch(ctx context.Context, i v1.PodInterface, podFilter *regexp.Regexp,
 	go func() {
 		logger.Info("await events")
 		defer func() {
+			close(added)
+			close(removed)
 			logger.Info("event processing ends")
 		}()
 		for {
 			select {
-			case e := <-watcher.ResultChan():
+			case e, ok := <-watcher.ResultChan():
 				logger.Info("received event")
 
-				if e.Object == nil {
-					logger.Info("event error, no object")
-					// Closed because of error
-					return
-				}
+				pod, isPod := e.Object.(*corev1.Pod)
+				if !ok || e.Type == watch.Error || !isPod {
+					// The watch ended, or failed. Re-establish it. The new watch
+					// reports the existing pods as added again.
+					logger.Info("watch lost, re-establish", "closed", !ok, "type", e.Type)
+					watcher.Stop()
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(rewatchDelay):
+					}
 
-				pod, ok := e.Object.(*corev1.Pod)
-				if !ok {
-					logger.Info("event error, object not a pod")
-					// Not a Pod
-					return
+					watcher, err = i.Watch(ctx, listOptions)
+					if err != nil {
+						logger.Info("failed to re-establish watch", "error", err.Error())
+						return
+					}
+					continue
 				}
 
 				if !podFilter.MatchString(pod.Name) {
@@ -94,10 +111,14 @@ func Watch(ctx context.Context, i v1.PodInterface, podFilter *regexp.Regexp,
 
 						if containerState.Match(c.State) {
 							logger.Info("report added", "container", c.Name, "pod", pod.Name, "namespace", pod.Namespace)
-							added <- &Target{
+							target := &Target{
 								Namespace: pod.Namespace,
 								Pod:       pod.Name,
 								Container: c.Name,
+								App:       pod.Labels[AppLabel],
+							}
+							if !report(ctx, watcher, added, target) {
+								return
 							}
 						}
 
@@ -121,18 +142,20 @@ func Watch(ctx context.Context, i v1.PodInterface, podFilter *regexp.Regexp,
 						}
 
 						logger.Info("report removed", "container", c.Name, "pod", pod.Name, "namespace", pod.Namespace)
-						removed <- &Target{
+						target := &Target{
 							Namespace: pod.Namespace,
 							Pod:       pod.Name,
 							Container: c.Name,
+							App:       pod.Labels[AppLabel],
+						}
+						if !report(ctx, watcher, removed, target) {
+							return
 						}
 					}
 				}
 			case <-ctx.Done():
 				logger.Info("received stop request")
 				watcher.Stop()
-				close(added)
-				close(removed)
 				return
 			}
 		}
@@ -141,3 +164,15 @@ func Watch(ctx context.Context, i v1.PodInterface, podFilter *regexp.Regexp,
 	logger.Info("pass watch report channels")
 	return added, removed, nil
 }
+
+// report sends the target to the channel. It returns false, after stopping the
+// watcher, if the context is done before the target is received.
+func report(ctx context.Context, watcher watch.Interface, targets chan *Target, target *Target) bool {
+	select {
+	case targets <- target:
+		return true
+	case <-ctx.Done():
+		watcher.Stop()
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+package tailer
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakePods hands out the watchers, in order. Calls beyond them fail.
+type fakePods struct {
+	v1.PodInterface
+	mu       sync.Mutex
+	watchers []*watch.FakeWatcher
+	calls    int
+}
+
+func (p *fakePods) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.calls >= len(p.watchers) {
+		return nil, context.DeadlineExceeded
+	}
+	p.calls++
+	return p.watchers[p.calls-1], nil
+}
+
+var _ = Describe("Watch", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var pods *fakePods
+
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "workspace",
+				Labels: map[string]string{AppLabel: "myapp"}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "myapp",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}}},
+		}
+	}
+
+	watchPods := func() (chan *Target, chan *Target) {
+		all := regexp.MustCompile(".*")
+		added, removed, err := Watch(ctx, pods, all, all, nil, RUNNING, labels.Everything())
+		Expect(err).ToNot(HaveOccurred())
+		return added, removed
+	}
+
+	BeforeEach(func() {
+		rewatchDelay = time.Millisecond
+		ctx, cancel = context.WithCancel(context.Background())
+		pods = &fakePods{watchers: []*watch.FakeWatcher{watch.NewFake(), watch.NewFake()}}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("reports the pods of a re-established watch", func() {
+		added, _ := watchPods()
+
+		go pods.watchers[0].Add(pod("myapp-1"))
+		Eventually(added).Should(Receive(Equal(&Target{
+			Namespace: "workspace", Pod: "myapp-1", Container: "myapp", App: "myapp"})))
+
+		// The API server ends the watch, pods created later are reported still
+		pods.watchers[0].Stop()
+		go pods.watchers[1].Add(pod("myapp-2"))
+		Eventually(added).Should(Receive(Equal(&Target{
+			Namespace: "workspace", Pod: "myapp-2", Container: "myapp", App: "myapp"})))
+	})
+
+	It("closes the channels when the watch cannot be re-established", func() {
+		pods.watchers = pods.watchers[:1]
+		added, removed := watchPods()
+
+		pods.watchers[0].Stop()
+		Eventually(added).Should(BeClosed())
+		Eventually(removed).Should(BeClosed())
+	})
+
+	It("closes the channels when the context is done", func() {
+		added, removed := watchPods()
+
+		cancel()
+		Eventually(added).Should(BeClosed())
+		Eventually(removed).Should(BeClosed())
+	})
+})
@@ -0,0 +1,83 @@
+// Package secretcrypt provides symmetric encryption of small string values,
+// keyed by the contents of a local key file. It is used to protect service
+// secrets in exported documents.
+package secretcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// Key is the AES-256 key derived from the contents of a key file
+type Key [32]byte
+
+// LoadKey reads the file at path and derives an encryption key from its contents.
+func LoadKey(path string) (Key, error) {
+	var key Key
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return key, errors.Wrap(err, "failed to read key file")
+	}
+	if len(content) == 0 {
+		return key, errors.New("key file is empty")
+	}
+
+	return sha256.Sum256(content), nil
+}
+
+// Encrypt seals the plain text with the key and returns it as a base64 string,
+// the random nonce prepended to the cipher text.
+func (k Key) Encrypt(plain string) (string, error) {
+	gcm, err := k.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "failed to generate nonce")
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt. It fails if the value was sealed with a different key.
+func (k Key) Decrypt(encoded string) (string, error) {
+	gcm, err := k.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.Wrap(err, "value is not base64 encoded")
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("value is too short")
+	}
+
+	nonce, text := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, text, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt value, wrong key?")
+	}
+
+	return string(plain), nil
+}
+
+func (k Key) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
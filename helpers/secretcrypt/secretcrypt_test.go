@@ -0,0 +1,85 @@
+package secretcrypt_test
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/epinio/epinio/helpers/secretcrypt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Key", func() {
+	var dir string
+
+	keyFrom := func(content string) secretcrypt.Key {
+		path := filepath.Join(dir, "key")
+		Expect(ioutil.WriteFile(path, []byte(content), 0600)).To(Succeed())
+
+		key, err := secretcrypt.LoadKey(path)
+		Expect(err).ToNot(HaveOccurred())
+		return key
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "epinio-secretcrypt")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("decrypts what it encrypted", func() {
+		key := keyFrom("the key")
+
+		encrypted, err := key.Encrypt("s3cr3t")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(encrypted).ToNot(ContainSubstring("s3cr3t"))
+
+		plain, err := key.Decrypt(encrypted)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plain).To(Equal("s3cr3t"))
+	})
+
+	It("fails to decrypt with a different key", func() {
+		encrypted, err := keyFrom("the key").Encrypt("s3cr3t")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = keyFrom("another key").Decrypt(encrypted)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("wrong key"))
+	})
+
+	It("fails to decrypt tampered values", func() {
+		key := keyFrom("the key")
+
+		encrypted, err := key.Encrypt("s3cr3t")
+		Expect(err).ToNot(HaveOccurred())
+
+		sealed, err := base64.StdEncoding.DecodeString(encrypted)
+		Expect(err).ToNot(HaveOccurred())
+		sealed[len(sealed)-1] ^= 0x01
+
+		_, err = key.Decrypt(base64.StdEncoding.EncodeToString(sealed))
+		Expect(err).To(HaveOccurred())
+
+		_, err = key.Decrypt("not base64!")
+		Expect(err).To(HaveOccurred())
+
+		_, err = key.Decrypt(base64.StdEncoding.EncodeToString([]byte("short")))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects an empty key file", func() {
+		path := filepath.Join(dir, "empty")
+		Expect(ioutil.WriteFile(path, []byte{}, 0600)).To(Succeed())
+
+		_, err := secretcrypt.LoadKey(path)
+		Expect(err).To(HaveOccurred())
+	})
+})
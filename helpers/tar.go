@@ -1,44 +1,239 @@
 package helpers
 
 import (
+	"archive/tar"
+	"bufio"
+	"io"
 	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
-	"github.com/mholt/archiver/v3"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/pkg/errors"
 )
 
+// IgnoreFile is the name of the files holding the patterns of the sources to leave out
+// of the tarball, with the semantics of `.gitignore`.
+const IgnoreFile = ".epinioignore"
+
+// tarTime is the modification time of all entries of a tarball. A fixed time makes the
+// tarball depend on the sources only. This is the time used by the buildpacks for
+// reproducible images.
+var tarTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// alwaysIgnored are the patterns of the git and ignore files which are never part of
+// the sources.
+var alwaysIgnored = []string{".git", ".gitignore", ".gitmodules", ".gitconfig", ".git-credentials", IgnoreFile}
+
+// TarOptions specify the sources to leave out of a tarball, in addition to the git
+// files, the IgnoreFile files, and the patterns of the latter.
+type TarOptions struct {
+	// Excludes are patterns with the semantics of `.gitignore`, relative to the
+	// directory of the sources. They take precedence over the ignore files.
+	Excludes []string
+	// GitIgnore adds the patterns of the `.gitignore` files to those of the IgnoreFile
+	// files.
+	GitIgnore bool
+}
+
+// TarStats describes a tarball made by TarWithOptions.
+type TarStats struct {
+	Files int   // Number of regular files
+	Size  int64 // Size of the tarball, in bytes
+}
+
+// Tar creates a tarball of the sources in the directory, with the default options. It
+// returns the temporary directory holding the tarball, to remove by the caller, and the
+// path of the tarball.
 func Tar(dir string) (string, string, error) {
-	files, err := ioutil.ReadDir(dir)
-	if err != nil {
-		return "", "", errors.Wrap(err, "cannot read the apps source files")
-	}
-	sources := []string{}
-	for _, f := range files {
-		// The FileInfo entries returned by ReadDir provide
-		// only the base name of the file or directory they
-		// are for. We have to add back the path of the
-		// application directory to get the proper paths to
-		// the files and directories to assemble in the
-		// tarball.
-		// Ignore git config files in the app sources.
-		if f.Name() == ".git" || f.Name() == ".gitignore" || f.Name() == ".gitmodules" || f.Name() == ".gitconfig" || f.Name() == ".git-credentials" {
-			continue
-		}
-		sources = append(sources, path.Join(dir, f.Name()))
+	tmpDir, tarball, _, err := TarWithOptions(dir, TarOptions{})
+	return tmpDir, tarball, err
+}
+
+// TarWithOptions creates a tarball of the sources in the directory, leaving out the
+// sources matched by the patterns of the options and ignore files. It returns the
+// temporary directory holding the tarball, to remove by the caller, the path of the
+// tarball, and its stats.
+//
+// The tarball is deterministic: entries are in lexical order, and their times and
+// owners are fixed. Identical sources result in identical tarballs.
+func TarWithOptions(dir string, options TarOptions) (string, string, TarStats, error) {
+	stats := TarStats{}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", "", stats, errors.Wrap(err, "cannot read the apps source files")
+	}
+	if !info.IsDir() {
+		return "", "", stats, errors.Errorf("cannot read the apps source files: %s is not a directory", dir)
+	}
+
+	ignoreFiles := []string{IgnoreFile}
+	if options.GitIgnore {
+		ignoreFiles = append(ignoreFiles, ".gitignore")
+	}
+
+	patterns := []gitignore.Pattern{}
+	for _, p := range alwaysIgnored {
+		patterns = append(patterns, gitignore.ParsePattern(p, nil))
+	}
+	excludes := []gitignore.Pattern{}
+	for _, p := range options.Excludes {
+		excludes = append(excludes, gitignore.ParsePattern(p, nil))
 	}
 
 	// create a tmpDir - tarball dir and POST
 	tmpDir, err := ioutil.TempDir("", "epinio-app")
 	if err != nil {
-		return "", "", errors.Wrap(err, "can't create temp directory")
+		return "", "", stats, errors.Wrap(err, "can't create temp directory")
 	}
 
 	tarball := path.Join(tmpDir, "blob.tar")
-	err = archiver.Archive(sources, tarball)
+	out, err := os.Create(tarball)
+	if err != nil {
+		return tmpDir, "", stats, errors.Wrap(err, "can't create archive")
+	}
+	defer out.Close()
+
+	tw := tar.NewWriter(out)
+
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		var components []string
+		if rel != "." {
+			components = strings.Split(filepath.ToSlash(rel), "/")
+
+			matcher := gitignore.NewMatcher(append(append([]gitignore.Pattern{}, patterns...), excludes...))
+			if matcher.Match(components, info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		if info.IsDir() {
+			// Patterns of the directory's ignore files apply to its contents
+			for _, ignoreFile := range ignoreFiles {
+				ps, err := readIgnoreFile(filepath.Join(file, ignoreFile), components)
+				if err != nil {
+					return err
+				}
+				patterns = append(patterns, ps...)
+			}
+			if rel == "." {
+				return nil
+			}
+		}
+
+		return tarEntry(tw, file, filepath.ToSlash(rel), info, &stats)
+	})
+	if err != nil {
+		return tmpDir, "", stats, errors.Wrap(err, "can't create archive")
+	}
+
+	if err := tw.Close(); err != nil {
+		return tmpDir, "", stats, errors.Wrap(err, "can't create archive")
+	}
+
+	info, err = out.Stat()
+	if err != nil {
+		return tmpDir, "", stats, errors.Wrap(err, "can't create archive")
+	}
+	stats.Size = info.Size()
+
+	return tmpDir, tarball, stats, nil
+}
+
+// tarEntry writes the file as entry of the tarball, with normalized times and owners.
+// Files other than directories, regular files and symbolic links are left out.
+func tarEntry(tw *tar.Writer, file, name string, info os.FileInfo, stats *TarStats) error {
+	mode := info.Mode()
+	if !mode.IsDir() && !mode.IsRegular() && mode&os.ModeSymlink == 0 {
+		return nil
+	}
+
+	link := ""
+	if mode&os.ModeSymlink != 0 {
+		var err error
+		link, err = os.Readlink(file)
+		if err != nil {
+			return err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+
+	header.Name = name
+	if mode.IsDir() {
+		header.Name += "/"
+	}
+	header.ModTime = tarTime
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+	header.Uid = 0
+	header.Gid = 0
+	header.Uname = ""
+	header.Gname = ""
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !mode.IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(file)
 	if err != nil {
-		return tmpDir, "", errors.Wrap(err, "can't create archive")
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(tw, f); err != nil {
+		return err
+	}
+
+	stats.Files++
+	return nil
+}
+
+// readIgnoreFile returns the patterns of the ignore file, for the sources in the
+// directory at the domain, i.e. path relative to the root of the sources. A missing
+// file has no patterns.
+func readIgnoreFile(file string, domain []string) ([]gitignore.Pattern, error) {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	patterns := []gitignore.Pattern{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
 	}
 
-	return tmpDir, tarball, nil
+	return patterns, scanner.Err()
 }
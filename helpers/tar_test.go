@@ -0,0 +1,142 @@
+package helpers_test
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/epinio/epinio/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TarWithOptions", func() {
+	var dir string
+	var tmpDirs []string
+
+	write := func(name, content string) {
+		file := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(file), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(file, []byte(content), 0600)).To(Succeed())
+	}
+
+	tarball := func(options helpers.TarOptions) (string, helpers.TarStats) {
+		tmpDir, tarball, stats, err := helpers.TarWithOptions(dir, options)
+		tmpDirs = append(tmpDirs, tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		return tarball, stats
+	}
+
+	entries := func(options helpers.TarOptions) []string {
+		file, _ := tarball(options)
+		f, err := os.Open(file)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+
+		names := []string{}
+		tr := tar.NewReader(f)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(header.ModTime.Equal(time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+			names = append(names, header.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "epinio-sources")
+		Expect(err).ToNot(HaveOccurred())
+		tmpDirs = []string{}
+
+		write("main.go", "package main")
+		write("src/app.go", "package src")
+		write("node_modules/lib/index.js", "module")
+		write("build/app", "binary")
+		write(".env", "SECRET=1")
+		write(".git/config", "[core]")
+		write(".gitignore", "build/\n")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+		for _, tmpDir := range tmpDirs {
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		}
+	})
+
+	It("leaves out the git files only, by default", func() {
+		Expect(entries(helpers.TarOptions{})).To(Equal([]string{
+			".env",
+			"build/",
+			"build/app",
+			"main.go",
+			"node_modules/",
+			"node_modules/lib/",
+			"node_modules/lib/index.js",
+			"src/",
+			"src/app.go",
+		}))
+	})
+
+	It("honours the ignore files, in all directories", func() {
+		write(".epinioignore", "# dependencies\nnode_modules/\n.env\n")
+		write("src/.epinioignore", "*.go\n!app.go\n")
+		write("src/other.go", "package src")
+
+		Expect(entries(helpers.TarOptions{})).To(Equal([]string{
+			"build/",
+			"build/app",
+			"main.go",
+			"src/",
+			"src/app.go",
+		}))
+	})
+
+	It("honours .gitignore when asked to", func() {
+		Expect(entries(helpers.TarOptions{GitIgnore: true})).ToNot(ContainElement("build/app"))
+	})
+
+	It("gives the excludes precedence over the ignore files", func() {
+		write(".epinioignore", "*.go\n!main.go\n")
+
+		Expect(entries(helpers.TarOptions{Excludes: []string{"main.go", "build"}})).To(Equal([]string{
+			".env",
+			"node_modules/",
+			"node_modules/lib/",
+			"node_modules/lib/index.js",
+			"src/",
+		}))
+	})
+
+	It("reports the number of files and the size", func() {
+		file, stats := tarball(helpers.TarOptions{})
+		info, err := os.Stat(file)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(stats.Files).To(Equal(5))
+		Expect(stats.Size).To(Equal(info.Size()))
+	})
+
+	It("is deterministic", func() {
+		first, _ := tarball(helpers.TarOptions{})
+
+		later := time.Now().Add(time.Hour)
+		Expect(os.Chtimes(filepath.Join(dir, "main.go"), later, later)).To(Succeed())
+
+		second, _ := tarball(helpers.TarOptions{})
+
+		a, err := ioutil.ReadFile(first)
+		Expect(err).ToNot(HaveOccurred())
+		b, err := ioutil.ReadFile(second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(a).To(Equal(b))
+	})
+})
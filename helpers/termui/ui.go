@@ -2,14 +2,18 @@ package termui
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/kyokomi/emoji"
 	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
 )
 
 type msgType int
@@ -36,10 +40,19 @@ const (
 	ask
 )
 
+// Output formats of the CLI. In the machine-readable formats, json and yaml, the
+// commands print their results to stdout, and all messages to stderr.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
 // UI contains functionality for dealing with the user
 // on the CLI
 type UI struct {
-	verbosity int // Verbosity level for user messages.
+	verbosity int    // Verbosity level for user messages.
+	output    string // Output format, see OutputTable, etc.
 }
 
 // Message represents a piece of information we want displayed to the user
@@ -77,7 +90,54 @@ type Progress interface {
 func NewUI() *UI {
 	return &UI{
 		verbosity: verbosity(),
+		output:    OutputFormat(),
+	}
+}
+
+// ValidateOutputFormat checks that the format is a supported output format.
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML:
+		return nil
+	}
+	return errors.Errorf("bad output format '%s', expected one of table, json, yaml", format)
+}
+
+// MachineReadable returns true if the requested output format is json or yaml.
+func (u *UI) MachineReadable() bool {
+	return u.output == OutputJSON || u.output == OutputYAML
+}
+
+// Result prints the value to stdout in the requested machine-readable format.
+// It does nothing for the table format, where the messages show the results.
+func (u *UI) Result(value interface{}) error {
+	var data []byte
+	var err error
+
+	switch u.output {
+	case OutputJSON:
+		data, err = json.MarshalIndent(value, "", "  ")
+		data = append(data, '\n')
+	case OutputYAML:
+		data, err = yaml.Marshal(value)
+	default:
+		return nil
 	}
+	if err != nil {
+		return errors.Wrap(err, "failed to format result")
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// writer returns the destination of messages. The machine-readable formats
+// keep stdout for the results.
+func (u *UI) writer() io.Writer {
+	if u.MachineReadable() {
+		return color.Error
+	}
+	return color.Output
 }
 
 // Progress creates, configures, and returns an active progress
@@ -167,9 +227,11 @@ func (u *Message) Msg(message string) {
 
 	message = emoji.Sprint(message)
 
+	out := u.ui.writer()
+
 	// Print a newline before starting output, if not compact.
 	if message != "" && !u.compact {
-		fmt.Println()
+		fmt.Fprintln(out)
 	}
 
 	if !u.keepline {
@@ -194,12 +256,12 @@ func (u *Message) Msg(message string) {
 		message = color.RedString(message)
 	}
 
-	fmt.Fprintf(color.Output, "%s", message)
+	fmt.Fprintf(out, "%s", message)
 
 	for _, interaction := range u.interactions {
 		switch interaction.variant {
 		case ask:
-			fmt.Printf("> ")
+			fmt.Fprintf(out, "> ")
 			switch interaction.valueType {
 			case tBool:
 				interaction.value = readBool()
@@ -211,17 +273,22 @@ func (u *Message) Msg(message string) {
 		case show:
 			switch interaction.valueType {
 			case tBool:
-				fmt.Fprintf(color.Output, "%s: %s\n", emoji.Sprint(interaction.name), color.MagentaString("%t", interaction.value))
+				fmt.Fprintf(out, "%s: %s\n", emoji.Sprint(interaction.name), color.MagentaString("%t", interaction.value))
 			case tInt:
-				fmt.Fprintf(color.Output, "%s: %s\n", emoji.Sprint(interaction.name), color.CyanString("%d", interaction.value))
+				fmt.Fprintf(out, "%s: %s\n", emoji.Sprint(interaction.name), color.CyanString("%d", interaction.value))
 			case tString:
-				fmt.Fprintf(color.Output, "%s: %s\n", emoji.Sprint(interaction.name), color.GreenString("%s", interaction.value))
+				fmt.Fprintf(out, "%s: %s\n", emoji.Sprint(interaction.name), color.GreenString("%s", interaction.value))
 			}
 		}
 	}
 
+	// Tables are results, which the machine-readable formats print separately.
 	for idx, headers := range u.tableHeaders {
-		table := tablewriter.NewWriter(color.Output)
+		if u.ui.MachineReadable() {
+			break
+		}
+
+		table := tablewriter.NewWriter(out)
 		table.SetHeader(headers)
 		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 		table.SetCenterSeparator("|")
@@ -389,3 +456,12 @@ func readInt() int {
 func verbosity() int {
 	return viper.GetInt("verbosity")
 }
+
+// OutputFormat returns the output format argument, defaulting to tables
+func OutputFormat() string {
+	format := viper.GetString("output-format")
+	if format == "" {
+		return OutputTable
+	}
+	return format
+}
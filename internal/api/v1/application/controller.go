@@ -5,8 +5,14 @@ import (
 	"context"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	ingressprovider "github.com/epinio/epinio/internal/ingress"
 	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/internal/volumes"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // Controller represents all functionality of the API related to applications
@@ -26,3 +32,60 @@ func (c Controller) validateNamespace(ctx context.Context, cluster *kubernetes.C
 
 	return nil
 }
+
+// validateRoutes checks that the options of the routes are valid and supported
+// by the ingress provider, and that none of the routes is already claimed by
+// another application, in any namespace.
+func (c Controller) validateRoutes(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, routeList []string) apierror.APIErrors {
+	provider := ingressprovider.Current()
+	for _, route := range routeList {
+		r, err := routes.Parse(route)
+		if err != nil {
+			return apierror.NewBadRequest(err.Error())
+		}
+		if err := ingressprovider.CheckSupported(provider, r); err != nil {
+			return apierror.NewBadRequest(err.Error())
+		}
+	}
+
+	conflicts, err := application.RouteConflicts(ctx, cluster, appRef, routeList)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	issues := []apierror.APIError{}
+	for _, conflict := range conflicts {
+		issues = append(issues, apierror.RouteInUse(conflict.Route, conflict.Owner.Namespace, conflict.Owner.Name))
+	}
+
+	return apierror.NewMultiError(issues)
+}
+
+// validateVolumes checks that the volumes to bind exist in the namespace, and
+// that their mount paths are valid and distinct.
+func (c Controller) validateVolumes(ctx context.Context, cluster *kubernetes.Cluster, namespace string, mounts models.VolumeMounts) apierror.APIErrors {
+	paths := map[string]string{}
+	for _, name := range mounts.Names() {
+		path := mounts[name]
+		if err := volumes.ValidateMountPath(path); err != nil {
+			return apierror.NewBadRequest(err.Error())
+		}
+		if other, ok := paths[path]; ok {
+			return apierror.NewBadRequest("volumes '" + other + "' and '" + name + "' have the same mount path")
+		}
+		paths[path] = name
+
+		if _, err := volumes.Get(ctx, cluster, namespace, name); err != nil {
+			if apierrors.IsNotFound(err) {
+				return apierror.VolumeIsNotKnown(name)
+			}
+			return apierror.InternalError(err)
+		}
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/domain"
+	"github.com/epinio/epinio/internal/quota"
 	"github.com/epinio/epinio/internal/services"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -74,27 +75,54 @@ func (hc Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierror.NewMultiError(theIssues)
 	}
 
+	desired := DefaultInstances
+	if createRequest.Configuration.Instances != nil {
+		desired = *createRequest.Configuration.Instances
+	}
+
+	violations, err := quota.CheckApp(ctx, cluster, appRef, desired, true)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if len(violations) > 0 {
+		return apierror.QuotaExceeded(namespace, violations...)
+	}
+
+	internal := createRequest.Configuration.Internal != nil && *createRequest.Configuration.Internal
+	if internal && len(createRequest.Configuration.Routes) > 0 {
+		return apierror.NewBadRequest("internal applications have no routes")
+	}
+
 	var routes []string
-	if len(createRequest.Configuration.Routes) > 0 {
+	if internal {
+		routes = []string{}
+	} else if len(createRequest.Configuration.Routes) > 0 {
 		routes = createRequest.Configuration.Routes
 	} else {
-		route, err := domain.AppDefaultRoute(ctx, createRequest.Name)
+		route, err := domain.AppDefaultRoute(ctx, cluster, createRequest.Name, namespace)
 		if err != nil {
 			return apierror.InternalError(err)
 		}
 		routes = []string{route}
 	}
 
-	// Arguments found OK, now we can modify the system state
-
-	err = application.Create(ctx, cluster, appRef, username, routes)
+	rejected, err := domain.CheckRoutes(ctx, cluster, namespace, routes)
 	if err != nil {
 		return apierror.InternalError(err)
 	}
+	if len(rejected) > 0 {
+		return apierror.RoutesNotOwned(namespace, rejected...)
+	}
 
-	desired := DefaultInstances
-	if createRequest.Configuration.Instances != nil {
-		desired = *createRequest.Configuration.Instances
+	if err := hc.validateRoutes(ctx, cluster, appRef, routes); err != nil {
+		return err
+	}
+
+	// Arguments found OK, now we can modify the system state
+
+	err = application.Create(ctx, cluster, appRef, username, routes, internal)
+	if err != nil {
+		return apierror.InternalError(err)
 	}
 
 	err = application.ScalingSet(ctx, cluster, appRef, desired)
@@ -1,6 +1,8 @@
 package application
 
 import (
+	"strings"
+
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
@@ -35,6 +37,17 @@ func (hc Controller) Delete(c *gin.Context) apierror.APIErrors {
 		return apierror.AppIsNotKnown(appName)
 	}
 
+	// The data of the bound volumes outlives the application. Require their
+	// explicit release, so that it is not orphaned by accident.
+	volumes, err := application.BoundVolumes(ctx, cluster, app)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if len(volumes) > 0 {
+		return apierror.NewBadRequest("bound volumes exist, unbind them first",
+			strings.Join(volumes.Names(), ", "))
+	}
+
 	services, err := application.BoundServiceNames(ctx, cluster, app)
 	if err != nil {
 		return apierror.InternalError(err)
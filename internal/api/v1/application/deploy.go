@@ -13,9 +13,14 @@ import (
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/domain"
 	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/internal/ingress"
 	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/quota"
 	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/internal/routes"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
@@ -34,7 +39,9 @@ type deployParam struct {
 	Stage       models.StageRef
 	Owner       metav1.OwnerReference
 	Environment models.EnvVariableList
+	Defaults    models.EnvVariableList // namespace defaults not overridden by Environment
 	Services    application.AppServiceBindList
+	Volumes     models.VolumeMounts
 }
 
 // Deploy handles the API endpoint /namespaces/:namespace/applications/:app/deploy
@@ -85,12 +92,43 @@ func (hc Controller) Deploy(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err, "failed to access application's desired instances")
 	}
 
+	// The quota may have changed since the app was created or last scaled
+	violations, err := quota.CheckApp(ctx, cluster, req.App, instances, false)
+	if err != nil {
+		return apierror.InternalError(err, "failed to check namespace quota")
+	}
+	if len(violations) > 0 {
+		return apierror.QuotaExceeded(namespace, violations...)
+	}
+
+	// The domains owned by the namespace may have changed since the routes were set
+	desiredRoutes, err := application.DesiredRoutes(ctx, cluster, req.App)
+	if err != nil {
+		return apierror.InternalError(err, "failed to access application's routes")
+	}
+	rejected, err := domain.CheckRoutes(ctx, cluster, namespace, desiredRoutes)
+	if err != nil {
+		return apierror.InternalError(err, "failed to check route domains")
+	}
+	if len(rejected) > 0 {
+		return apierror.RoutesNotOwned(namespace, rejected...)
+	}
+	if err := hc.validateRoutes(ctx, cluster, req.App, desiredRoutes); err != nil {
+		return err
+	}
+
 	// determine runtime environment, if any
 	environment, err := application.Environment(ctx, cluster, req.App)
 	if err != nil {
 		return apierror.InternalError(err, "failed to access application's runtime environment")
 	}
 
+	// determine the namespace defaults for the variables not set by the app
+	defaults, err := namespaces.Environment(ctx, cluster, req.App.Namespace)
+	if err != nil {
+		return apierror.InternalError(err, "failed to access namespace's default environment")
+	}
+
 	// determine bound services, if any
 	services, err := application.BoundServices(ctx, cluster, req.App)
 	if err != nil {
@@ -102,11 +140,19 @@ func (hc Controller) Deploy(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err, "failed to process application's bound services")
 	}
 
+	// determine bound volumes, if any
+	volumes, err := application.BoundVolumes(ctx, cluster, req.App)
+	if err != nil {
+		return apierror.InternalError(err, "failed to access application's bound volumes")
+	}
+
 	deployParams := deployParam{
 		AppRef:      req.App,
 		Owner:       owner,
 		Environment: environment.List(),
+		Defaults:    defaults.Without(environment.Names()).List(),
 		Services:    bindings,
+		Volumes:     volumes,
 		Instances:   instances,
 		ImageURL:    req.ImageURL,
 		Username:    username,
@@ -133,6 +179,8 @@ func (hc Controller) Deploy(c *gin.Context) apierror.APIErrors {
 
 	log.Info("deploying app service", "namespace", namespace, "app", req.App)
 
+	// The service for the HTTP routes. The service for gRPC routes, which talks h2c,
+	// is managed by SyncIngresses, as it exists for apps with such routes only.
 	svc := newAppService(req.App, username)
 
 	log.Info("app service", "name", svc.ObjectMeta.Name)
@@ -155,6 +203,30 @@ func (hc Controller) Deploy(c *gin.Context) apierror.APIErrors {
 		}
 	}
 
+	// Make the app service reachable under the name of the app, for the other
+	// apps of the namespace. A foreign service of that name is left alone.
+	alias := newAppAlias(req.App, username)
+	alias.SetOwnerReferences([]metav1.OwnerReference{owner})
+	if _, err := cluster.Kubectl.CoreV1().Services(req.App.Namespace).Create(ctx, alias, metav1.CreateOptions{}); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return apierror.InternalError(err)
+		}
+
+		service, err := cluster.Kubectl.CoreV1().Services(req.App.Namespace).Get(ctx, alias.Name, metav1.GetOptions{})
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+
+		if application.IsAlias(service.Labels, req.App) {
+			alias.ResourceVersion = service.ResourceVersion
+			if _, err := cluster.Kubectl.CoreV1().Services(req.App.Namespace).Update(ctx, alias, metav1.UpdateOptions{}); err != nil {
+				return apierror.InternalError(err)
+			}
+		} else {
+			log.Info("skipping app alias, name in use", "namespace", namespace, "app", req.App)
+		}
+	}
+
 	routes, err := application.SyncIngresses(ctx, cluster, req.App, username)
 	if err != nil {
 		return apierror.InternalError(err, "syncing application Ingresses")
@@ -208,6 +280,7 @@ func newAppDeployment(stageID string, deployParams deployParam) *appsv1.Deployme
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &deployParams.Instances,
+			Strategy: application.DeploymentStrategy(deployParams.Volumes),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app.kubernetes.io/name": deployParams.Name,
@@ -223,7 +296,8 @@ func newAppDeployment(stageID string, deployParams deployParam) *appsv1.Deployme
 				Spec: v1.PodSpec{
 					ServiceAccountName:           deployParams.Namespace,
 					AutomountServiceAccountToken: &automountServiceAccountToken,
-					Volumes:                      deployParams.Services.ToVolumesArray(),
+					Volumes: append(deployParams.Services.ToVolumesArray(),
+						application.VolumesArray(deployParams.Volumes)...),
 					Containers: []v1.Container{
 						{
 							Name:  deployParams.Name,
@@ -233,8 +307,10 @@ func newAppDeployment(stageID string, deployParams deployParam) *appsv1.Deployme
 									ContainerPort: 8080,
 								},
 							},
-							Env:          deployParams.Environment.ToEnvVarArray(deployParams.AppRef),
-							VolumeMounts: deployParams.Services.ToMountsArray(),
+							Env: append(deployParams.Environment.ToEnvVarArray(deployParams.AppRef),
+								deployParams.Defaults.ToSecretRefArray(namespaces.EnvSecretName)...),
+							VolumeMounts: append(deployParams.Services.ToMountsArray(),
+								application.VolumeMountsArray(deployParams.Volumes)...),
 						},
 					},
 				},
@@ -243,16 +319,14 @@ func newAppDeployment(stageID string, deployParams deployParam) *appsv1.Deployme
 	}
 }
 
-// newAppService is a helper that creates the kube service resource for the app
+// newAppService is a helper that creates the kube service resource for the app,
+// for its HTTP routes
 func newAppService(app models.AppRef, username string) *v1.Service {
 	return &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      names.ServiceName(app.Name),
-			Namespace: app.Namespace,
-			Annotations: map[string]string{
-				"traefik.ingress.kubernetes.io/router.entrypoints": "websecure",
-				"traefik.ingress.kubernetes.io/router.tls":         "true",
-			},
+			Name:        names.ServiceName(app.Name),
+			Namespace:   app.Namespace,
+			Annotations: ingress.Current().ServiceAnnotations(routes.TypeHTTP),
 			Labels: map[string]string{
 				"app.kubernetes.io/component":  "application",
 				"app.kubernetes.io/managed-by": "epinio",
@@ -278,6 +352,34 @@ func newAppService(app models.AppRef, username string) *v1.Service {
 	}
 }
 
+// newAppAlias is a helper that creates the kube service resource aliasing the
+// service of the app under the name of the app
+func newAppAlias(app models.AppRef, username string) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app.Name,
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/component":  application.AliasComponent,
+				"app.kubernetes.io/managed-by": "epinio",
+				"app.kubernetes.io/name":       app.Name,
+				"app.kubernetes.io/part-of":    app.Namespace,
+				"app.kubernetes.io/created-by": username,
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Type:         v1.ServiceTypeExternalName,
+			ExternalName: application.ServiceHost(names.ServiceName(app.Name), app.Namespace),
+			Ports: []v1.ServicePort{
+				{
+					Port:     8080,
+					Protocol: v1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
 // replaceInternalRegistry replaces the registry part of ImageURL with the localhost
 // version of the internal Epinio registry if one is found in the registry connection
 // details.
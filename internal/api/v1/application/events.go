@@ -0,0 +1,54 @@
+package application
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Events handles the API endpoint GET /namespaces/:namespace/applications/:app/events
+// It returns the kube events of the resources of the specified application, and
+// the diagnostics of its instances, i.e. their last container terminations.
+func (hc Controller) Events(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := hc.validateNamespace(ctx, cluster, namespace); err != nil {
+		return err
+	}
+
+	appRef := models.NewAppRef(appName, namespace)
+
+	exists, err := application.Exists(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.AppIsNotKnown(appName)
+	}
+
+	events, err := application.Events(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	instances, err := application.Diagnostics(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, models.AppEventsResponse{
+		Events:    events,
+		Instances: instances,
+	})
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
 
 	"github.com/gorilla/websocket"
@@ -75,6 +77,12 @@ func (hc Controller) Logs(c *gin.Context) {
 
 	followStr := c.Query("follow")
 
+	params, err := logParameters(c)
+	if err != nil {
+		response.Error(c, apierror.BadRequest(err))
+		return
+	}
+
 	log.Info("upgrade to web socket")
 
 	var upgrader = websocket.Upgrader{}
@@ -89,7 +97,9 @@ func (hc Controller) Logs(c *gin.Context) {
 	log.Info("streaming mode", "follow", follow)
 	log.Info("streaming begin")
 
-	err = hc.streamPodLogs(ctx, conn, namespace, appName, stageID, cluster, follow)
+	err = hc.streamPodLogs(ctx, conn, func(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.WaitGroup) error {
+		return application.Logs(ctx, logChan, wg, cluster, follow, appName, stageID, namespace, params)
+	})
 	if err != nil {
 		log.V(1).Error(err, "error occurred after upgrading the websockets connection")
 		return
@@ -98,12 +108,15 @@ func (hc Controller) Logs(c *gin.Context) {
 	log.Info("streaming completed")
 }
 
-// streamPodLogs sends the logs of any containers matching namespaceName, appName
-// and stageID to hc.conn (websockets) until ctx is Done or the connection is
-// closed.
+// logBackend writes log lines to the logChan, until the ctx is Done. See
+// application.Logs and application.NamespaceLogs.
+type logBackend func(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.WaitGroup) error
+
+// streamPodLogs sends the logs written by the backend to conn (websockets)
+// until ctx is Done or the connection is closed.
 // Internally this uses two concurrent "threads" talking with each other
 // over the logChan. This is a channel of ContainerLogLine.
-// The first thread runs `application.Logs` in a go routine. It spins up a number of supporting go routines
+// The first thread runs the backend, i.e. `application.Logs`, in a go routine. It spins up a number of supporting go routines
 // that are stopped when the passed context is "Done()". The parent go routine
 // waits until all the subordinate routines are stopped. It does this by waiting on a WaitGroup.
 // When that happens the parent go routine closes the logChan. This signals
@@ -113,7 +126,7 @@ func (hc Controller) Logs(c *gin.Context) {
 // connection is closed. In any case it will call the cancel func that will stop
 // all the children go routines described above and then will wait for their parent
 // go routine to stop too (using another WaitGroup).
-func (hc Controller) streamPodLogs(ctx context.Context, conn *websocket.Conn, namespaceName, appName, stageID string, cluster *kubernetes.Cluster, follow bool) error {
+func (hc Controller) streamPodLogs(ctx context.Context, conn *websocket.Conn, backend logBackend) error {
 	logger := requestctx.Logger(ctx).WithName("streamer-to-websockets").V(1)
 	logChan := make(chan tailer.ContainerLogLine)
 	logCtx, logCancelFunc := context.WithCancel(ctx)
@@ -121,18 +134,18 @@ func (hc Controller) streamPodLogs(ctx context.Context, conn *websocket.Conn, na
 
 	wg.Add(1)
 	go func(outerWg *sync.WaitGroup) {
-		logger.Info("create backend", "follow", follow, "app", appName, "stage", stageID, "namespace", namespaceName)
+		logger.Info("create backend")
 		defer func() {
 			logger.Info("backend ends")
 		}()
 
 		var tailWg sync.WaitGroup
-		err := application.Logs(logCtx, logChan, &tailWg, cluster, follow, appName, stageID, namespaceName)
+		err := backend(logCtx, logChan, &tailWg)
 		if err != nil {
 			logger.Error(err, "setting up log routines failed")
 		}
 
-		logger.Info("wait for backend completion", "follow", follow, "app", appName, "stage", stageID, "namespace", namespaceName)
+		logger.Info("wait for backend completion")
 		tailWg.Wait()  // Wait until all child routines are stopped
 		close(logChan) // Close the channel so the loop below can stop
 		outerWg.Done() // Let the outer method know we are done
@@ -190,3 +203,26 @@ func (hc Controller) streamPodLogs(ctx context.Context, conn *websocket.Conn, na
 
 	return conn.Close()
 }
+
+// logParameters is a helper for Logs. It reads and validates the query
+// parameters restricting the log lines.
+func logParameters(c *gin.Context) (application.LogParameters, error) {
+	params := models.LogParameters{
+		Since:      c.Query("since"),
+		Include:    c.QueryArray("include"),
+		Exclude:    c.QueryArray("exclude"),
+		Instance:   c.Query("instance"),
+		Container:  c.Query("container"),
+		Timestamps: c.Query("timestamps") == "true",
+	}
+
+	if tailStr := c.Query("tail"); tailStr != "" {
+		tail, err := strconv.ParseInt(tailStr, 10, 64)
+		if err != nil {
+			return application.LogParameters{}, errors.Errorf("bad tail '%s', expected a number of lines", tailStr)
+		}
+		params.Tail = &tail
+	}
+
+	return application.NewLogParameters(params)
+}
@@ -0,0 +1,88 @@
+package application
+
+import (
+	"context"
+	"sync"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+
+	"github.com/gorilla/websocket"
+)
+
+// NamespaceLogs handles the API endpoint GET /namespaces/:namespace/logs
+// It arranges for the logs of the applications of the namespace to be streamed
+// over a websocket, interleaved. The `app` query parameter, which can be
+// repeated, restricts the stream to the named applications.
+func (hc Controller) NamespaceLogs(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	apps := c.QueryArray("app")
+
+	log.Info("get cluster client")
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		response.Error(c, apierror.InternalError(err))
+		return
+	}
+
+	log.Info("validate namespace", "name", namespace)
+	if err := hc.validateNamespace(ctx, cluster, namespace); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	for _, appName := range apps {
+		log.Info("validate application", "name", appName, "namespace", namespace)
+
+		exists, err := application.Exists(ctx, cluster, models.NewAppRef(appName, namespace))
+		if err != nil {
+			response.Error(c, apierror.InternalError(err))
+			return
+		}
+		if !exists {
+			response.Error(c, apierror.AppIsNotKnown(appName))
+			return
+		}
+	}
+
+	log.Info("process query")
+
+	follow := c.Query("follow") == "true"
+
+	params, err := logParameters(c)
+	if err != nil {
+		response.Error(c, apierror.BadRequest(err))
+		return
+	}
+
+	log.Info("upgrade to web socket")
+
+	var upgrader = websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		response.Error(c, apierror.InternalError(err))
+		return
+	}
+
+	log.Info("streaming mode", "follow", follow)
+	log.Info("streaming begin")
+
+	err = hc.streamPodLogs(ctx, conn, func(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.WaitGroup) error {
+		return application.NamespaceLogs(ctx, logChan, wg, cluster, follow, namespace, apps, params)
+	})
+	if err != nil {
+		log.V(1).Error(err, "error occurred after upgrading the websockets connection")
+		return
+	}
+
+	log.Info("streaming completed")
+}
@@ -0,0 +1,135 @@
+package application
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/routes"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RouteCert handles the API endpoint POST /namespaces/:namespace/applications/:app/routecert
+// It configures where the certificate of one of the application's routes comes from, i.e.
+// an existing secret, an uploaded certificate and key, a specific issuer, or the default
+// issuer. Active routes are updated immediately.
+func (hc Controller) RouteCert(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+	username := requestctx.User(ctx)
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := hc.validateNamespace(ctx, cluster, namespace); err != nil {
+		return err
+	}
+
+	appRef := models.NewAppRef(appName, namespace)
+	exists, err := application.Exists(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if !exists {
+		return apierror.AppIsNotKnown(appName)
+	}
+
+	var certRequest models.RouteCertRequest
+	err = c.BindJSON(&certRequest)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	desiredRoutes, err := application.DesiredRoutes(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	// Routes are identified by host and path, i.e. without their options
+	parsed := routes.FromString(certRequest.Route)
+	if parsed.Type == routes.TypeTCP {
+		return apierror.NewBadRequest("tcp routes do not use certificates", certRequest.Route)
+	}
+	route := parsed.String()
+
+	known := false
+	for _, desiredRoute := range desiredRoutes {
+		if routes.FromString(desiredRoute).String() == route {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return apierror.NewBadRequest("route is not used by the application", certRequest.Route)
+	}
+
+	upload := certRequest.Certificate != "" || certRequest.Key != ""
+	if upload && (certRequest.Certificate == "" || certRequest.Key == "") {
+		return apierror.NewBadRequest("certificate and key have to be specified together")
+	}
+
+	choices := 0
+	for _, chosen := range []bool{certRequest.Secret != "", certRequest.Issuer != "", upload} {
+		if chosen {
+			choices++
+		}
+	}
+	if choices > 1 {
+		return apierror.NewBadRequest("at most one of secret, issuer, and certificate/key may be specified")
+	}
+
+	config := models.RouteTLS{
+		Secret: certRequest.Secret,
+		Issuer: certRequest.Issuer,
+	}
+
+	if config.Secret != "" {
+		err := application.ValidateRouteSecret(ctx, cluster, appRef, config.Secret)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return apierror.NewBadRequest("secret not found", config.Secret)
+			}
+			return apierror.BadRequest(err)
+		}
+	}
+
+	// Certificates are requested from cluster issuers only
+	if config.Issuer != "" {
+		exists, err := cluster.ClusterIssuerExists(ctx, config.Issuer)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		if !exists {
+			return apierror.NewBadRequest("cluster issuer not found", config.Issuer)
+		}
+	}
+
+	if upload {
+		secretName, err := application.RouteCertificateStore(ctx, cluster, appRef, route,
+			[]byte(certRequest.Certificate), []byte(certRequest.Key))
+		if err != nil {
+			return apierror.BadRequest(err)
+		}
+		config.Secret = secretName
+	}
+
+	err = application.RouteTLSSet(ctx, cluster, appRef, route, config)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	_, err = application.SyncIngresses(ctx, cluster, appRef, username)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/routes"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RouteMove handles the API endpoint POST /namespaces/:namespace/routemove
+// It moves a route from one application of the namespace to another, in one
+// operation. The active route is re-pointed at the new application without
+// being removed first.
+func (hc Controller) RouteMove(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	username := requestctx.User(ctx)
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := hc.validateNamespace(ctx, cluster, namespace); err != nil {
+		return err
+	}
+
+	var moveRequest models.RouteMoveRequest
+	err = c.BindJSON(&moveRequest)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	if moveRequest.From == moveRequest.To {
+		return apierror.NewBadRequest("source and target application are the same", moveRequest.From)
+	}
+
+	from := models.NewAppRef(moveRequest.From, namespace)
+	to := models.NewAppRef(moveRequest.To, namespace)
+
+	for _, appRef := range []models.AppRef{from, to} {
+		exists, err := application.Exists(ctx, cluster, appRef)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		if !exists {
+			return apierror.AppIsNotKnown(appRef.Name)
+		}
+	}
+
+	toCR, err := application.Get(ctx, cluster, to)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if application.IsInternal(toCR) {
+		return apierror.NewBadRequest("internal applications have no routes", to.Name)
+	}
+
+	// The route is served by the target without interruption only if it is running
+	workload, err := application.NewWorkload(cluster, to).Get(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if workload == nil {
+		return apierror.NewBadRequest("target application is not deployed", to.Name)
+	}
+
+	// Routes are identified by host and path, i.e. without their options. The
+	// route is moved with the options it has.
+	route := routes.FromString(moveRequest.Route).String()
+
+	fromRoutes, err := application.DesiredRoutes(ctx, cluster, from)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	desiredRoute := ""
+	for _, r := range fromRoutes {
+		if routes.FromString(r).String() == route {
+			desiredRoute = r
+			break
+		}
+	}
+	if desiredRoute == "" {
+		return apierror.NewBadRequest("route is not used by the application", moveRequest.Route, from.Name)
+	}
+
+	toRoutes, err := application.DesiredRoutes(ctx, cluster, to)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	for _, r := range toRoutes {
+		if routes.FromString(r).String() == route {
+			return apierror.NewBadRequest("route is already used by the application", moveRequest.Route, to.Name)
+		}
+	}
+
+	err = application.MoveRoute(ctx, cluster, from, to, desiredRoute, username)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
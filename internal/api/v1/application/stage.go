@@ -161,7 +161,9 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err, "failed to generate a uid")
 	}
 
-	environment, err := application.Environment(ctx, cluster, req.App)
+	// The builder sees the environment the app will run with, i.e. including
+	// the namespace defaults
+	environment, err := application.EffectiveEnvironment(ctx, cluster, req.App)
 	if err != nil {
 		return apierror.InternalError(err, "failed to access application runtime environment")
 	}
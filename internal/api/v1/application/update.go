@@ -8,6 +8,8 @@ import (
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/domain"
+	"github.com/epinio/epinio/internal/quota"
 	"github.com/epinio/epinio/internal/services"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -54,11 +56,72 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		return apierror.NewBadRequest("instances param should be integer equal or greater than zero")
 	}
 
+	if updateRequest.Instances != nil {
+		violations, err := quota.CheckApp(ctx, cluster, appRef, *updateRequest.Instances, false)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		if len(violations) > 0 {
+			return apierror.QuotaExceeded(namespace, violations...)
+		}
+	}
+
+	if updateRequest.Internal != nil && *updateRequest.Internal && len(updateRequest.Routes) > 0 {
+		return apierror.NewBadRequest("internal applications have no routes")
+	}
+
+	if len(updateRequest.Routes) > 0 {
+		rejected, err := domain.CheckRoutes(ctx, cluster, namespace, updateRequest.Routes)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		if len(rejected) > 0 {
+			return apierror.RoutesNotOwned(namespace, rejected...)
+		}
+
+		if err := hc.validateRoutes(ctx, cluster, appRef, updateRequest.Routes); err != nil {
+			return err
+		}
+	}
+
+	if updateRequest.Volumes != nil {
+		if err := hc.validateVolumes(ctx, cluster, namespace, updateRequest.Volumes); err != nil {
+			return err
+		}
+	}
+
 	app, err := application.Lookup(ctx, cluster, namespace, appName)
 	if err != nil {
 		return apierror.InternalError(err)
 	}
 
+	// Determine the change of mode between internal and public, if any. Going
+	// internal removes all routes. Going public without routes uses the default
+	// route.
+
+	wasInternal := app.Configuration.Internal != nil && *app.Configuration.Internal
+	internal := wasInternal
+	if updateRequest.Internal != nil {
+		internal = *updateRequest.Internal
+	} else if len(updateRequest.Routes) > 0 {
+		internal = false
+	}
+
+	desiredRoutes := updateRequest.Routes
+	if internal && !wasInternal {
+		desiredRoutes = []string{}
+	}
+	if !internal && wasInternal && len(desiredRoutes) == 0 {
+		route, err := domain.AppDefaultRoute(ctx, cluster, appName, namespace)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		if err := hc.validateRoutes(ctx, cluster, appRef, []string{route}); err != nil {
+			return err
+		}
+		desiredRoutes = []string{route}
+	}
+
 	// TODO: Can we optimize to perform a single restart regardless of what changed ?!
 	// TODO: Should we ?
 
@@ -94,7 +157,7 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 				return apierror.InternalError(err)
 			}
 
-			err = application.NewWorkload(cluster, app.Meta).
+			_, err = application.NewWorkload(cluster, app.Meta).
 				EnvironmentChange(ctx, varNames)
 			if err != nil {
 				return apierror.InternalError(err)
@@ -155,21 +218,46 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		}
 	}
 
-	// Only update the app if routes have been set, otherwise just leave it
-	// as it is.
-	if len(updateRequest.Routes) > 0 {
+	if updateRequest.Volumes != nil {
+		err := application.BoundVolumesSet(ctx, cluster, app.Meta, updateRequest.Volumes, true)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+
+		// Restart workload, if any
+		if app.Workload != nil {
+			err = application.NewWorkload(cluster, app.Meta).
+				BoundVolumesChange(ctx, updateRequest.Volumes)
+			if err != nil {
+				return apierror.InternalError(err)
+			}
+		}
+	}
+
+	if internal != wasInternal {
+		err := application.InternalSet(ctx, cluster, appRef, internal)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
+	// Only update the app if routes have been set, or the mode changed,
+	// otherwise just leave it as it is.
+	if len(updateRequest.Routes) > 0 || internal != wasInternal {
 		client, err := cluster.ClientApp()
 		if err != nil {
 			return apierror.InternalError(err)
 		}
 
 		routes := []string{}
-		for _, d := range updateRequest.Routes {
+		for _, d := range desiredRoutes {
 			routes = append(routes, fmt.Sprintf("%q", d))
 		}
 
+		// Note: `add` replaces existing routes, and also works for internal
+		// applications, whose resource may lack the routes.
 		patch := fmt.Sprintf(`[{
-			"op": "replace",
+			"op": "add",
 			"path": "/spec/routes",
 			"value": [%s] }]`,
 			strings.Join(routes, ","))
@@ -71,6 +71,7 @@ type AppShowResponse struct {
 
 // swagger:route GET /namespaces/{Namespace}/applications/{App}/logs application AppLogs
 // Return logs of the named `App` in the `Namespace` streamed over a websocket.
+// The query parameters restrict the log lines, see models.LogParameters.
 // responses:
 //   200: AppLogsResponse
 
@@ -80,6 +81,22 @@ type AppLogsParam struct {
 	Namespace string
 	// in: path
 	App string
+	// in: query
+	Follow bool
+	// in: query
+	Since string
+	// in: query
+	Tail int64
+	// in: query
+	Include []string
+	// in: query
+	Exclude []string
+	// in: query
+	Instance string
+	// in: query
+	Container string
+	// in: query
+	Timestamps bool
 }
 
 // swagger:response AppLogsResponse
@@ -123,6 +140,7 @@ type AppPortForwardResponse struct{}
 
 // swagger:route GET /namespaces/{Namespace}/staging/{StageID}/logs application StagingLogs
 // Return logs of the named `StageID` in the `Namespace` streamed over a websocket.
+// The query parameters restrict the log lines, see models.LogParameters.
 // responses:
 //   200: StagingLogsResponse
 
@@ -132,11 +150,61 @@ type StagingLogsParam struct {
 	Namespace string
 	// in: path
 	StageID string
+	// in: query
+	Follow bool
+	// in: query
+	Since string
+	// in: query
+	Tail int64
+	// in: query
+	Include []string
+	// in: query
+	Exclude []string
+	// in: query
+	Instance string
+	// in: query
+	Container string
+	// in: query
+	Timestamps bool
 }
 
 // swagger:response StagingLogsResponse
 type StagingLogsResponse struct{}
 
+// swagger:route GET /namespaces/{Namespace}/logs namespace NamespaceLogs
+// Return logs of the applications in the `Namespace` streamed over a websocket, interleaved.
+// The `App` query parameter restricts the logs to the named applications. It can be repeated.
+// The other query parameters restrict the log lines, see models.LogParameters.
+// responses:
+//   200: NamespaceLogsResponse
+
+// swagger:parameters NamespaceLogs
+type NamespaceLogsParam struct {
+	// in: path
+	Namespace string
+	// in: query
+	App []string
+	// in: query
+	Follow bool
+	// in: query
+	Since string
+	// in: query
+	Tail int64
+	// in: query
+	Include []string
+	// in: query
+	Exclude []string
+	// in: query
+	Instance string
+	// in: query
+	Container string
+	// in: query
+	Timestamps bool
+}
+
+// swagger:response NamespaceLogsResponse
+type NamespaceLogsResponse struct{}
+
 // swagger:route GET /namespaces/{Namespace}/staging/{StageID}/complete application StagingComplete
 // Waits for the completion of the staging process identified by `StageID` in the `Namespace`.
 // responses:
@@ -213,6 +281,29 @@ type AppRestartResponse struct {
 	Body models.Response
 }
 
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/routecert application AppRouteCert
+// Configure the certificate of one of the routes of the named `App` in the `Namespace`.
+// The certificate is taken from an existing secret, an uploaded certificate and key,
+// or is issued by a specific or the default issuer.
+// responses:
+//   200: AppRouteCertResponse
+
+// swagger:parameters AppRouteCert
+type AppRouteCertParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Configuration models.RouteCertRequest
+}
+
+// swagger:response AppRouteCertResponse
+type AppRouteCertResponse struct {
+	// in: body
+	Body models.Response
+}
+
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/import-git application AppImportGit
 // Store the named `App` from a Git repo in the `Namespace`.
 // responses:
@@ -315,3 +406,23 @@ type AppRunningResponse struct {
 	// in: body
 	Body models.Response
 }
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/events application AppEvents
+// Return the kube events of the deployment, replicasets, pods, and ingresses of the
+// named `App` in the `Namespace`, and the last container terminations of its instances.
+// responses:
+//   200: AppEventsResponse
+
+// swagger:parameters AppEvents
+type AppEventsParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppEventsResponse
+type AppEventsResponse struct {
+	// in: body
+	Body models.AppEventsResponse
+}
@@ -0,0 +1,72 @@
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+//go:generate swagger generate spec
+
+// swagger:route GET /domains domain Domains
+// Return list of registered domains, and the namespaces they are assigned to.
+// responses:
+//   200: DomainsResponse
+
+// swagger:parameters Domains
+type DomainsParam struct{}
+
+// swagger:response DomainsResponse
+type DomainsResponse struct {
+	// in: body
+	Body models.DomainList
+}
+
+// swagger:route POST /domains domain DomainCreate
+// Register the posted domain, optionally assigned to a namespace.
+// responses:
+//   200: DomainCreateResponse
+
+// swagger:parameters DomainCreate
+type DomainCreateParam struct {
+	// in: body
+	Domain models.Domain
+}
+
+// swagger:response DomainCreateResponse
+type DomainCreateResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route PATCH /domains/{Domain} domain DomainAssign
+// Assign the named `Domain` to a namespace, or release it.
+// responses:
+//   200: DomainAssignResponse
+
+// swagger:parameters DomainAssign
+type DomainAssignParam struct {
+	// in: path
+	Domain string
+	// in: body
+	Assignment models.DomainAssignRequest
+}
+
+// swagger:response DomainAssignResponse
+type DomainAssignResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /domains/{Domain} domain DomainDelete
+// Remove the named `Domain` from the registry.
+// responses:
+//   200: DomainDeleteResponse
+
+// swagger:parameters DomainDelete
+type DomainDeleteParam struct {
+	// in: path
+	Domain string
+}
+
+// swagger:response DomainDeleteResponse
+type DomainDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
@@ -0,0 +1,105 @@
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+//go:generate swagger generate spec
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/drains drain AppDrains
+// Return list of log drains of the `App` in `Namespace`, and the state of the forwarding to them.
+// responses:
+//   200: DrainsResponse
+
+// swagger:parameters AppDrains
+type AppDrainsParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response DrainsResponse
+type DrainsResponse struct {
+	// in: body
+	Body models.DrainList
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/drains drain AppDrainCreate
+// Add a log drain for the posted url to the `App` in `Namespace`.
+// responses:
+//   200: DrainCreateResponse
+
+// swagger:parameters AppDrainCreate
+type AppDrainCreateParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Configuration models.DrainCreateRequest
+}
+
+// swagger:response DrainCreateResponse
+type DrainCreateResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/applications/{App}/drains/{Drain} drain AppDrainDelete
+// Remove the log drain with id `Drain` from the `App` in `Namespace`.
+// responses:
+//   200: DrainDeleteResponse
+
+// swagger:parameters AppDrainDelete
+type AppDrainDeleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Drain string
+}
+
+// swagger:response DrainDeleteResponse
+type DrainDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// Namespace Drains
+
+// swagger:route GET /namespaces/{Namespace}/drains drain NamespaceDrains
+// Return list of log drains of the `Namespace`, and the state of the forwarding to them.
+// responses:
+//   200: DrainsResponse
+
+// swagger:parameters NamespaceDrains
+type NamespaceDrainsParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:route POST /namespaces/{Namespace}/drains drain NamespaceDrainCreate
+// Add a log drain for the posted url to the `Namespace`, covering all its applications.
+// responses:
+//   200: DrainCreateResponse
+
+// swagger:parameters NamespaceDrainCreate
+type NamespaceDrainCreateParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Configuration models.DrainCreateRequest
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/drains/{Drain} drain NamespaceDrainDelete
+// Remove the log drain with id `Drain` from the `Namespace`.
+// responses:
+//   200: DrainDeleteResponse
+
+// swagger:parameters NamespaceDrainDelete
+type NamespaceDrainDeleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	Drain string
+}
@@ -92,3 +92,164 @@ type NamespaceMatchResponse struct {
 type NamespaceMatch0Param struct{}
 
 // response: See NamespaceMatch.
+
+// swagger:route GET /namespaces/{Namespace}/quota namespace NamespaceQuota
+// Return the quota of the named `Namespace`, and the current usage of the limited resources.
+// responses:
+//   200: NamespaceQuotaResponse
+
+// swagger:parameters NamespaceQuota
+type NamespaceQuotaParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response NamespaceQuotaResponse
+type NamespaceQuotaResponse struct {
+	// in: body
+	Body models.NamespaceQuotaResponse
+}
+
+// swagger:route PUT /namespaces/{Namespace}/quota namespace NamespaceQuotaSet
+// Replace the quota of the named `Namespace`.
+// responses:
+//   200: NamespaceQuotaSetResponse
+
+// swagger:parameters NamespaceQuotaSet
+type NamespaceQuotaSetParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Body models.NamespaceQuota
+}
+
+// swagger:response NamespaceQuotaSetResponse
+type NamespaceQuotaSetResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/quota namespace NamespaceQuotaDelete
+// Remove the quota of the named `Namespace`.
+// responses:
+//   200: NamespaceQuotaDeleteResponse
+
+// swagger:parameters NamespaceQuotaDelete
+type NamespaceQuotaDeleteParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response NamespaceQuotaDeleteResponse
+type NamespaceQuotaDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route GET /namespaces/{Namespace}/network namespace NamespaceNetwork
+// Return the network isolation of the named `Namespace`.
+// responses:
+//   200: NamespaceNetworkResponse
+
+// swagger:parameters NamespaceNetwork
+type NamespaceNetworkParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response NamespaceNetworkResponse
+type NamespaceNetworkResponse struct {
+	// in: body
+	Body models.NamespaceNetwork
+}
+
+// swagger:route PUT /namespaces/{Namespace}/network namespace NamespaceNetworkSet
+// Replace the network isolation of the named `Namespace`.
+// responses:
+//   200: NamespaceNetworkSetResponse
+
+// swagger:parameters NamespaceNetworkSet
+type NamespaceNetworkSetParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Body models.NamespaceNetwork
+}
+
+// swagger:response NamespaceNetworkSetResponse
+type NamespaceNetworkSetResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/network namespace NamespaceNetworkDelete
+// Remove the network isolation of the named `Namespace`.
+// responses:
+//   200: NamespaceNetworkDeleteResponse
+
+// swagger:parameters NamespaceNetworkDelete
+type NamespaceNetworkDeleteParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response NamespaceNetworkDeleteResponse
+type NamespaceNetworkDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route GET /namespaces/{Namespace}/environment namespace NamespaceEnvList
+// Return the default environment variable assignments of the named `Namespace`.
+// responses:
+//   200: NamespaceEnvListResponse
+
+// swagger:parameters NamespaceEnvList
+type NamespaceEnvListParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response NamespaceEnvListResponse
+type NamespaceEnvListResponse struct {
+	// in: body
+	Body models.EnvVariableMap
+}
+
+// swagger:route POST /namespaces/{Namespace}/environment namespace NamespaceEnvSet
+// Create/modify the posted default environment variable assignments of the named `Namespace`.
+// responses:
+//   200: NamespaceEnvSetResponse
+
+// swagger:parameters NamespaceEnvSet
+type NamespaceEnvSetParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Body models.EnvVariableMap
+}
+
+// swagger:response NamespaceEnvSetResponse
+type NamespaceEnvSetResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/environment/{Env} namespace NamespaceEnvUnset
+// Remove the default environment variable `Env` from the named `Namespace`.
+// responses:
+//   200: NamespaceEnvUnsetResponse
+
+// swagger:parameters NamespaceEnvUnset
+type NamespaceEnvUnsetParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	Env string
+}
+
+// swagger:response NamespaceEnvUnsetResponse
+type NamespaceEnvUnsetResponse struct {
+	// in: body
+	Body models.Response
+}
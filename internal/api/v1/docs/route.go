@@ -0,0 +1,39 @@
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+//go:generate swagger generate spec
+
+// swagger:route GET /routes route Routes
+// Return list of the active routes of all applications in all namespaces.
+// responses:
+//   200: RoutesResponse
+
+// swagger:parameters Routes
+type RoutesParam struct{}
+
+// swagger:response RoutesResponse
+type RoutesResponse struct {
+	// in: body
+	Body models.RouteList
+}
+
+// swagger:route POST /namespaces/{Namespace}/routemove route AppRouteMove
+// Move a route from one application of the `Namespace` to another, in one operation.
+// The active route is re-pointed at the new application without interruption.
+// responses:
+//   200: AppRouteMoveResponse
+
+// swagger:parameters AppRouteMove
+type AppRouteMoveParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Configuration models.RouteMoveRequest
+}
+
+// swagger:response AppRouteMoveResponse
+type AppRouteMoveResponse struct {
+	// in: body
+	Body models.Response
+}
@@ -43,7 +43,7 @@ type ServiceAppsResponse struct {
 }
 
 // swagger:route GET /namespaces/{Namespace}/services service Services
-// Return list of services in the `Namespace`.
+// Return list of services in the `Namespace`, optionally restricted to those matching the label `Selector`.
 // responses:
 //   200: ServicesResponse
 
@@ -51,6 +51,8 @@ type ServiceAppsResponse struct {
 type ServicesParam struct {
 	// in: path
 	Namespace string
+	// in: query
+	Selector string
 }
 
 // swagger:response ServicesResponse
@@ -140,11 +142,14 @@ type ServiceReplaceResponse struct {
 }
 
 // swagger:route GET /services service AllServices
-// Return list of services in all namespaces.
+// Return list of services in all namespaces, optionally restricted to those matching the label `Selector`.
 // responses:
 //   200: ServicesResponse
 
 // swagger:parameters AllServices
-type ServiceAllServicesParam struct{}
+type ServiceAllServicesParam struct {
+	// in: query
+	Selector string
+}
 
 // response: See Services.
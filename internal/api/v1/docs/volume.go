@@ -0,0 +1,104 @@
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+//go:generate swagger generate spec
+
+// swagger:route GET /namespaces/{Namespace}/volumes volume Volumes
+// Return list of volumes in the `Namespace`, with the applications they are bound to.
+// responses:
+//   200: VolumesResponse
+
+// swagger:parameters Volumes
+type VolumesParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response VolumesResponse
+type VolumesResponse struct {
+	// in: body
+	Body models.VolumeList
+}
+
+// swagger:route POST /namespaces/{Namespace}/volumes volume VolumeCreate
+// Create the posted volume in the `Namespace`.
+// responses:
+//   200: VolumeCreateResponse
+
+// swagger:parameters VolumeCreate
+type VolumeCreateParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	Configuration models.VolumeCreateRequest
+}
+
+// swagger:response VolumeCreateResponse
+type VolumeCreateResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/volumes/{Volume} volume VolumeDelete
+// Delete the named `Volume` in the `Namespace`, and its data. Bound volumes are not deleted.
+// responses:
+//   200: VolumeDeleteResponse
+
+// swagger:parameters VolumeDelete
+type VolumeDeleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	Volume string
+}
+
+// swagger:response VolumeDeleteResponse
+type VolumeDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// Volume Bindings
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/volumebindings volume VolumeBindingCreate
+// Bind the posted volume to `App` in `Namespace`, at the posted mount path.
+// responses:
+//   200: VolumeBindResponse
+
+// swagger:parameters VolumeBindingCreate
+type VolumeBindingCreateParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Body models.VolumeBindRequest
+}
+
+// swagger:response VolumeBindResponse
+type VolumeBindResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/applications/{App}/volumebindings/{Volume} volume VolumeBindingDelete
+// Remove the binding between `App` and `Volume` in `Namespace`. The data of the volume is kept.
+// responses:
+//   200: VolumeUnbindResponse
+
+// swagger:parameters VolumeBindingDelete
+type VolumeBindingDeleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Volume string
+}
+
+// swagger:response VolumeUnbindResponse
+type VolumeUnbindResponse struct {
+	// in: body
+	Body models.Response
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	domains "github.com/epinio/epinio/internal/domain"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Assign handles the API endpoint /domains/:domain (PATCH).
+// It assigns the domain to a namespace, possibly as its default, or releases it.
+func (dc Controller) Assign(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	name := c.Param("domain")
+
+	var request models.DomainAssignRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	known, err := domains.Lookup(ctx, cluster, name)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if known == nil {
+		return apierror.DomainIsNotKnown(name)
+	}
+
+	if err := validateAssignment(c, cluster, name, request.Namespace, request.Default); err != nil {
+		return err
+	}
+
+	err = domains.Assign(ctx, cluster, name, request.Namespace, request.Default)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
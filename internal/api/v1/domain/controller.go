@@ -0,0 +1,6 @@
+// Package domain contains the API handlers to manage the domain registry.
+package domain
+
+// Controller represents all functionality of the API related to domains
+type Controller struct {
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	domains "github.com/epinio/epinio/internal/domain"
+	"github.com/epinio/epinio/internal/namespaces"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Create handles the API endpoint /domains (POST).
+// It registers a new domain, optionally assigning it to a namespace.
+func (dc Controller) Create(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	var request models.Domain
+	err := c.BindJSON(&request)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	if err := domains.ValidateName(request.Name); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	known, err := domains.Lookup(ctx, cluster, request.Name)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if known != nil {
+		return apierror.DomainAlreadyKnown(request.Name)
+	}
+
+	if err := validateAssignment(c, cluster, request.Name, request.Namespace, request.Default); err != nil {
+		return err
+	}
+
+	err = domains.Register(ctx, cluster, request)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.Created(c)
+	return nil
+}
+
+// validateAssignment is a helper for Create and Assign. It checks that the
+// namespace a domain is assigned to exists, that the domain shares no hosts
+// with the domains of other namespaces, and that only assigned domains are
+// made a default.
+func validateAssignment(c *gin.Context, cluster *kubernetes.Cluster, name, namespace string, isDefault bool) apierror.APIErrors {
+	if namespace == "" {
+		if isDefault {
+			return apierror.NewBadRequest("a default domain requires a namespace")
+		}
+		return nil
+	}
+
+	exists, err := namespaces.Exists(c.Request.Context(), cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(namespace)
+	}
+
+	registry, err := domains.List(c.Request.Context(), cluster)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if err := domains.CheckAssignment(registry, name, namespace); err != nil {
+		return apierror.NewBadRequest(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	domains "github.com/epinio/epinio/internal/domain"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Delete handles the API endpoint /domains/:domain (DELETE).
+// It removes the domain from the registry. Existing routes on the domain are
+// kept until their application is updated or deployed again.
+func (dc Controller) Delete(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	name := c.Param("domain")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	known, err := domains.Lookup(ctx, cluster, name)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if known == nil {
+		return apierror.DomainIsNotKnown(name)
+	}
+
+	err = domains.Unregister(ctx, cluster, name)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	domains "github.com/epinio/epinio/internal/domain"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Index handles the API endpoint /domains (GET)
+// It returns a list of all registered domains, and the namespaces they are assigned to.
+func (dc Controller) Index(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	registry, err := domains.List(ctx, cluster)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, registry)
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package drain contains the API handlers to manage the log drains of applications
+// and namespaces.
+package drain
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/namespaces"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Controller represents all functionality of the API related to log drains
+type Controller struct {
+}
+
+// validate is a helper for all handlers. It checks that the namespace exists,
+// and, for a non-empty application name, that the named application exists in it.
+func validate(ctx context.Context, cluster *kubernetes.Cluster, namespace, app string) apierror.APIErrors {
+	exists, err := namespaces.Exists(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(namespace)
+	}
+
+	if app == "" {
+		return nil
+	}
+
+	exists, err = application.Exists(ctx, cluster, models.NewAppRef(app, namespace))
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.AppIsNotKnown(app)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package drain
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/drains"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Create handles the API endpoint /namespaces/:namespace/applications/:app/drains (POST)
+// It adds a log drain for the posted url to the application.
+func (dc Controller) Create(c *gin.Context) apierror.APIErrors {
+	return create(c, c.Param("namespace"), c.Param("app"))
+}
+
+// NamespaceCreate handles the API endpoint /namespaces/:namespace/drains (POST)
+// It adds a log drain for the posted url to the namespace, covering all its applications.
+func (dc Controller) NamespaceCreate(c *gin.Context) apierror.APIErrors {
+	return create(c, c.Param("namespace"), "")
+}
+
+// create is the helper for Create and NamespaceCreate
+func create(c *gin.Context, namespace, app string) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	var createRequest models.DrainCreateRequest
+	err := c.BindJSON(&createRequest)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	if err := drains.Validate(createRequest.URL); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := validate(ctx, cluster, namespace, app); err != nil {
+		return err
+	}
+
+	drainList, err := drains.List(ctx, cluster, namespace, app)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	for _, drain := range drainList {
+		if drain.URL == createRequest.URL {
+			return apierror.DrainAlreadyKnown(createRequest.URL)
+		}
+	}
+
+	err = drains.Add(ctx, cluster, namespace, app, createRequest.URL)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.Created(c)
+	return nil
+}
@@ -0,0 +1,60 @@
+package drain
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/drains"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Delete handles the API endpoint /namespaces/:namespace/applications/:app/drains/:drain (DELETE)
+// It removes the identified log drain from the application.
+func (dc Controller) Delete(c *gin.Context) apierror.APIErrors {
+	return remove(c, c.Param("namespace"), c.Param("app"))
+}
+
+// NamespaceDelete handles the API endpoint /namespaces/:namespace/drains/:drain (DELETE)
+// It removes the identified log drain from the namespace.
+func (dc Controller) NamespaceDelete(c *gin.Context) apierror.APIErrors {
+	return remove(c, c.Param("namespace"), "")
+}
+
+// remove is the helper for Delete and NamespaceDelete
+func remove(c *gin.Context, namespace, app string) apierror.APIErrors {
+	ctx := c.Request.Context()
+	id := c.Param("drain")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := validate(ctx, cluster, namespace, app); err != nil {
+		return err
+	}
+
+	drainList, err := drains.List(ctx, cluster, namespace, app)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	known := false
+	for _, drain := range drainList {
+		if drain.ID == id {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return apierror.DrainIsNotKnown(id)
+	}
+
+	err = drains.Remove(ctx, cluster, namespace, app, id)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
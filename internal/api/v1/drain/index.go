@@ -0,0 +1,43 @@
+package drain
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/drains"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Index handles the API endpoint /namespaces/:namespace/applications/:app/drains (GET)
+// It returns the log drains of the application, and the state of the forwarding to them.
+func (dc Controller) Index(c *gin.Context) apierror.APIErrors {
+	return index(c, c.Param("namespace"), c.Param("app"))
+}
+
+// NamespaceIndex handles the API endpoint /namespaces/:namespace/drains (GET)
+// It returns the log drains of the namespace, and the state of the forwarding to them.
+func (dc Controller) NamespaceIndex(c *gin.Context) apierror.APIErrors {
+	return index(c, c.Param("namespace"), "")
+}
+
+// index is the helper for Index and NamespaceIndex
+func index(c *gin.Context, namespace, app string) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := validate(ctx, cluster, namespace, app); err != nil {
+		return err
+	}
+
+	drainList, err := drains.List(ctx, cluster, namespace, app)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, drainList)
+	return nil
+}
@@ -57,7 +57,7 @@ func (hc Controller) Unset(c *gin.Context) apierror.APIErrors {
 			return apierror.InternalError(err)
 		}
 
-		err = application.NewWorkload(cluster, app.Meta).EnvironmentChange(ctx, varNames)
+		_, err = application.NewWorkload(cluster, app.Meta).EnvironmentChange(ctx, varNames)
 		if err != nil {
 			return apierror.InternalError(err)
 		}
@@ -7,6 +7,7 @@ import (
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/domain"
 	"github.com/epinio/epinio/internal/namespaces"
 	"github.com/epinio/epinio/internal/services"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
@@ -53,6 +54,12 @@ func (oc Controller) Delete(c *gin.Context) apierror.APIErrors {
 		}
 	}
 
+	// Release the domains of the namespace, for use by other namespaces
+	err = domain.ReleaseNamespace(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
 	// Deleting the namespace here. That will automatically delete the application resources.
 	err = namespaces.Delete(ctx, cluster, namespace)
 	if err != nil {
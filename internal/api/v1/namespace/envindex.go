@@ -0,0 +1,38 @@
+package namespace
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/namespaces"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvIndex handles the API endpoint GET /namespaces/:namespace/environment
+// It returns the default environment variables of the namespace
+func (oc Controller) EnvIndex(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := namespaces.Exists(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(namespace)
+	}
+
+	environment, err := namespaces.Environment(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, environment)
+	return nil
+}
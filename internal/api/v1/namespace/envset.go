@@ -0,0 +1,95 @@
+package namespace
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/namespaces"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvSet handles the API endpoint POST /namespaces/:namespace/environment
+// It adds/modifies the default environment variables of the namespace, and
+// restarts the active applications of the namespace to pick them up.
+func (oc Controller) EnvSet(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := namespaces.Exists(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(namespace)
+	}
+
+	var setRequest models.EnvVariableMap
+	err = c.BindJSON(&setRequest)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	err = namespaces.EnvironmentSet(ctx, cluster, namespace, setRequest)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	err = refreshEnvironments(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// refreshEnvironments updates the workloads of all active applications in the
+// namespace for a change of the namespace's default environment. The restart
+// ensures that changed values are picked up even if the set of variables
+// stayed the same.
+func refreshEnvironments(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	apps, err := application.List(ctx, cluster, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range apps {
+		if app.Workload == nil {
+			continue
+		}
+
+		varNames, err := application.EnvironmentNames(ctx, cluster, app.Meta)
+		if err != nil {
+			return err
+		}
+
+		workload := application.NewWorkload(cluster, app.Meta)
+
+		changed, err := workload.EnvironmentChange(ctx, varNames)
+		if err != nil {
+			return err
+		}
+		if changed {
+			// The rollout picks up the new values as well
+			continue
+		}
+
+		// Same variables, new values. Only a restart picks them up.
+		err = workload.Restart(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
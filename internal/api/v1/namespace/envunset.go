@@ -0,0 +1,45 @@
+package namespace
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/namespaces"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvUnset handles the API endpoint DELETE /namespaces/:namespace/environment/:env
+// It removes the named default environment variable from the namespace, and
+// restarts the active applications of the namespace to drop it.
+func (oc Controller) EnvUnset(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	varName := c.Param("env")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := namespaces.Exists(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(namespace)
+	}
+
+	err = namespaces.EnvironmentUnset(ctx, cluster, namespace, varName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	err = refreshEnvironments(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
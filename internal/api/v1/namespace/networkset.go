@@ -0,0 +1,53 @@
+package namespace
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/network"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NetworkSet handles the API endpoint PUT /namespaces/:namespace/network
+// It replaces the network isolation of the specified namespace, i.e. whether it
+// is isolated, and which other namespaces are allowed to reach it.
+func (oc Controller) NetworkSet(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	var request models.NamespaceNetwork
+	err := c.BindJSON(&request)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	if err := network.Validate(request); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	for _, name := range append([]string{namespace}, request.AllowFrom...) {
+		exists, err := namespaces.Exists(ctx, cluster, name)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		if !exists {
+			return apierror.NamespaceIsNotKnown(name)
+		}
+	}
+
+	err = network.Set(ctx, cluster, namespace, request)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
@@ -0,0 +1,52 @@
+package namespace
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/quota"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuotaSet handles the API endpoint PUT /namespaces/:namespace/quota
+// It replaces the quota of the specified namespace. Existing applications and
+// services exceeding the new limits are left untouched, the limits only
+// affect future operations.
+func (oc Controller) QuotaSet(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	var request models.NamespaceQuota
+	err := c.BindJSON(&request)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	if err := quota.Validate(request); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := namespaces.Exists(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(namespace)
+	}
+
+	err = quota.Set(ctx, cluster, namespace, request)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
@@ -0,0 +1,6 @@
+// Package route contains the API handlers to inspect application routes across namespaces.
+package route
+
+// Controller represents all functionality of the API related to routes
+type Controller struct {
+}
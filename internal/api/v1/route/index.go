@@ -0,0 +1,29 @@
+package route
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Index handles the API endpoint /routes (GET)
+// It returns a list of the active routes of all applications in all namespaces,
+// with their owning application and certificate.
+func (rc Controller) Index(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	routes, err := application.AllRoutes(ctx, cluster)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, routes)
+	return nil
+}
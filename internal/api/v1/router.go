@@ -10,11 +10,15 @@ import (
 
 	"github.com/epinio/epinio/helpers/routes"
 	"github.com/epinio/epinio/internal/api/v1/application"
+	"github.com/epinio/epinio/internal/api/v1/domain"
+	"github.com/epinio/epinio/internal/api/v1/drain"
 	"github.com/epinio/epinio/internal/api/v1/env"
 	"github.com/epinio/epinio/internal/api/v1/namespace"
 	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/api/v1/route"
 	"github.com/epinio/epinio/internal/api/v1/service"
 	"github.com/epinio/epinio/internal/api/v1/servicebinding"
+	"github.com/epinio/epinio/internal/api/v1/volume"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/pkg/api/core/v1/errors"
 )
@@ -86,6 +90,9 @@ var Routes = routes.NamedRoutes{
 	"AppRestart":      post("/namespaces/:namespace/applications/:app/restart", errorHandler(application.Controller{}.Restart)),
 	"AppUpdate":       patch("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Update)),
 	"AppRunning":      get("/namespaces/:namespace/applications/:app/running", errorHandler(application.Controller{}.Running)),
+	"AppEvents":       get("/namespaces/:namespace/applications/:app/events", errorHandler(application.Controller{}.Events)), // See events.go
+	"AppRouteCert":    post("/namespaces/:namespace/applications/:app/routecert", errorHandler(application.Controller{}.RouteCert)),
+	"AppRouteMove":    post("/namespaces/:namespace/routemove", errorHandler(application.Controller{}.RouteMove)),
 
 	// See env.go
 	"EnvList": get("/namespaces/:namespace/applications/:app/environment", errorHandler(env.Controller{}.Index)),
@@ -104,12 +111,52 @@ var Routes = routes.NamedRoutes{
 	"ServiceBindingDelete": delete("/namespaces/:namespace/applications/:app/servicebindings/:service",
 		errorHandler(servicebinding.Controller{}.Delete)),
 
+	// Bind and unbind volumes to/from applications, by means of volumebindings in applications
+	"VolumeBindingCreate": post("/namespaces/:namespace/applications/:app/volumebindings",
+		errorHandler(volume.Controller{}.Bind)),
+	"VolumeBindingDelete": delete("/namespaces/:namespace/applications/:app/volumebindings/:volume",
+		errorHandler(volume.Controller{}.Unbind)),
+
+	// List, add and remove the log drains of applications
+	"AppDrains":      get("/namespaces/:namespace/applications/:app/drains", errorHandler(drain.Controller{}.Index)),
+	"AppDrainCreate": post("/namespaces/:namespace/applications/:app/drains", errorHandler(drain.Controller{}.Create)),
+	"AppDrainDelete": delete("/namespaces/:namespace/applications/:app/drains/:drain", errorHandler(drain.Controller{}.Delete)),
+
 	// List, create, show and delete namespaces
 	"Namespaces":      get("/namespaces", errorHandler(namespace.Controller{}.Index)),
 	"NamespaceCreate": post("/namespaces", errorHandler(namespace.Controller{}.Create)),
 	"NamespaceDelete": delete("/namespaces/:namespace", errorHandler(namespace.Controller{}.Delete)),
 	"NamespaceShow":   get("/namespaces/:namespace", errorHandler(namespace.Controller{}.Show)),
 
+	// List, set and unset the default environment of namespaces
+	"NamespaceEnvList":  get("/namespaces/:namespace/environment", errorHandler(namespace.Controller{}.EnvIndex)),
+	"NamespaceEnvSet":   post("/namespaces/:namespace/environment", errorHandler(namespace.Controller{}.EnvSet)),
+	"NamespaceEnvUnset": delete("/namespaces/:namespace/environment/:env", errorHandler(namespace.Controller{}.EnvUnset)),
+
+	// List, add and remove the log drains of namespaces
+	"NamespaceDrains":      get("/namespaces/:namespace/drains", errorHandler(drain.Controller{}.NamespaceIndex)),
+	"NamespaceDrainCreate": post("/namespaces/:namespace/drains", errorHandler(drain.Controller{}.NamespaceCreate)),
+	"NamespaceDrainDelete": delete("/namespaces/:namespace/drains/:drain", errorHandler(drain.Controller{}.NamespaceDelete)),
+
+	// Show, set and remove namespace quotas
+	"NamespaceQuota":       get("/namespaces/:namespace/quota", errorHandler(namespace.Controller{}.Quota)),
+	"NamespaceQuotaSet":    put("/namespaces/:namespace/quota", errorHandler(namespace.Controller{}.QuotaSet)),
+	"NamespaceQuotaDelete": delete("/namespaces/:namespace/quota", errorHandler(namespace.Controller{}.QuotaDelete)),
+
+	// Show, set and remove namespace network isolation
+	"NamespaceNetwork":       get("/namespaces/:namespace/network", errorHandler(namespace.Controller{}.Network)),
+	"NamespaceNetworkSet":    put("/namespaces/:namespace/network", errorHandler(namespace.Controller{}.NetworkSet)),
+	"NamespaceNetworkDelete": delete("/namespaces/:namespace/network", errorHandler(namespace.Controller{}.NetworkDelete)),
+
+	// List, register, assign and unregister domains
+	"Domains":      get("/domains", errorHandler(domain.Controller{}.Index)),
+	"DomainCreate": post("/domains", errorHandler(domain.Controller{}.Create)),
+	"DomainAssign": patch("/domains/:domain", errorHandler(domain.Controller{}.Assign)),
+	"DomainDelete": delete("/domains/:domain", errorHandler(domain.Controller{}.Delete)),
+
+	// List the routes of all applications
+	"Routes": get("/routes", errorHandler(route.Controller{}.Index)),
+
 	// Note, the second registration catches calls with an empty pattern!
 	"NamespacesMatch":  get("/namespacematches/:pattern", errorHandler(namespace.Controller{}.Match)),
 	"NamespacesMatch0": get("/namespacematches", errorHandler(namespace.Controller{}.Match)),
@@ -124,6 +171,11 @@ var Routes = routes.NamedRoutes{
 	"ServiceDelete":  delete("/namespaces/:namespace/services/:service", errorHandler(service.Controller{}.Delete)),
 	"ServiceUpdate":  patch("/namespaces/:namespace/services/:service", errorHandler(service.Controller{}.Update)),
 	"ServiceReplace": put("/namespaces/:namespace/services/:service", errorHandler(service.Controller{}.Replace)),
+
+	// List, create and delete volumes
+	"Volumes":      get("/namespaces/:namespace/volumes", errorHandler(volume.Controller{}.Index)),
+	"VolumeCreate": post("/namespaces/:namespace/volumes", errorHandler(volume.Controller{}.Create)),
+	"VolumeDelete": delete("/namespaces/:namespace/volumes/:volume", errorHandler(volume.Controller{}.Delete)),
 }
 
 var WsRoutes = routes.NamedRoutes{
@@ -131,6 +183,7 @@ var WsRoutes = routes.NamedRoutes{
 	"AppPortForward": get("/namespaces/:namespace/applications/:app/portforward", errorHandler(application.Controller{}.PortForward)),
 	"AppLogs":        get("/namespaces/:namespace/applications/:app/logs", application.Controller{}.Logs),
 	"StagingLogs":    get("/namespaces/:namespace/staging/:stage_id/logs", application.Controller{}.Logs),
+	"NamespaceLogs":  get("/namespaces/:namespace/logs", application.Controller{}.NamespaceLogs),
 }
 
 // Lemon extends the specified router with the methods and urls
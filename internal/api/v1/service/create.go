@@ -5,6 +5,7 @@ import (
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/quota"
 	"github.com/epinio/epinio/internal/services"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -32,6 +33,10 @@ func (sc Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierror.NewBadRequest("Cannot create service without data")
 	}
 
+	if err := services.ValidateLabels(createRequest.Labels); err != nil {
+		return apierror.BadRequest(err)
+	}
+
 	cluster, err := kubernetes.GetCluster(ctx)
 	if err != nil {
 		return apierror.InternalError(err)
@@ -57,8 +62,17 @@ func (sc Controller) Create(c *gin.Context) apierror.APIErrors {
 	}
 	// any error here is `service not found`, and we can continue
 
+	violations, err := quota.CheckService(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if len(violations) > 0 {
+		return apierror.QuotaExceeded(namespace, violations...)
+	}
+
 	// Create the new service. At last.
-	_, err = services.CreateService(ctx, cluster, createRequest.Name, namespace, username, createRequest.Data)
+	_, err = services.CreateService(ctx, cluster, createRequest.Name, namespace, username,
+		createRequest.Data, createRequest.Labels, createRequest.Description)
 	if err != nil {
 		return apierror.InternalError(err)
 	}
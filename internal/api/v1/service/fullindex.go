@@ -8,13 +8,20 @@ import (
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 
 	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // FullIndex handles the API endpoint GET /services
-// It lists all the known applications in all namespaces, with and without workload.
+// It lists all the known services in all namespaces, optionally
+// restricted to those matching the label selector in the query.
 func (hc Controller) FullIndex(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 
+	selector, err := labels.Parse(c.Query("selector"))
+	if err != nil {
+		return apierror.BadRequest(err, "bad label selector")
+	}
+
 	cluster, err := kubernetes.GetCluster(ctx)
 	if err != nil {
 		return apierror.InternalError(err)
@@ -24,6 +31,7 @@ func (hc Controller) FullIndex(c *gin.Context) apierror.APIErrors {
 	if err != nil {
 		return apierror.InternalError(err)
 	}
+	allServices = allServices.Filter(selector)
 
 	appsOf, err := application.BoundAppsNames(ctx, cluster, "")
 	if err != nil {
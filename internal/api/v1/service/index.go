@@ -12,14 +12,21 @@ import (
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // Index handles the API end point /namespaces/:namespace/services
-// It returns a list of all known service instances
+// It returns a list of all known service instances, optionally
+// restricted to those matching the label selector in the query.
 func (sc Controller) Index(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	namespace := c.Param("namespace")
 
+	selector, err := labels.Parse(c.Query("selector"))
+	if err != nil {
+		return apierror.BadRequest(err, "bad label selector")
+	}
+
 	cluster, err := kubernetes.GetCluster(ctx)
 	if err != nil {
 		return apierror.InternalError(err)
@@ -37,6 +44,7 @@ func (sc Controller) Index(c *gin.Context) apierror.APIErrors {
 	if err != nil {
 		return apierror.InternalError(err)
 	}
+	namespaceServices = namespaceServices.Filter(selector)
 
 	appsOf, err := application.BoundAppsNames(ctx, cluster, namespace)
 	if err != nil {
@@ -75,9 +83,11 @@ func makeResponse(ctx context.Context, appsOf map[string][]string, services serv
 				Namespace: service.Namespace(),
 			},
 			Configuration: models.ServiceShowResponse{
-				Username:  service.User(),
-				Details:   serviceDetails,
-				BoundApps: appNames,
+				Username:    service.User(),
+				Details:     serviceDetails,
+				BoundApps:   appNames,
+				Labels:      service.Labels,
+				Description: service.Description,
 			},
 		})
 	}
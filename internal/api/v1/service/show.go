@@ -57,9 +57,11 @@ func (sc Controller) Show(c *gin.Context) apierror.APIErrors {
 			Namespace: service.Namespace(),
 		},
 		Configuration: models.ServiceShowResponse{
-			Username:  service.User(),
-			Details:   serviceDetails,
-			BoundApps: appNames,
+			Username:    service.User(),
+			Details:     serviceDetails,
+			BoundApps:   appNames,
+			Labels:      service.Labels,
+			Description: service.Description,
 		},
 	})
 	return nil
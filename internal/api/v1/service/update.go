@@ -50,6 +50,10 @@ func (sc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		return apierror.BadRequest(err)
 	}
 
+	if err := services.ValidateLabels(updateRequest.SetLabels); err != nil {
+		return apierror.BadRequest(err)
+	}
+
 	// Save changes to resource
 
 	err = services.UpdateService(ctx, cluster, service, updateRequest)
@@ -57,6 +61,13 @@ func (sc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		return apierror.InternalError(err)
 	}
 
+	// Changes to labels and description only do not affect the bound apps.
+
+	if len(updateRequest.Remove) == 0 && len(updateRequest.Set) == 0 {
+		response.OK(c)
+		return nil
+	}
+
 	// Determine bound apps, as candidates for restart.
 
 	appNames, err := application.BoundAppsNamesFor(ctx, cluster, namespace, serviceName)
@@ -0,0 +1,76 @@
+package volume
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/volumes"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Bind handles the API endpoint /namespaces/:namespace/applications/:app/volumebindings (POST)
+// It binds the specified volume to the application, mounted at the requested
+// path. Binding a bound volume moves it to the new path. A running application
+// is restarted.
+func (vc Controller) Bind(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	var bindRequest models.VolumeBindRequest
+	err := c.BindJSON(&bindRequest)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	if bindRequest.Volume == "" {
+		return apierror.NewBadRequest("cannot bind volume without name")
+	}
+	if err := volumes.ValidateMountPath(bindRequest.Path); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := validateNamespace(ctx, cluster, namespace); err != nil {
+		return err
+	}
+
+	appRef := models.NewAppRef(appName, namespace)
+	if err := validateApp(ctx, cluster, appRef); err != nil {
+		return err
+	}
+
+	if err := validateVolume(ctx, cluster, namespace, bindRequest.Volume); err != nil {
+		return err
+	}
+
+	bound, err := application.BoundVolumes(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	for name, path := range bound {
+		if name != bindRequest.Volume && path == bindRequest.Path {
+			return apierror.NewBadRequest("mount path is used by volume '" + name + "'")
+		}
+	}
+
+	err = application.BoundVolumesSet(ctx, cluster, appRef, models.VolumeMounts{
+		bindRequest.Volume: bindRequest.Path,
+	}, false)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := changeWorkload(ctx, cluster, appRef); err != nil {
+		return err
+	}
+
+	response.OK(c)
+	return nil
+}
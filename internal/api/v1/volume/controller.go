@@ -0,0 +1,60 @@
+// Package volume contains the API handlers to manage the persistent volumes of namespaces,
+// and their bindings to applications.
+package volume
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/namespaces"
+	"github.com/epinio/epinio/internal/volumes"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Controller represents all functionality of the API related to volumes
+type Controller struct {
+}
+
+// validateNamespace is a helper for all handlers. It checks that the namespace exists.
+func validateNamespace(ctx context.Context, cluster *kubernetes.Cluster, namespace string) apierror.APIErrors {
+	exists, err := namespaces.Exists(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(namespace)
+	}
+
+	return nil
+}
+
+// validateVolume is a helper for the handlers of a single volume. It checks that
+// the named volume exists in the namespace.
+func validateVolume(ctx context.Context, cluster *kubernetes.Cluster, namespace, name string) apierror.APIErrors {
+	_, err := volumes.Get(ctx, cluster, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierror.VolumeIsNotKnown(name)
+		}
+		return apierror.InternalError(err)
+	}
+
+	return nil
+}
+
+// validateApp is a helper for the handlers of the volume bindings. It checks
+// that the named application exists in the namespace.
+func validateApp(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) apierror.APIErrors {
+	exists, err := application.Exists(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.AppIsNotKnown(appRef.Name)
+	}
+
+	return nil
+}
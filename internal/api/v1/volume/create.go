@@ -0,0 +1,50 @@
+package volume
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/volumes"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Create handles the API endpoint /namespaces/:namespace/volumes (POST)
+// It creates a volume of the requested size and storage class in the namespace.
+func (vc Controller) Create(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	username := requestctx.User(ctx)
+
+	var createRequest models.VolumeCreateRequest
+	err := c.BindJSON(&createRequest)
+	if err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	if err := volumes.Validate(createRequest); err != nil {
+		return apierror.BadRequest(err)
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := validateNamespace(ctx, cluster, namespace); err != nil {
+		return err
+	}
+
+	err = volumes.Create(ctx, cluster, namespace, createRequest, username)
+	if err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return apierror.VolumeAlreadyKnown(createRequest.Name)
+		}
+		return apierror.InternalError(err)
+	}
+
+	response.Created(c)
+	return nil
+}
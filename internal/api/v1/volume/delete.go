@@ -0,0 +1,48 @@
+package volume
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/volumes"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Delete handles the API endpoint /namespaces/:namespace/volumes/:volume (DELETE)
+// It deletes the named volume, and with it its data. A volume bound to
+// applications is not deleted.
+func (vc Controller) Delete(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	volumeName := c.Param("volume")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := validateNamespace(ctx, cluster, namespace); err != nil {
+		return err
+	}
+
+	if err := validateVolume(ctx, cluster, namespace, volumeName); err != nil {
+		return err
+	}
+
+	boundAppNames, err := application.BoundAppsNamesForVolume(ctx, cluster, namespace, volumeName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if len(boundAppNames) > 0 {
+		return apierror.VolumeIsBound(volumeName, boundAppNames...)
+	}
+
+	err = volumes.Delete(ctx, cluster, namespace, volumeName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
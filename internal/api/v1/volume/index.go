@@ -0,0 +1,43 @@
+package volume
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/volumes"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Index handles the API endpoint /namespaces/:namespace/volumes (GET)
+// It returns a list of all the volumes of the namespace, with the applications
+// they are bound to.
+func (vc Controller) Index(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := validateNamespace(ctx, cluster, namespace); err != nil {
+		return err
+	}
+
+	volumeList, err := volumes.List(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	for i, volume := range volumeList {
+		appNames, err := application.BoundAppsNamesForVolume(ctx, cluster, namespace, volume.Name)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+		volumeList[i].BoundApps = appNames
+	}
+
+	response.OKReturn(c, volumeList)
+	return nil
+}
@@ -0,0 +1,81 @@
+package volume
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Unbind handles the API endpoint /namespaces/:namespace/applications/:app/volumebindings/:volume (DELETE)
+// It removes the binding between the specified volume and application. The
+// data of the volume is kept. A running application is restarted.
+func (vc Controller) Unbind(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+	volumeName := c.Param("volume")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := validateNamespace(ctx, cluster, namespace); err != nil {
+		return err
+	}
+
+	appRef := models.NewAppRef(appName, namespace)
+	if err := validateApp(ctx, cluster, appRef); err != nil {
+		return err
+	}
+
+	bound, err := application.BoundVolumes(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if _, ok := bound[volumeName]; !ok {
+		return apierror.VolumeIsNotBound(volumeName)
+	}
+
+	err = application.BoundVolumesUnset(ctx, cluster, appRef, volumeName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := changeWorkload(ctx, cluster, appRef); err != nil {
+		return err
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// changeWorkload is a helper for Bind and Unbind. It imports the changed set of
+// bound volumes into the workload of the application, if it is running.
+func changeWorkload(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) apierror.APIErrors {
+	_, err := application.NewWorkload(cluster, appRef).Deployment(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return apierror.InternalError(err)
+	}
+
+	mounts, err := application.BoundVolumes(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	err = application.NewWorkload(cluster, appRef).BoundVolumesChange(ctx, mounts)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	return nil
+}
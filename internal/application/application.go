@@ -35,8 +35,9 @@ const EpinioApplicationAreaLabel = "epinio.suse.org/area"
 
 // Create generates a new kube app resource in the namespace of the
 // namespace. Note that this is the passive resource holding the
-// app's configuration. It is not the active workload
-func Create(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, username string, routes []string) error {
+// app's configuration. It is not the active workload. An internal app
+// is marked as such, and has no routes.
+func Create(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, username string, routes []string, internal bool) error {
 	client, err := cluster.ClientApp()
 	if err != nil {
 		return err
@@ -58,6 +59,9 @@ func Create(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef,
 	us.SetAPIVersion("application.epinio.io/v1")
 	us.SetKind("App")
 	us.SetName(app.Name)
+	if internal {
+		us.SetAnnotations(map[string]string{InternalAnnotation: "true"})
+	}
 
 	_, err = client.Namespace(app.Namespace).Create(ctx, us, metav1.CreateOptions{})
 	return err
@@ -344,8 +348,8 @@ func Unstage(ctx context.Context, cluster *kubernetes.Cluster, appRef models.App
 // to close the logChan when done.
 // When stageID is an empty string, no staging logs are returned. If it is set,
 // then only logs from that staging process are returned.
-func Logs(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.WaitGroup, cluster *kubernetes.Cluster, follow bool, app, stageID, namespace string) error {
-	logger := requestctx.Logger(ctx).WithName("logs-backend").V(2)
+// The parameters restrict the returned log lines further.
+func Logs(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.WaitGroup, cluster *kubernetes.Cluster, follow bool, app, stageID, namespace string, params LogParameters) error {
 	selector := labels.NewSelector()
 
 	var selectors [][]string
@@ -371,23 +375,60 @@ func Logs(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.Wa
 		selector = selector.Add(*req)
 	}
 
+	// Staging logs are returned in the order of the staging steps
+	return tailLogs(ctx, logChan, wg, cluster, follow, selector, stageID != "", params)
+}
+
+// NamespaceLogs method writes the log lines of the named applications of the
+// namespace to the specified logChan, interleaved. No names means all
+// applications of the namespace. Otherwise it behaves like Logs, above.
+func NamespaceLogs(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.WaitGroup, cluster *kubernetes.Cluster, follow bool, namespace string, apps []string, params LogParameters) error {
+	selector := labels.NewSelector()
+
+	requirements := [][]string{
+		{"app.kubernetes.io/component", "application"},
+		{"app.kubernetes.io/part-of", namespace},
+	}
+	for _, req := range requirements {
+		req, err := labels.NewRequirement(req[0], selection.Equals, []string{req[1]})
+		if err != nil {
+			return err
+		}
+		selector = selector.Add(*req)
+	}
+
+	if len(apps) > 0 {
+		req, err := labels.NewRequirement("app.kubernetes.io/name", selection.In, apps)
+		if err != nil {
+			return err
+		}
+		selector = selector.Add(*req)
+	}
+
+	return tailLogs(ctx, logChan, wg, cluster, follow, selector, false, params)
+}
+
+// tailLogs is a helper for Logs and NamespaceLogs. It writes the log lines of
+// the containers of the selected pods to the logChan.
+func tailLogs(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.WaitGroup, cluster *kubernetes.Cluster,
+	follow bool, selector labels.Selector, ordered bool, params LogParameters) error {
+
+	logger := requestctx.Logger(ctx).WithName("logs-backend").V(2)
+
 	config := &tailer.Config{
-		ContainerQuery:        regexp.MustCompile(".*"),
+		ContainerQuery:        params.ContainerQuery,
 		ExcludeContainerQuery: regexp.MustCompile("linkerd-(proxy|init)"),
 		ContainerState:        "running",
-		Exclude:               nil,
-		Include:               nil,
-		Timestamps:            false,
-		Since:                 duration.LogHistory(),
+		Exclude:               params.Exclude,
+		Include:               params.Include,
+		Timestamps:            params.Timestamps,
+		Since:                 params.Since,
 		AllNamespaces:         true,
 		LabelSelector:         selector,
-		TailLines:             nil,
+		TailLines:             params.TailLines,
 		Namespace:             "",
-		PodQuery:              regexp.MustCompile(".*"),
-	}
-
-	if stageID != "" {
-		config.Ordered = true
+		PodQuery:              params.PodQuery,
+		Ordered:               ordered,
 	}
 
 	if follow {
@@ -444,17 +485,33 @@ func fetch(ctx context.Context, cluster *kubernetes.Cluster, app *models.App) er
 		return err
 	}
 
+	volumes, err := BoundVolumes(ctx, cluster, app.Meta)
+	if err != nil {
+		return err
+	}
+
 	stageID, err := StageID(applicationCR)
 	if err != nil {
 		return err
 	}
 
+	builderImage, _, err := unstructured.NestedString(applicationCR.Object, "spec", "builderimage")
+	if err != nil {
+		return apierror.InternalError(err, "builderimage should be a string!")
+	}
+
 	app.Configuration.Instances = &instances
 	app.Configuration.Services = services
+	app.Configuration.Volumes = volumes
 	app.Configuration.Environment = environment
 	app.Configuration.Routes = desiredRoutes
+	if IsInternal(applicationCR) {
+		internal := true
+		app.Configuration.Internal = &internal
+	}
 	app.Origin = origin
 	app.StageID = stageID
+	app.BuilderImage = builderImage
 
 	// Check if app is active, and if yes, fill the associated parts.
 	// May have to straighten the workload structure a bit further.
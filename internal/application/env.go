@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/namespaces"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -42,6 +43,23 @@ func Environment(ctx context.Context, cluster *kubernetes.Cluster, appRef models
 	return result, nil
 }
 
+// EffectiveEnvironment returns the environment variables and their values the
+// named application runs with. These are the variables set on the application
+// by users, merged over the default variables of the application's namespace.
+func EffectiveEnvironment(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (models.EnvVariableMap, error) {
+	environment, err := Environment(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := namespaces.Environment(ctx, cluster, appRef.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return defaults.Overlay(environment), nil
+}
+
 // EnvironmentSet adds or modifies the specified environment variable
 // for the named application. When the function returns the variable
 // will have the specified value. If the application is active the
@@ -0,0 +1,204 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Events returns the kube events of the resources of the application, i.e. its
+// deployment, replicasets, pods, and ingresses, ordered by the time they were
+// last seen. Events of pods which are gone already are included, as long as
+// their replicaset exists.
+func Events(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (models.AppEventList, error) {
+	selector := labels.Set(map[string]string{
+		"app.kubernetes.io/component": "application",
+		"app.kubernetes.io/name":      appRef.Name,
+		"app.kubernetes.io/part-of":   appRef.Namespace,
+	}).String()
+
+	objects := eventObjects{
+		"Deployment": {appRef.Name: struct{}{}},
+		"ReplicaSet": {},
+		"Pod":        {},
+		"Ingress":    {},
+	}
+
+	replicaSets, err := cluster.Kubectl.AppsV1().ReplicaSets(appRef.Namespace).List(ctx,
+		metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	for _, rs := range replicaSets.Items {
+		objects["ReplicaSet"][rs.Name] = struct{}{}
+	}
+
+	pods, err := NewWorkload(cluster, appRef).Pods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		objects["Pod"][pod.Name] = struct{}{}
+	}
+
+	ingresses, err := ingressListForApp(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+	for _, ingress := range ingresses.Items {
+		objects["Ingress"][ingress.Name] = struct{}{}
+	}
+
+	events, err := cluster.Kubectl.CoreV1().Events(appRef.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects.filter(events.Items), nil
+}
+
+// Diagnostics returns the state of the instances of the application, and the
+// last terminations of their application containers, ordered by name.
+func Diagnostics(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) ([]models.InstanceDiagnostics, error) {
+	pods, err := NewWorkload(cluster, appRef).Pods(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.InstanceDiagnostics{}
+	for _, pod := range pods.Items {
+		result = append(result, instanceDiagnostics(pod, appRef.Name))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// eventObjects maps kinds of kube resources to the names of the resources
+// whose events are wanted.
+type eventObjects map[string]map[string]struct{}
+
+// filter returns the events about the objects, ordered by the time they were
+// last seen. Pod events are also selected by the name prefix of the known
+// replicasets, to keep the events of deleted pods.
+func (o eventObjects) filter(events []corev1.Event) models.AppEventList {
+	matches := []corev1.Event{}
+	for _, event := range events {
+		if o.match(event.InvolvedObject) {
+			matches = append(matches, event)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return eventTime(matches[i]).Before(eventTime(matches[j]))
+	})
+
+	result := models.AppEventList{}
+	for _, event := range matches {
+		result = append(result, models.AppEvent{
+			Type:      event.Type,
+			Reason:    event.Reason,
+			Message:   event.Message,
+			Kind:      event.InvolvedObject.Kind,
+			Object:    event.InvolvedObject.Name,
+			Count:     event.Count,
+			FirstSeen: formatTime(event.FirstTimestamp.Time),
+			LastSeen:  formatTime(eventTime(event)),
+		})
+	}
+
+	return result
+}
+
+// match returns true if the object is one of the selected objects.
+func (o eventObjects) match(object corev1.ObjectReference) bool {
+	names, ok := o[object.Kind]
+	if !ok {
+		return false
+	}
+	if _, ok := names[object.Name]; ok {
+		return true
+	}
+
+	if object.Kind == "Pod" {
+		for rs := range o["ReplicaSet"] {
+			if strings.HasPrefix(object.Name, rs+"-") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// instanceDiagnostics returns the state of the pod, and of its container
+// running the application.
+func instanceDiagnostics(pod corev1.Pod, containerName string) models.InstanceDiagnostics {
+	result := models.InstanceDiagnostics{
+		Name:  pod.Name,
+		Phase: string(pod.Status.Phase),
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName {
+			continue
+		}
+
+		result.Ready = status.Ready
+		result.Restarts = status.RestartCount
+		if status.State.Waiting != nil {
+			result.Waiting = status.State.Waiting.Reason
+		}
+
+		// A container terminated for good reports in the current state,
+		// a restarted one in the last state.
+		terminated := status.LastTerminationState.Terminated
+		if status.State.Terminated != nil {
+			terminated = status.State.Terminated
+		}
+		if terminated != nil {
+			result.LastTermination = &models.ContainerTermination{
+				Reason:     terminated.Reason,
+				ExitCode:   terminated.ExitCode,
+				Signal:     terminated.Signal,
+				Message:    terminated.Message,
+				StartedAt:  formatTime(terminated.StartedAt.Time),
+				FinishedAt: formatTime(terminated.FinishedAt.Time),
+				OOMKilled:  terminated.Reason == "OOMKilled",
+			}
+		}
+	}
+
+	return result
+}
+
+// eventTime returns the time the event was last seen. Events reported through
+// the events.k8s.io api may only carry the event time.
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.FirstTimestamp.Time
+}
+
+// formatTime returns the time in RFC3339 format, and the zero time as empty string.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Events", func() {
+	at := func(minute int) metav1.Time {
+		return metav1.NewTime(time.Date(2022, 3, 4, 5, minute, 0, 0, time.UTC))
+	}
+
+	event := func(kind, name, reason string, minute int) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name},
+			Type:           "Warning",
+			Reason:         reason,
+			Count:          1,
+			FirstTimestamp: at(minute),
+			LastTimestamp:  at(minute),
+		}
+	}
+
+	Describe("filter", func() {
+		objects := eventObjects{
+			"Deployment": {"shop": {}},
+			"ReplicaSet": {"shop-5d8b9c": {}},
+			"Pod":        {"shop-5d8b9c-x7k2p": {}},
+			"Ingress":    {},
+		}
+
+		It("returns the events of the objects, ordered by time", func() {
+			events := objects.filter([]corev1.Event{
+				event("Pod", "shop-5d8b9c-x7k2p", "BackOff", 3),
+				event("Deployment", "shop", "ScalingReplicaSet", 1),
+				event("Deployment", "cart", "ScalingReplicaSet", 2),
+				event("Service", "shop", "Created", 2),
+			})
+
+			Expect(events).To(Equal(models.AppEventList{
+				{Type: "Warning", Reason: "ScalingReplicaSet", Kind: "Deployment", Object: "shop", Count: 1,
+					FirstSeen: "2022-03-04T05:01:00Z", LastSeen: "2022-03-04T05:01:00Z"},
+				{Type: "Warning", Reason: "BackOff", Kind: "Pod", Object: "shop-5d8b9c-x7k2p", Count: 1,
+					FirstSeen: "2022-03-04T05:03:00Z", LastSeen: "2022-03-04T05:03:00Z"},
+			}))
+		})
+
+		It("keeps the events of deleted pods of known replicasets", func() {
+			events := objects.filter([]corev1.Event{
+				event("Pod", "shop-5d8b9c-gone1", "Killing", 1),
+				event("Pod", "shopping-1234-abcde", "Killing", 1),
+			})
+
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Object).To(Equal("shop-5d8b9c-gone1"))
+		})
+	})
+
+	Describe("instanceDiagnostics", func() {
+		pod := func(status corev1.ContainerStatus) corev1.Pod {
+			return corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop-5d8b9c-x7k2p"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "linkerd-proxy", Ready: true},
+						status,
+					},
+				},
+			}
+		}
+
+		It("reports the last termination of a crashlooping container", func() {
+			diagnostics := instanceDiagnostics(pod(corev1.ContainerStatus{
+				Name:         "shop",
+				RestartCount: 4,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Reason:     "OOMKilled",
+						ExitCode:   137,
+						StartedAt:  at(1),
+						FinishedAt: at(2),
+					},
+				},
+			}), "shop")
+
+			Expect(diagnostics).To(Equal(models.InstanceDiagnostics{
+				Name:     "shop-5d8b9c-x7k2p",
+				Phase:    "Running",
+				Restarts: 4,
+				Waiting:  "CrashLoopBackOff",
+				LastTermination: &models.ContainerTermination{
+					Reason:     "OOMKilled",
+					ExitCode:   137,
+					StartedAt:  "2022-03-04T05:01:00Z",
+					FinishedAt: "2022-03-04T05:02:00Z",
+					OOMKilled:  true,
+				},
+			}))
+		})
+
+		It("reports a healthy container without termination", func() {
+			diagnostics := instanceDiagnostics(pod(corev1.ContainerStatus{
+				Name:  "shop",
+				Ready: true,
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{StartedAt: at(1)},
+				},
+			}), "shop")
+
+			Expect(diagnostics.Ready).To(BeTrue())
+			Expect(diagnostics.Waiting).To(BeEmpty())
+			Expect(diagnostics.LastTermination).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	ingressprovider "github.com/epinio/epinio/internal/ingress"
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// syncGRPCService is a helper for SyncIngresses. It ensures that the application
+// has the h2c service backing its gRPC routes if, and only if, it has such routes.
+func syncGRPCService(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef,
+	owner metav1.OwnerReference, username string, grpc bool) error {
+
+	log := requestctx.Logger(ctx)
+	services := cluster.Kubectl.CoreV1().Services(appRef.Namespace)
+	name := names.GRPCServiceName(appRef.Name)
+
+	if !grpc {
+		err := services.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			log.Info("deleted app grpc service", "name", name)
+		}
+		return nil
+	}
+
+	service := newGRPCService(appRef, username)
+	service.SetOwnerReferences([]metav1.OwnerReference{owner})
+
+	_, err := services.Create(ctx, service, metav1.CreateOptions{})
+	if err == nil {
+		log.Info("created app grpc service", "name", name)
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing, err := services.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	service.ResourceVersion = existing.ResourceVersion
+	service.Spec.ClusterIP = existing.Spec.ClusterIP
+	_, err = services.Update(ctx, service, metav1.UpdateOptions{})
+	return err
+}
+
+// hasGRPCRoutes returns true if any of the routes is a gRPC route.
+func hasGRPCRoutes(desiredRoutes []string) bool {
+	for _, desiredRoute := range desiredRoutes {
+		if routes.FromString(desiredRoute).Type == routes.TypeGRPC {
+			return true
+		}
+	}
+	return false
+}
+
+// newGRPCService returns the service backing the gRPC routes of the application. It
+// talks h2c to the application.
+func newGRPCService(appRef models.AppRef, username string) *v1.Service {
+	h2c := "kubernetes.io/h2c"
+
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        names.GRPCServiceName(appRef.Name),
+			Namespace:   appRef.Namespace,
+			Annotations: ingressprovider.Current().ServiceAnnotations(routes.TypeGRPC),
+			Labels: map[string]string{
+				"app.kubernetes.io/component":  "application",
+				"app.kubernetes.io/managed-by": "epinio",
+				"app.kubernetes.io/name":       appRef.Name,
+				"app.kubernetes.io/part-of":    appRef.Namespace,
+				"app.kubernetes.io/created-by": username,
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{
+					Port:        8080,
+					Protocol:    v1.ProtocolTCP,
+					TargetPort:  intstr.IntOrString{IntVal: 8080},
+					AppProtocol: &h2c,
+				},
+			},
+			Selector: map[string]string{
+				"app.kubernetes.io/component": "application",
+				"app.kubernetes.io/name":      appRef.Name,
+			},
+			Type: v1.ServiceTypeClusterIP,
+		},
+	}
+}
@@ -0,0 +1,27 @@
+package application
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	v1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("gRPC routes", func() {
+	It("requires the gRPC service for gRPC routes only", func() {
+		Expect(hasGRPCRoutes([]string{"web.example.com", "tcp://1883"})).To(BeFalse())
+		Expect(hasGRPCRoutes([]string{"web.example.com", "grpc://api.example.com"})).To(BeTrue())
+		Expect(hasGRPCRoutes(nil)).To(BeFalse())
+	})
+
+	It("talks h2c to the application", func() {
+		service := newGRPCService(models.NewAppRef("api", "workspace"), "admin")
+
+		Expect(service.Namespace).To(Equal("workspace"))
+		Expect(service.Spec.Type).To(Equal(v1.ServiceTypeClusterIP))
+		Expect(service.Spec.Ports).To(HaveLen(1))
+		Expect(*service.Spec.Ports[0].AppProtocol).To(Equal("kubernetes.io/h2c"))
+		Expect(service.Spec.Selector).To(HaveKeyWithValue("app.kubernetes.io/name", "api"))
+	})
+})
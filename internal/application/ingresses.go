@@ -7,7 +7,10 @@ import (
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/auth"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/domain"
+	ingressprovider "github.com/epinio/epinio/internal/ingress"
 	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/internal/network"
 	"github.com/epinio/epinio/internal/routes"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -32,6 +35,17 @@ func DesiredRoutes(ctx context.Context, cluster *kubernetes.Cluster, appRef mode
 		return []string{}, apierror.InternalError(err, "failed to get the application resource")
 	}
 
+	return desiredRoutesOf(applicationCR)
+}
+
+// desiredRoutesOf is a helper for DesiredRoutes and SyncIngresses. It returns
+// the routes stored in the application resource. Internal applications have
+// none, and their resource may omit the empty list.
+func desiredRoutesOf(applicationCR *unstructured.Unstructured) ([]string, error) {
+	if IsInternal(applicationCR) {
+		return []string{}, nil
+	}
+
 	desiredRoutes, found, err := unstructured.NestedStringSlice(applicationCR.Object, "spec", "routes")
 
 	if !found {
@@ -63,12 +77,60 @@ func ListRoutes(ctx context.Context, cluster *kubernetes.Cluster, appRef models.
 		result = append(result, route.String())
 	}
 
+	serviceList, err := tcpServiceList(ctx, cluster, appRef.Namespace, appRef.Name)
+	if err != nil {
+		return result, err
+	}
+	for _, service := range serviceList.Items {
+		result = append(result, tcpRouteFromService(service).String())
+	}
+
+	return result, nil
+}
+
+// RouteEndpoints returns the connection endpoints of the (currently active) gRPC
+// and TCP routes of the given application. HTTP routes are their own endpoint
+// and are not listed.
+func RouteEndpoints(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) ([]models.RouteEndpoint, error) {
+	ingressList, err := ingressListForApp(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.RouteEndpoint{}
+	for _, ingress := range ingressList.Items {
+		route, err := routes.FromIngress(ingress)
+		if err != nil {
+			return result, err
+		}
+		if route.Type != routes.TypeGRPC {
+			continue
+		}
+
+		result = append(result, models.RouteEndpoint{
+			Route:    route.String(),
+			Endpoint: route.Domain + ":443",
+		})
+	}
+
+	serviceList, err := tcpServiceList(ctx, cluster, appRef.Namespace, appRef.Name)
+	if err != nil {
+		return result, err
+	}
+	for _, service := range serviceList.Items {
+		result = append(result, models.RouteEndpoint{
+			Route:    tcpRouteFromService(service).String(),
+			Endpoint: tcpEndpoint(service),
+		})
+	}
+
 	return result, nil
 }
 
 // SyncIngresses ensures that each route in the Application CRD "Routes" field
-// has a respective Ingress resource. It also ensures that no other Ingresses
-// exist for that application (e.g. for routes that have been removed).
+// has a respective Ingress resource, or Service for TCP routes. It also ensures
+// that no other Ingresses exist for that application (e.g. for routes that have
+// been removed).
 // Returns the current list of routes (after syncing) and error if something goes wrong.
 func SyncIngresses(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, username string) ([]string, error) {
 	// Note: While the code below is very similar to `DesiredRoutes` (DR) it is not
@@ -89,13 +151,19 @@ func SyncIngresses(ctx context.Context, cluster *kubernetes.Cluster, appRef mode
 		UID:        applicationCR.GetUID(),
 	}
 
-	desiredRoutes, found, err := unstructured.NestedStringSlice(applicationCR.Object, "spec", "routes")
-	if !found {
-		return []string{}, errors.New("couldn't parse the Application for Routes")
+	desiredRoutes, err := desiredRoutesOf(applicationCR)
+	if err != nil {
+		return []string{}, err
 	}
+
+	// Reject routes on domains the namespace does not own, before touching anything
+	rejected, err := domain.CheckRoutes(ctx, cluster, appRef.Namespace, desiredRoutes)
 	if err != nil {
 		return []string{}, err
 	}
+	if len(rejected) > 0 {
+		return []string{}, apierror.RoutesNotOwned(appRef.Namespace, rejected...)
+	}
 
 	ingressList, err := ingressListForApp(ctx, cluster, appRef)
 	if err != nil {
@@ -112,20 +180,82 @@ func SyncIngresses(ctx context.Context, cluster *kubernetes.Cluster, appRef mode
 		existingIngresses[route.String()] = ingress
 	}
 
-	// Ensure desired routes
+	routeTLS, err := RouteTLS(ctx, cluster, appRef)
+	if err != nil {
+		return []string{}, err
+	}
+
+	// Ensure desired routes. Routes are identified by host and path, i.e. without
+	// their options.
 	log := requestctx.Logger(ctx)
+	provider := ingressprovider.Current()
 	desiredRoutesMap := map[string]bool{}
+	tcpRoutes := []routes.Route{}
 	for _, desiredRoute := range desiredRoutes {
-		desiredRoutesMap[desiredRoute] = true
-		if _, ok := existingIngresses[desiredRoute]; ok {
+		route := routes.FromString(desiredRoute)
+		if route.Type == routes.TypeTCP {
+			// Not handled by the ingress controller
+			tcpRoutes = append(tcpRoutes, route)
+			continue
+		}
+		desiredRoutesMap[route.String()] = true
+		tlsConfig := routeTLS[route.String()]
+
+		if ingress, ok := existingIngresses[route.String()]; ok {
+			tlsChanged := ingress.Annotations[RouteTLSAnnotation] != tlsConfig.String()
+			optionsChanged := ingress.Annotations[routes.OptionsAnnotation] != route.Options.String()
+			if !tlsChanged && !optionsChanged {
+				continue
+			}
+			log.Info("updating app ingress", "namespace", appRef.Namespace, "app", appRef.Name, "route", desiredRoute, "tls", tlsConfig.String())
+
+			// Regenerate the annotations, for the new options
+			desiredIngress := route.ToIngress(ingress.Name)
+			completeIngress(&desiredIngress, appRef, route, username)
+			ingress.Annotations = desiredIngress.Annotations
+
+			applyRouteTLS(&ingress, tlsConfig)
+			updatedIngress, err := cluster.Kubectl.NetworkingV1().Ingresses(appRef.Namespace).Update(ctx, &ingress, metav1.UpdateOptions{})
+			if err != nil {
+				return []string{}, errors.Wrap(err, "updating an application Ingress")
+			}
+
+			if optionsChanged {
+				err = provider.Sync(ctx, cluster, updatedIngress, route)
+				if err != nil {
+					return []string{}, errors.Wrap(err, "exposing an application Ingress")
+				}
+			}
+
+			if tlsChanged {
+				// Replace the certificate of the previous configuration, if any
+				err = auth.DeleteCertificate(ctx, cluster, appRef.Namespace, updatedIngress.Name)
+				if err != nil {
+					return []string{}, err
+				}
+				if tlsConfig.Secret != "" {
+					// No issuer anymore, its secret is orphaned
+					err = deleteIssuedSecret(ctx, cluster, appRef.Namespace, updatedIngress.Name)
+					if err != nil {
+						return []string{}, err
+					}
+				}
+				err = syncRouteCertificate(ctx, cluster, appRef, updatedIngress, route, tlsConfig)
+				if err != nil {
+					return []string{}, err
+				}
+			}
 			continue
 		}
 		log.Info("creating app ingress", "namespace", appRef.Namespace, "app", appRef.Name, "", desiredRoute)
 
-		route := routes.FromString(desiredRoute)
 		ingressName := names.IngressName(fmt.Sprintf("%s-%s", appRef.Name, route))
+		if route.Type == routes.TypeGRPC {
+			ingressName = names.IngressName(fmt.Sprintf("%s-grpc-%s%s", appRef.Name, route.Domain, route.Path))
+		}
 		ingress := route.ToIngress(ingressName)
-		completeIngress(&ingress, appRef, username) // Add more fields, annotations, etc
+		completeIngress(&ingress, appRef, route, username) // Add more fields, annotations, etc
+		applyRouteTLS(&ingress, tlsConfig)
 
 		log.Info("app ingress", "name", ingress.ObjectMeta.Name)
 
@@ -140,22 +270,14 @@ func SyncIngresses(ctx context.Context, cluster *kubernetes.Cluster, appRef mode
 					return []string{}, errors.Wrap(err, "creating an application Ingress")
 				}
 
-				// Create the certificate for this Ingress (Ignores it if it exists)
-				cert := auth.CertParam{
-					Name:      createdIngress.Name,
-					Namespace: appRef.Namespace,
-					Issuer:    viper.GetString("tls-issuer"),
-					Domain:    route.Domain,
-					Labels:    map[string]string{"app.kubernetes.io/name": appRef.Name},
-				}
-				certOwner := &metav1.OwnerReference{
-					APIVersion: "networking.k8s.io/v1",
-					Kind:       "Ingress",
-					Name:       createdIngress.Name,
-					UID:        createdIngress.UID,
+				// Create the provider specific resources for this Ingress
+				err = provider.Sync(ctx, cluster, createdIngress, route)
+				if err != nil {
+					return []string{}, errors.Wrap(err, "exposing an application Ingress")
 				}
-				log.Info("app cert", "route", cert.Domain, "issuer", cert.Issuer)
-				err = auth.CreateCertificate(ctx, cluster, cert, certOwner)
+
+				// Create the certificate for this Ingress (Ignores it if it exists)
+				err = syncRouteCertificate(ctx, cluster, appRef, createdIngress, route, tlsConfig)
 				if err != nil {
 					return []string{}, err
 				}
@@ -165,8 +287,29 @@ func SyncIngresses(ctx context.Context, cluster *kubernetes.Cluster, appRef mode
 		}
 	}
 
+	err = syncTCPServices(ctx, cluster, appRef, owner, username, tcpRoutes)
+	if err != nil {
+		return []string{}, errors.Wrap(err, "exposing application TCP routes")
+	}
+
+	err = network.SyncServicePolicies(ctx, cluster, appRef.Namespace)
+	if err != nil {
+		return []string{}, errors.Wrap(err, "admitting the clients of application TCP routes")
+	}
+
+	err = syncGRPCService(ctx, cluster, appRef, owner, username, hasGRPCRoutes(desiredRoutes))
+	if err != nil {
+		return []string{}, errors.Wrap(err, "exposing application gRPC routes")
+	}
+
+	// Cleanup the certificate configuration of removed routes
+	err = routeTLSPrune(ctx, cluster, appRef, desiredRoutesMap)
+	if err != nil {
+		return []string{}, errors.Wrap(err, "pruning route certificate configuration")
+	}
+
 	// Cleanup removed ingresses. Automatically deletes certificates using
-	// owner references.
+	// owner references. The secrets issued for them are deleted explicitly.
 	for route, ingress := range existingIngresses {
 		if _, ok := desiredRoutesMap[route]; !ok {
 			deletionPropagation := metav1.DeletePropagationBackground
@@ -176,23 +319,65 @@ func SyncIngresses(ctx context.Context, cluster *kubernetes.Cluster, appRef mode
 				return []string{}, err
 			}
 			log.Info("deleted ingress", ingress.Name)
+
+			if err := deleteIssuedSecret(ctx, cluster, appRef.Namespace, ingress.Name); err != nil {
+				return []string{}, err
+			}
 		}
 	}
 
 	return desiredRoutes, nil
 }
 
+// syncRouteCertificate is a helper for SyncIngresses. It creates the
+// cert-manager certificate for the ingress of a route, using the issuer of the
+// route's configuration, or the server-wide default. Nothing is done for routes
+// using a user provided secret.
+func syncRouteCertificate(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef,
+	ingress *networkingv1.Ingress, route routes.Route, config models.RouteTLS) error {
+
+	if config.Secret != "" {
+		return nil
+	}
+
+	issuer := config.Issuer
+	if issuer == "" {
+		issuer = viper.GetString("tls-issuer")
+	}
+
+	cert := auth.CertParam{
+		Name:      ingress.Name,
+		Namespace: appRef.Namespace,
+		Issuer:    issuer,
+		Domain:    route.Domain,
+		Labels:    map[string]string{"app.kubernetes.io/name": appRef.Name},
+	}
+	certOwner := &metav1.OwnerReference{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+		Name:       ingress.Name,
+		UID:        ingress.UID,
+	}
+
+	requestctx.Logger(ctx).Info("app cert", "route", cert.Domain, "issuer", cert.Issuer)
+
+	return auth.CreateCertificate(ctx, cluster, cert, certOwner)
+}
+
 // completeIngress takes an Ingress as created by the routes#ToIngress
 // method and fills in more data needed for Epinio.
-func completeIngress(ingress *networkingv1.Ingress, appRef models.AppRef, username string) *networkingv1.Ingress {
+func completeIngress(ingress *networkingv1.Ingress, appRef models.AppRef, route routes.Route, username string) *networkingv1.Ingress {
 	name := viper.GetString("ingress-class-name")
 	if name != "" {
 		ingress.Spec.IngressClassName = &name
 	}
 
-	ingress.ObjectMeta.Annotations = map[string]string{
-		"traefik.ingress.kubernetes.io/router.entrypoints": "websecure",
-		"traefik.ingress.kubernetes.io/router.tls":         "true",
+	if ingress.ObjectMeta.Annotations == nil {
+		ingress.ObjectMeta.Annotations = map[string]string{}
+	}
+	annotations := ingressprovider.Current().IngressAnnotations(appRef.Namespace, ingress.Name, route)
+	for key, value := range annotations {
+		ingress.ObjectMeta.Annotations[key] = value
 	}
 
 	ingress.ObjectMeta.Labels = map[string]string{
@@ -203,10 +388,16 @@ func completeIngress(ingress *networkingv1.Ingress, appRef models.AppRef, userna
 		"app.kubernetes.io/part-of":    appRef.Namespace,
 	}
 
+	// gRPC routes go to the service talking h2c to the application
+	serviceName := names.ServiceName(appRef.Name)
+	if route.Type == routes.TypeGRPC {
+		serviceName = names.GRPCServiceName(appRef.Name)
+	}
+
 	ingress.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend =
 		networkingv1.IngressBackend{
 			Service: &networkingv1.IngressServiceBackend{
-				Name: names.ServiceName(appRef.Name),
+				Name: serviceName,
 				Port: networkingv1.ServiceBackendPort{
 					Number: 8080,
 				}}}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/spf13/viper"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultClusterDomain is the DNS domain of kubernetes clusters, unless configured
+// otherwise.
+const DefaultClusterDomain = "cluster.local"
+
+const (
+	// InternalAnnotation marks an application without public routes. SyncIngresses
+	// creates no ingresses for such an application.
+	InternalAnnotation = "epinio.suse.org/internal"
+
+	// AliasComponent is the component label of the service aliasing the service of
+	// an application under the name of the application, for discovery by the other
+	// applications of its namespace.
+	AliasComponent = "application-alias"
+)
+
+// IsInternal returns true if the application resource is marked as internal.
+func IsInternal(applicationCR *unstructured.Unstructured) bool {
+	return applicationCR.GetAnnotations()[InternalAnnotation] == "true"
+}
+
+// InternalSet marks the application as internal, or public. Removing or adding
+// routes is the responsibility of the caller.
+func InternalSet(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, internal bool) error {
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return err
+	}
+
+	// A null value removes the annotation
+	var value interface{}
+	if internal {
+		value = "true"
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				InternalAnnotation: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Namespace(appRef.Namespace).Patch(ctx, appRef.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// InternalURL returns the url the application is reachable at from within the
+// cluster. This is the alias of the application's service, if it exists, and
+// the service itself otherwise. Within the namespace of the application the
+// first label of the host suffices.
+func InternalURL(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (string, error) {
+	host := names.ServiceName(appRef.Name)
+
+	alias, err := cluster.Kubectl.CoreV1().Services(appRef.Namespace).Get(ctx, appRef.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && IsAlias(alias.Labels, appRef) {
+		host = appRef.Name
+	}
+
+	return fmt.Sprintf("http://%s:8080", ServiceHost(host, appRef.Namespace)), nil
+}
+
+// ServiceHost returns the fully qualified in-cluster host name of the named kube service,
+// in the cluster domain configured for the server.
+func ServiceHost(name, namespace string) string {
+	domain := viper.GetString("cluster-domain")
+	if domain == "" {
+		domain = DefaultClusterDomain
+	}
+	return fmt.Sprintf("%s.%s.svc.%s", name, namespace, domain)
+}
+
+// IsAlias returns true if the labels are those of the alias service of the application.
+func IsAlias(labels map[string]string, appRef models.AppRef) bool {
+	return labels["app.kubernetes.io/component"] == AliasComponent &&
+		labels["app.kubernetes.io/name"] == appRef.Name
+}
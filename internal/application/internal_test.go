@@ -0,0 +1,66 @@
+package application
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Internal applications", func() {
+	Describe("desiredRoutesOf", func() {
+		It("returns no routes for internal applications", func() {
+			applicationCR := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			applicationCR.SetAnnotations(map[string]string{InternalAnnotation: "true"})
+
+			Expect(IsInternal(applicationCR)).To(BeTrue())
+			Expect(desiredRoutesOf(applicationCR)).To(BeEmpty())
+		})
+
+		It("requires routes for public applications", func() {
+			applicationCR := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+			Expect(IsInternal(applicationCR)).To(BeFalse())
+			_, err := desiredRoutesOf(applicationCR)
+			Expect(err).To(MatchError("couldn't parse the Application for Routes"))
+		})
+	})
+
+	Describe("IsAlias", func() {
+		appRef := models.NewAppRef("backend", "workspace")
+
+		It("recognizes the alias of the application", func() {
+			Expect(IsAlias(map[string]string{
+				"app.kubernetes.io/component": AliasComponent,
+				"app.kubernetes.io/name":      "backend",
+			}, appRef)).To(BeTrue())
+		})
+
+		It("rejects foreign services", func() {
+			Expect(IsAlias(map[string]string{
+				"app.kubernetes.io/name": "backend",
+			}, appRef)).To(BeFalse())
+			Expect(IsAlias(map[string]string{
+				"app.kubernetes.io/component": AliasComponent,
+				"app.kubernetes.io/name":      "frontend",
+			}, appRef)).To(BeFalse())
+		})
+	})
+
+	Describe("ServiceHost", func() {
+		AfterEach(func() {
+			viper.Set("cluster-domain", "")
+		})
+
+		It("defaults to the standard cluster domain", func() {
+			Expect(ServiceHost("backend", "workspace")).To(Equal("backend.workspace.svc.cluster.local"))
+		})
+
+		It("uses the configured cluster domain", func() {
+			viper.Set("cluster-domain", "example.internal")
+			Expect(ServiceHost("backend", "workspace")).To(Equal("backend.workspace.svc.example.internal"))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/internal/duration"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// LogParameters is the validated form of the models.LogParameters, ready for
+// use by the log tailer.
+type LogParameters struct {
+	Since          time.Duration
+	TailLines      *int64
+	Include        []*regexp.Regexp
+	Exclude        []*regexp.Regexp
+	PodQuery       *regexp.Regexp
+	ContainerQuery *regexp.Regexp
+	Timestamps     bool
+}
+
+// DefaultLogParameters returns the parameters used when a log request does not
+// restrict the log lines.
+func DefaultLogParameters() LogParameters {
+	return LogParameters{
+		Since:          duration.LogHistory(),
+		PodQuery:       regexp.MustCompile(".*"),
+		ContainerQuery: regexp.MustCompile(".*"),
+	}
+}
+
+// NewLogParameters validates the parameters of a log request, and returns them
+// in the form used by the log tailer.
+func NewLogParameters(params models.LogParameters) (LogParameters, error) {
+	result := DefaultLogParameters()
+
+	if params.Since != "" {
+		since, err := time.ParseDuration(params.Since)
+		if err != nil || since <= 0 {
+			return result, errors.Errorf("bad since '%s', expected a positive duration, e.g. 1h", params.Since)
+		}
+		result.Since = since
+	}
+
+	if params.Tail != nil {
+		if *params.Tail < 0 {
+			return result, errors.Errorf("bad tail '%d', expected a number of lines", *params.Tail)
+		}
+		tail := *params.Tail
+		result.TailLines = &tail
+	}
+
+	var err error
+	result.Include, err = compileAll("include", params.Include)
+	if err != nil {
+		return result, err
+	}
+	result.Exclude, err = compileAll("exclude", params.Exclude)
+	if err != nil {
+		return result, err
+	}
+
+	if params.Instance != "" {
+		if msgs := validation.IsDNS1123Subdomain(params.Instance); len(msgs) > 0 {
+			return result, errors.Errorf("bad instance '%s': %s", params.Instance, strings.Join(msgs, ", "))
+		}
+		result.PodQuery = regexp.MustCompile("^" + regexp.QuoteMeta(params.Instance) + "$")
+	}
+
+	if params.Container != "" {
+		result.ContainerQuery, err = regexp.Compile(params.Container)
+		if err != nil {
+			return result, errors.Wrapf(err, "bad container '%s'", params.Container)
+		}
+	}
+
+	result.Timestamps = params.Timestamps
+
+	return result, nil
+}
+
+// compileAll is a helper for NewLogParameters. It compiles the regular
+// expressions of the named parameter.
+func compileAll(name string, expressions []string) ([]*regexp.Regexp, error) {
+	result := []*regexp.Regexp{}
+	for _, expression := range expressions {
+		rex, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad %s '%s'", name, expression)
+		}
+		result = append(result, rex)
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"time"
+
+	"github.com/epinio/epinio/internal/duration"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Log parameters", func() {
+	It("defaults to all lines of all instances", func() {
+		params, err := NewLogParameters(models.LogParameters{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(params.Since).To(Equal(duration.LogHistory()))
+		Expect(params.TailLines).To(BeNil())
+		Expect(params.Include).To(BeEmpty())
+		Expect(params.Exclude).To(BeEmpty())
+		Expect(params.PodQuery.MatchString("myapp-5d8f7c-x2x9z")).To(BeTrue())
+		Expect(params.ContainerQuery.MatchString("myapp")).To(BeTrue())
+		Expect(params.Timestamps).To(BeFalse())
+	})
+
+	It("converts the restrictions", func() {
+		tail := int64(10)
+		params, err := NewLogParameters(models.LogParameters{
+			Since:      "90m",
+			Tail:       &tail,
+			Include:    []string{"ERROR", "WARN"},
+			Exclude:    []string{"healthz"},
+			Instance:   "myapp-5d8f7c-x2x9z",
+			Timestamps: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(params.Since).To(Equal(90 * time.Minute))
+		Expect(*params.TailLines).To(Equal(int64(10)))
+		Expect(params.Include).To(HaveLen(2))
+		Expect(params.Exclude[0].MatchString("GET /healthz")).To(BeTrue())
+		Expect(params.PodQuery.MatchString("myapp-5d8f7c-x2x9z")).To(BeTrue())
+		Expect(params.PodQuery.MatchString("myapp-5d8f7c-x2x9z-other")).To(BeFalse())
+		Expect(params.Timestamps).To(BeTrue())
+	})
+
+	It("rejects bad values", func() {
+		negative := int64(-1)
+		for _, bad := range []models.LogParameters{
+			{Since: "yesterday"},
+			{Since: "-1h"},
+			{Tail: &negative},
+			{Include: []string{"("}},
+			{Exclude: []string{"[a-"}},
+			{Instance: "My_Instance"},
+			{Container: "*"},
+		} {
+			_, err := NewLogParameters(bad)
+			Expect(err).To(HaveOccurred(), "%+v", bad)
+		}
+	})
+})
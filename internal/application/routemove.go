@@ -0,0 +1,289 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	ingressprovider "github.com/epinio/epinio/internal/ingress"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// MoveRoute moves the desired route of the `from` application to the `to`
+// application of the same namespace. The `to` application has to be deployed.
+// The existing Ingress (or Service, for TCP routes) of the route is re-pointed
+// at the `to` application, keeping its certificate and addresses, so that the
+// route is served without interruption. The backend service the route needs
+// is created before. The route is added to the `to` application before it is
+// removed from the `from` application, i.e. it is never unclaimed. The
+// certificate configuration of the route moves along with it. If the move
+// fails, the route is returned to the `from` application.
+func MoveRoute(ctx context.Context, cluster *kubernetes.Cluster, from, to models.AppRef, desiredRoute, username string) error {
+	log := requestctx.Logger(ctx).WithName("MoveRoute")
+
+	route := routes.FromString(desiredRoute)
+
+	fromCR, err := Get(ctx, cluster, from)
+	if err != nil {
+		return err
+	}
+	toCR, err := Get(ctx, cluster, to)
+	if err != nil {
+		return err
+	}
+	owner := metav1.OwnerReference{
+		APIVersion: toCR.GetAPIVersion(),
+		Kind:       toCR.GetKind(),
+		Name:       toCR.GetName(),
+		UID:        toCR.GetUID(),
+	}
+
+	// Claim the route for the new application
+	toRoutes, _, err := unstructured.NestedStringSlice(toCR.Object, "spec", "routes")
+	if err != nil {
+		return err
+	}
+	err = routesSet(ctx, cluster, to, append(toRoutes, desiredRoute))
+	if err != nil {
+		return err
+	}
+
+	err = moveRouteResources(ctx, cluster, from, to, owner, route, username)
+	if err == nil {
+		// Release the route from the old application
+		var fromRoutes []string
+		fromRoutes, _, err = unstructured.NestedStringSlice(fromCR.Object, "spec", "routes")
+		if err == nil {
+			err = routesSet(ctx, cluster, from, withoutRoute(fromRoutes, route))
+		}
+	}
+	if err != nil {
+		log.Info("move failed, returning the route", "route", route.String(), "error", err.Error())
+		undoMoveRoute(ctx, cluster, from, to, toRoutes, route, username)
+		return err
+	}
+
+	// Creates the resources of the route, if the old application had none yet
+	_, err = SyncIngresses(ctx, cluster, to, username)
+	if err != nil {
+		return err
+	}
+
+	// Removes the resources the old application needs no more, e.g. its gRPC service
+	_, err = SyncIngresses(ctx, cluster, from, username)
+	return err
+}
+
+// moveRouteResources is a helper for MoveRoute. It moves the certificate
+// configuration of the route, and re-points its existing resources at the `to`
+// application, after ensuring the backend service of the route.
+func moveRouteResources(ctx context.Context, cluster *kubernetes.Cluster, from, to models.AppRef,
+	owner metav1.OwnerReference, route routes.Route, username string) error {
+
+	log := requestctx.Logger(ctx).WithName("MoveRoute")
+
+	err := moveRouteTLS(ctx, cluster, from, to, route)
+	if err != nil {
+		return err
+	}
+
+	// The service of the gRPC routes exists only for applications having such routes
+	if route.Type == routes.TypeGRPC {
+		err = syncGRPCService(ctx, cluster, to, owner, username, true)
+		if err != nil {
+			return errors.Wrap(err, "creating the application gRPC service")
+		}
+	}
+
+	// Re-point the existing resources of the route
+	if route.Type == routes.TypeTCP {
+		serviceList, err := tcpServiceList(ctx, cluster, from.Namespace, from.Name)
+		if err != nil {
+			return err
+		}
+		for _, service := range serviceList.Items {
+			if tcpRouteFromService(service).String() != route.String() {
+				continue
+			}
+			log.Info("moving app tcp service", "name", service.Name, "from", from.Name, "to", to.Name)
+
+			err = updateTCPService(ctx, cluster, service, to, owner, username, route)
+			if err != nil {
+				return errors.Wrap(err, "moving an application TCP route")
+			}
+		}
+		return nil
+	}
+
+	return moveIngress(ctx, cluster, from, to, owner, route, username)
+}
+
+// undoMoveRoute is a helper for MoveRoute. It returns the route, and its
+// certificate configuration, to the `from` application after a failed move, and
+// brings the resources of both applications in line with their routes. Errors
+// are logged only, to not hide the error of the move.
+func undoMoveRoute(ctx context.Context, cluster *kubernetes.Cluster, from, to models.AppRef,
+	toRoutes []string, route routes.Route, username string) {
+
+	log := requestctx.Logger(ctx).WithName("MoveRoute")
+
+	if err := routesSet(ctx, cluster, to, toRoutes); err != nil {
+		log.Error(err, "failed to release the route", "app", to.Name)
+	}
+	if err := moveRouteTLS(ctx, cluster, to, from, route); err != nil {
+		log.Error(err, "failed to return the route tls configuration", "app", from.Name)
+	}
+	if _, err := SyncIngresses(ctx, cluster, to, username); err != nil {
+		log.Error(err, "failed to sync the routes", "app", to.Name)
+	}
+	if _, err := SyncIngresses(ctx, cluster, from, username); err != nil {
+		log.Error(err, "failed to sync the routes", "app", from.Name)
+	}
+}
+
+// moveIngress is a helper for MoveRoute. It re-points the Ingress of the route,
+// if any, from the `from` application to the `to` application.
+func moveIngress(ctx context.Context, cluster *kubernetes.Cluster, from, to models.AppRef,
+	owner metav1.OwnerReference, route routes.Route, username string) error {
+
+	ingressList, err := ingressListForApp(ctx, cluster, from)
+	if err != nil {
+		return err
+	}
+
+	routeTLS, err := RouteTLS(ctx, cluster, to)
+	if err != nil {
+		return err
+	}
+
+	for _, ingress := range ingressList.Items {
+		ingressRoute, err := routes.FromIngress(ingress)
+		if err != nil {
+			return err
+		}
+		if ingressRoute.String() != route.String() {
+			continue
+		}
+
+		requestctx.Logger(ctx).Info("moving app ingress", "name", ingress.Name, "from", from.Name, "to", to.Name)
+
+		repointIngress(&ingress, to, owner, route, username, routeTLS[route.String()])
+
+		updatedIngress, err := cluster.Kubectl.NetworkingV1().Ingresses(to.Namespace).Update(ctx, &ingress, metav1.UpdateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "moving an application Ingress")
+		}
+
+		// The provider specific resources are labeled like the Ingress
+		err = ingressprovider.Current().Sync(ctx, cluster, updatedIngress, route)
+		if err != nil {
+			return errors.Wrap(err, "exposing an application Ingress")
+		}
+	}
+
+	return nil
+}
+
+// repointIngress changes the Ingress of the route to belong to, and forward to,
+// the `to` application. The name of the Ingress, and with it the certificate, is
+// kept.
+func repointIngress(ingress *networkingv1.Ingress, to models.AppRef, owner metav1.OwnerReference,
+	route routes.Route, username string, config models.RouteTLS) {
+
+	completeIngress(ingress, to, route, username)
+	applyRouteTLS(ingress, config)
+	ingress.SetOwnerReferences([]metav1.OwnerReference{owner})
+}
+
+// moveRouteTLS is a helper for MoveRoute. It moves the certificate
+// configuration of the route from the `from` application to the `to`
+// application. A user provided certificate secret is additionally owned by the
+// `to` application, so that it survives the deletion of the `from` application.
+func moveRouteTLS(ctx context.Context, cluster *kubernetes.Cluster, from, to models.AppRef, route routes.Route) error {
+	routeTLS, err := RouteTLS(ctx, cluster, from)
+	if err != nil {
+		return err
+	}
+
+	config, ok := routeTLS[route.String()]
+	if !ok {
+		return nil
+	}
+
+	if config.Secret != "" {
+		toCR, err := Get(ctx, cluster, to)
+		if err != nil {
+			return err
+		}
+
+		secret, err := cluster.GetSecret(ctx, to.Namespace, config.Secret)
+		if err != nil {
+			return err
+		}
+
+		owned := false
+		for _, ref := range secret.OwnerReferences {
+			if ref.UID == toCR.GetUID() {
+				owned = true
+			}
+		}
+		if len(secret.OwnerReferences) > 0 && !owned {
+			secret.OwnerReferences = append(secret.OwnerReferences, metav1.OwnerReference{
+				APIVersion: toCR.GetAPIVersion(),
+				Kind:       toCR.GetKind(),
+				Name:       toCR.GetName(),
+				UID:        toCR.GetUID(),
+			})
+			_, err = cluster.Kubectl.CoreV1().Secrets(to.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = RouteTLSSet(ctx, cluster, to, route.String(), config)
+	if err != nil {
+		return err
+	}
+
+	return RouteTLSSet(ctx, cluster, from, route.String(), models.RouteTLS{})
+}
+
+// routesSet replaces the desired routes of the application.
+func routesSet(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, routeList []string) error {
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(routeList)
+	if err != nil {
+		return err
+	}
+
+	// Note: `add` replaces existing routes, and also works for resources lacking them.
+	patch := fmt.Sprintf(`[{ "op": "add", "path": "/spec/routes", "value": %s }]`, value)
+
+	_, err = client.Namespace(appRef.Namespace).Patch(ctx, appRef.Name, types.JSONPatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// withoutRoute returns the routes of the list which are not the given route.
+// Routes are compared by identity, i.e. without their options.
+func withoutRoute(routeList []string, route routes.Route) []string {
+	result := []string{}
+	for _, r := range routeList {
+		if routes.FromString(r).String() != route.String() {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Route moves", func() {
+	Describe("repointIngress", func() {
+		It("hands the ingress over to the new application", func() {
+			route := routes.FromString("www.example.com/shop")
+			from := models.NewAppRef("shop-v1", "workspace")
+			to := models.NewAppRef("shop-v2", "workspace")
+
+			ingress := route.ToIngress("i-shop-v1-www.example.com-shop")
+			completeIngress(&ingress, from, route, "admin")
+			owner := metav1.OwnerReference{Kind: "App", Name: "shop-v2", UID: "5678"}
+
+			repointIngress(&ingress, to, owner, route, "admin", models.RouteTLS{})
+
+			Expect(ingress.Name).To(Equal("i-shop-v1-www.example.com-shop"))
+			Expect(ingress.Labels).To(HaveKeyWithValue("app.kubernetes.io/name", "shop-v2"))
+			Expect(ingress.OwnerReferences).To(Equal([]metav1.OwnerReference{owner}))
+			Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name).To(Equal("s-shop-v2"))
+			Expect(ingress.Spec.TLS[0].SecretName).To(Equal("i-shop-v1-www.example.com-shop-tls"))
+		})
+	})
+
+	Describe("withoutRoute", func() {
+		It("removes the route, regardless of its options", func() {
+			routeList := []string{"www.example.com/shop?timeout=30s", "example.com"}
+			Expect(withoutRoute(routeList, routes.FromString("www.example.com/shop"))).To(
+				Equal([]string{"example.com"}))
+		})
+	})
+})
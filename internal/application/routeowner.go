@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RouteConflict describes a route which is already claimed by another application
+type RouteConflict struct {
+	Route string
+	Owner models.AppRef
+}
+
+// RouteConflicts returns the routes of the list which are claimed by applications
+// other than the referenced one, across all namespaces, together with the owning
+// application. A route is claimed by an application when it is one of the desired
+// routes stored in the Application CR, regardless of the application being active.
+// Routes are claimed by host and path, regardless of their type, as HTTP and gRPC
+// routes are both served by an Ingress for the host and path. Routes of the list
+// claiming the same host and path with different types conflict as well, these
+// are reported as owned by the referenced application.
+func RouteConflicts(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, routeList []string) ([]RouteConflict, error) {
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.Namespace("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[string]models.AppRef{}
+	for _, app := range list.Items {
+		owner := models.NewAppRef(app.GetName(), app.GetNamespace())
+		if owner == appRef {
+			continue
+		}
+
+		desiredRoutes, _, err := unstructured.NestedStringSlice(app.Object, "spec", "routes")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, route := range desiredRoutes {
+			parsed := routes.FromString(route)
+			if parsed.Type == routes.TypeTCP {
+				continue
+			}
+			owners[ownerKey(parsed)] = owner
+		}
+	}
+
+	return routeConflicts(appRef, owners, routeList), nil
+}
+
+// AllRoutes returns the active routes of all applications, across all
+// namespaces, with their owning application and certificate, sorted by route.
+// TCP routes have no certificate.
+func AllRoutes(ctx context.Context, cluster *kubernetes.Cluster) (models.RouteList, error) {
+	ingressList, err := cluster.ListIngress(ctx, "",
+		"app.kubernetes.io/managed-by=epinio,app.kubernetes.io/component=application")
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.RouteList{}
+	for _, ingress := range ingressList.Items {
+		route, err := routes.FromIngress(ingress)
+		if err != nil {
+			return nil, err
+		}
+
+		info := models.RouteInfo{
+			Route:     route.String(),
+			Namespace: ingress.Namespace,
+			App:       ingress.Labels["app.kubernetes.io/name"],
+		}
+
+		if len(ingress.Spec.TLS) > 0 {
+			certificate, err := ingressCertificate(ctx, cluster, ingress)
+			if err != nil {
+				return nil, err
+			}
+			info.Certificate = &certificate
+		}
+
+		result = append(result, info)
+	}
+
+	serviceList, err := tcpServiceList(ctx, cluster, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range serviceList.Items {
+		result = append(result, models.RouteInfo{
+			Route:     tcpRouteFromService(service).String(),
+			Namespace: service.Namespace,
+			App:       service.Labels["app.kubernetes.io/name"],
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Route < result[j].Route
+	})
+
+	return result, nil
+}
+
+// routeConflicts is the core of RouteConflicts, separated out for testing.
+func routeConflicts(appRef models.AppRef, owners map[string]models.AppRef, routeList []string) []RouteConflict {
+	result := []RouteConflict{}
+	claimed := map[string]string{}
+	for _, route := range routeList {
+		parsed := routes.FromString(route)
+		if parsed.Type == routes.TypeTCP {
+			// Port based, every service has its own address
+			continue
+		}
+
+		key := ownerKey(parsed)
+		if owner, ok := owners[key]; ok {
+			result = append(result, RouteConflict{Route: route, Owner: owner})
+			continue
+		}
+		if other, ok := claimed[key]; ok && other != parsed.String() {
+			result = append(result, RouteConflict{Route: route, Owner: appRef})
+			continue
+		}
+		claimed[key] = parsed.String()
+	}
+	return result
+}
+
+// ownerKey returns the host and path a route is claimed by.
+func ownerKey(route routes.Route) string {
+	return strings.TrimSuffix(route.Domain+route.Path, "/")
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Route ownership", func() {
+	Describe("routeConflicts", func() {
+		appRef := models.NewAppRef("web", "workspace")
+		owners := map[string]models.AppRef{
+			"app.example.com":      models.NewAppRef("app", "workspace"),
+			"shop.example.com/api": models.NewAppRef("api", "shop"),
+		}
+
+		It("returns nothing for unclaimed routes", func() {
+			Expect(routeConflicts(appRef, owners, []string{"other.example.com", "shop.example.com"})).To(BeEmpty())
+		})
+
+		It("names the owner of claimed routes", func() {
+			Expect(routeConflicts(appRef, owners, []string{"fresh.example.com", "shop.example.com/api"})).To(Equal([]RouteConflict{
+				{Route: "shop.example.com/api", Owner: models.NewAppRef("api", "shop")},
+			}))
+		})
+
+		It("normalizes the requested routes", func() {
+			Expect(routeConflicts(appRef, owners, []string{"app.example.com/"})).To(Equal([]RouteConflict{
+				{Route: "app.example.com/", Owner: models.NewAppRef("app", "workspace")},
+			}))
+		})
+
+		It("claims host and path regardless of the type of route", func() {
+			Expect(routeConflicts(appRef, owners, []string{"grpc://shop.example.com/api"})).To(Equal([]RouteConflict{
+				{Route: "grpc://shop.example.com/api", Owner: models.NewAppRef("api", "shop")},
+			}))
+		})
+
+		It("reports http and grpc routes of the same host and path in the list", func() {
+			Expect(routeConflicts(appRef, owners, []string{"x.example.com/api", "grpc://x.example.com/api"})).To(Equal([]RouteConflict{
+				{Route: "grpc://x.example.com/api", Owner: appRef},
+			}))
+		})
+
+		It("ignores repeated routes of the list, and tcp routes", func() {
+			Expect(routeConflicts(appRef, owners, []string{"x.example.com/api", "x.example.com/api/", "tcp://1883", "tcp://1884"})).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,417 @@
+package application
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"sort"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	routeTLSKey = "routes"
+
+	// RouteTLSAnnotation records the certificate configuration (See
+	// models.RouteTLS.String) an ingress was created with. A difference to the
+	// desired configuration causes SyncIngresses to update the ingress.
+	RouteTLSAnnotation = "epinio.suse.org/route-tls"
+
+	// certificateNameAnnotation is set by cert-manager on the secrets it issues,
+	// naming the certificate the secret belongs to.
+	certificateNameAnnotation = "cert-manager.io/certificate-name"
+)
+
+// RouteTLS returns the certificate configuration of the application's routes,
+// keyed by route. Routes using the default issuer have no entry.
+func RouteTLS(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (map[string]models.RouteTLS, error) {
+	tlsSecret, err := routeTLSLoad(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRouteTLS(tlsSecret)
+}
+
+// RouteTLSSet sets the certificate configuration for the specified route of the
+// application. An empty configuration returns the route to the default issuer.
+// Synchronizing the ingresses is the responsibility of the caller.
+func RouteTLSSet(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, route string, config models.RouteTLS) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tlsSecret, err := routeTLSLoad(ctx, cluster, appRef)
+		if err != nil {
+			return err
+		}
+
+		tlsMap, err := decodeRouteTLS(tlsSecret)
+		if err != nil {
+			return err
+		}
+
+		if config.String() == "" {
+			delete(tlsMap, route)
+		} else {
+			tlsMap[route] = config
+		}
+
+		encoded, err := json.Marshal(tlsMap)
+		if err != nil {
+			return err
+		}
+
+		tlsSecret.Data = map[string][]byte{
+			routeTLSKey: encoded,
+		}
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(appRef.Namespace).Update(
+			ctx, tlsSecret, metav1.UpdateOptions{})
+
+		return err
+	})
+}
+
+// RouteCertificateStore saves a user provided certificate and key for the
+// specified route of the application into a kube TLS secret, and returns the
+// name of that secret. The secret is owned by the application.
+func RouteCertificateStore(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, route string, cert, key []byte) (string, error) {
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return "", errors.Wrap(err, "bad certificate/key pair")
+	}
+
+	app, err := Get(ctx, cluster, appRef)
+	if err != nil {
+		return "", err
+	}
+
+	secretName := uploadedSecretName(appRef, route)
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: appRef.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: app.GetAPIVersion(),
+				Kind:       app.GetKind(),
+				Name:       app.GetName(),
+				UID:        app.GetUID(),
+			}},
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       appRef.Name,
+				"app.kubernetes.io/part-of":    appRef.Namespace,
+				"app.kubernetes.io/managed-by": "epinio",
+				"app.kubernetes.io/component":  "application",
+				EpinioApplicationAreaLabel:     "routetls",
+			},
+		},
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       cert,
+			v1.TLSPrivateKeyKey: key,
+		},
+	}
+
+	secrets := cluster.Kubectl.CoreV1().Secrets(appRef.Namespace)
+
+	_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return secretName, nil
+}
+
+// ValidateRouteSecret checks that the named secret exists in the namespace of the
+// application and holds a certificate and key.
+func ValidateRouteSecret(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, secretName string) error {
+	secret, err := cluster.GetSecret(ctx, appRef.Namespace, secretName)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := secret.Data[v1.TLSCertKey]; !ok {
+		return errors.Errorf("secret %s has no %s", secretName, v1.TLSCertKey)
+	}
+	if _, ok := secret.Data[v1.TLSPrivateKeyKey]; !ok {
+		return errors.Errorf("secret %s has no %s", secretName, v1.TLSPrivateKeyKey)
+	}
+
+	return nil
+}
+
+// RouteCertificates returns information about the certificates used by the
+// active routes of the application, including their expiry.
+func RouteCertificates(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) ([]models.RouteCertificate, error) {
+	ingressList, err := ingressListForApp(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.RouteCertificate{}
+	for _, ingress := range ingressList.Items {
+		if len(ingress.Spec.TLS) == 0 {
+			continue
+		}
+
+		certificate, err := ingressCertificate(ctx, cluster, ingress)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, certificate)
+	}
+
+	return result, nil
+}
+
+// ingressCertificate returns information about the certificate used by the
+// ingress of an application route, including its expiry.
+func ingressCertificate(ctx context.Context, cluster *kubernetes.Cluster, ingress networkingv1.Ingress) (models.RouteCertificate, error) {
+	route, err := routes.FromIngress(ingress)
+	if err != nil {
+		return models.RouteCertificate{}, err
+	}
+
+	certificate := models.RouteCertificate{
+		Route:  route.String(),
+		Secret: ingress.Spec.TLS[0].SecretName,
+		Source: ingress.Annotations[RouteTLSAnnotation],
+	}
+
+	secret, err := cluster.GetSecret(ctx, ingress.Namespace, certificate.Secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return models.RouteCertificate{}, err
+	}
+	if err == nil {
+		expires, err := certificateExpiry(secret.Data[v1.TLSCertKey])
+		if err == nil {
+			certificate.Expires = expires.Format(time.RFC3339)
+		}
+	}
+
+	return certificate, nil
+}
+
+// applyRouteTLS is a helper for SyncIngresses. It points the ingress to the
+// secret holding the certificate for the given configuration, and records the
+// configuration on the ingress.
+func applyRouteTLS(ingress *networkingv1.Ingress, config models.RouteTLS) {
+	if config.Secret != "" {
+		ingress.Spec.TLS[0].SecretName = config.Secret
+	} else {
+		ingress.Spec.TLS[0].SecretName = ingress.Name + "-tls" // Secret has the same name as the Ingress
+	}
+
+	if ingress.ObjectMeta.Annotations == nil {
+		ingress.ObjectMeta.Annotations = map[string]string{}
+	}
+	ingress.ObjectMeta.Annotations[RouteTLSAnnotation] = config.String()
+}
+
+// routeTLSPrune is a helper for SyncIngresses. It removes the certificate configuration
+// of the routes the application no longer has, together with the certificates uploaded
+// for these routes.
+func routeTLSPrune(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, desiredRoutes map[string]bool) error {
+	pruned := []models.RouteTLS{}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tlsSecret, err := routeTLSLoad(ctx, cluster, appRef)
+		if err != nil {
+			return err
+		}
+
+		tlsMap, err := decodeRouteTLS(tlsSecret)
+		if err != nil {
+			return err
+		}
+
+		stale := staleRouteTLS(tlsMap, desiredRoutes)
+		if len(stale) == 0 {
+			return nil
+		}
+
+		pruned = []models.RouteTLS{}
+		for _, route := range stale {
+			if tlsMap[route].Secret == uploadedSecretName(appRef, route) {
+				pruned = append(pruned, tlsMap[route])
+			}
+			delete(tlsMap, route)
+		}
+
+		encoded, err := json.Marshal(tlsMap)
+		if err != nil {
+			return err
+		}
+
+		tlsSecret.Data = map[string][]byte{
+			routeTLSKey: encoded,
+		}
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(appRef.Namespace).Update(
+			ctx, tlsSecret, metav1.UpdateOptions{})
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, config := range pruned {
+		err := cluster.Kubectl.CoreV1().Secrets(appRef.Namespace).Delete(ctx, config.Secret, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// staleRouteTLS returns the sorted routes of the configuration which are not desired.
+func staleRouteTLS(tlsMap map[string]models.RouteTLS, desiredRoutes map[string]bool) []string {
+	stale := []string{}
+	for route := range tlsMap {
+		if !desiredRoutes[route] {
+			stale = append(stale, route)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// deleteIssuedSecret is a helper for SyncIngresses. It removes the secret holding the
+// certificate cert-manager issued for the ingress. cert-manager leaves the secret behind
+// when the certificate is deleted. Secrets not issued for the ingress are left alone.
+func deleteIssuedSecret(ctx context.Context, cluster *kubernetes.Cluster, namespace, ingressName string) error {
+	secretName := ingressName + "-tls"
+
+	secret, err := cluster.GetSecret(ctx, namespace, secretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !isIssuedSecret(secret, ingressName) {
+		return nil
+	}
+
+	err = cluster.Kubectl.CoreV1().Secrets(namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// isIssuedSecret returns true if cert-manager issued the secret for the certificate of
+// the ingress. The certificate has the name of the ingress.
+func isIssuedSecret(secret *v1.Secret, ingressName string) bool {
+	return secret.Annotations[certificateNameAnnotation] == ingressName
+}
+
+// uploadedSecretName returns the name of the secret holding the certificate uploaded
+// for the route of the application.
+func uploadedSecretName(appRef models.AppRef, route string) string {
+	return names.IngressName(appRef.Name+"-"+route) + "-byo-tls"
+}
+
+// certificateExpiry returns the end of the validity period of the first
+// certificate found in the PEM data.
+func certificateExpiry(pemData []byte) (time.Time, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return time.Time{}, errors.New("no PEM data found")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return cert.NotAfter, nil
+}
+
+// decodeRouteTLS is a helper for the public functions. It extracts the route
+// configuration map from the secret.
+func decodeRouteTLS(tlsSecret *v1.Secret) (map[string]models.RouteTLS, error) {
+	result := map[string]models.RouteTLS{}
+
+	data, ok := tlsSecret.Data[routeTLSKey]
+	if !ok {
+		return result, nil
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, errors.Wrap(err, "bad route tls configuration")
+	}
+
+	return result, nil
+}
+
+// routeTLSLoad locates and returns the kube secret storing the certificate
+// configuration of the referenced application's routes. If necessary it creates
+// that secret.
+func routeTLSLoad(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (*v1.Secret, error) {
+	secretName := appRef.MakeRouteTLSSecretName()
+
+	tlsSecret, err := cluster.GetSecret(ctx, appRef.Namespace, secretName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		// Error is `Not Found`. Create the secret.
+
+		app, err := Get(ctx, cluster, appRef)
+		if err != nil {
+			// Should not happen. The application was validated to exist already somewhere
+			// by this function's callers.
+			return nil, err
+		}
+
+		owner := metav1.OwnerReference{
+			APIVersion: app.GetAPIVersion(),
+			Kind:       app.GetKind(),
+			Name:       app.GetName(),
+			UID:        app.GetUID(),
+		}
+
+		tlsSecret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: appRef.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					owner,
+				},
+				Labels: map[string]string{
+					"app.kubernetes.io/name":       appRef.Name,
+					"app.kubernetes.io/part-of":    appRef.Namespace,
+					"app.kubernetes.io/managed-by": "epinio",
+					"app.kubernetes.io/component":  "application",
+					EpinioApplicationAreaLabel:     "routetls",
+				},
+			},
+		}
+		err = cluster.CreateSecret(ctx, appRef.Namespace, *tlsSecret)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tlsSecret, nil
+}
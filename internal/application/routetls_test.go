@@ -0,0 +1,126 @@
+package application
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Route certificates", func() {
+	Describe("certificateExpiry", func() {
+		It("returns the end of the validity period", func() {
+			notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "myapp.mydomain.org"},
+				NotBefore:    notAfter.Add(-time.Hour),
+				NotAfter:     notAfter,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+			Expect(err).ToNot(HaveOccurred())
+
+			expires, err := certificateExpiry(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expires.UTC()).To(Equal(notAfter))
+		})
+
+		It("fails for data without a certificate", func() {
+			_, err := certificateExpiry([]byte("garbage"))
+			Expect(err).To(MatchError("no PEM data found"))
+		})
+	})
+
+	Describe("decodeRouteTLS", func() {
+		It("returns an empty configuration for a new secret", func() {
+			config, err := decodeRouteTLS(&v1.Secret{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).To(BeEmpty())
+		})
+
+		It("returns the configuration per route", func() {
+			config, err := decodeRouteTLS(&v1.Secret{Data: map[string][]byte{
+				routeTLSKey: []byte(`{"myapp.mydomain.org/api":{"issuer":"corporate"}}`),
+			}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).To(Equal(map[string]models.RouteTLS{
+				"myapp.mydomain.org/api": {Issuer: "corporate"},
+			}))
+		})
+	})
+
+	Describe("applyRouteTLS", func() {
+		var ingress networkingv1.Ingress
+
+		BeforeEach(func() {
+			ingress = networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "i-myapp"},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{{}},
+				},
+			}
+		})
+
+		It("uses the secret of the ingress by default", func() {
+			applyRouteTLS(&ingress, models.RouteTLS{Issuer: "corporate"})
+			Expect(ingress.Spec.TLS[0].SecretName).To(Equal("i-myapp-tls"))
+			Expect(ingress.Annotations[RouteTLSAnnotation]).To(Equal("issuer=corporate"))
+		})
+
+		It("uses a user provided secret", func() {
+			applyRouteTLS(&ingress, models.RouteTLS{Secret: "mycert"})
+			Expect(ingress.Spec.TLS[0].SecretName).To(Equal("mycert"))
+			Expect(ingress.Annotations[RouteTLSAnnotation]).To(Equal("secret=mycert"))
+		})
+	})
+
+	Describe("staleRouteTLS", func() {
+		It("returns the configured routes the application no longer has", func() {
+			tlsMap := map[string]models.RouteTLS{
+				"myapp.mydomain.org":     {Issuer: "corporate"},
+				"old.mydomain.org":       {Secret: "mycert"},
+				"older.mydomain.org/api": {Issuer: "corporate"},
+			}
+			desired := map[string]bool{"myapp.mydomain.org": true}
+
+			Expect(staleRouteTLS(tlsMap, desired)).To(Equal([]string{"old.mydomain.org", "older.mydomain.org/api"}))
+			Expect(staleRouteTLS(tlsMap, map[string]bool{
+				"myapp.mydomain.org":     true,
+				"old.mydomain.org":       true,
+				"older.mydomain.org/api": true,
+			})).To(BeEmpty())
+		})
+	})
+
+	Describe("isIssuedSecret", func() {
+		It("recognizes the secret issued for the ingress", func() {
+			secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:        "i-myapp-tls",
+				Annotations: map[string]string{"cert-manager.io/certificate-name": "i-myapp"},
+			}}
+			Expect(isIssuedSecret(secret, "i-myapp")).To(BeTrue())
+			Expect(isIssuedSecret(secret, "i-other")).To(BeFalse())
+		})
+
+		It("leaves user secrets alone", func() {
+			secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "i-myapp-tls"}}
+			Expect(isIssuedSecret(secret, "i-myapp")).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// TCPRouteComponent is the component label of the services exposing the TCP
+// routes of applications, one service per route.
+const TCPRouteComponent = "application-tcp-route"
+
+// syncTCPServices is a helper for SyncIngresses. It ensures that each of the
+// TCP routes has a respective LoadBalancer or NodePort service, and that no
+// other such services exist for the application.
+func syncTCPServices(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef,
+	owner metav1.OwnerReference, username string, tcpRoutes []routes.Route) error {
+
+	log := requestctx.Logger(ctx)
+	services := cluster.Kubectl.CoreV1().Services(appRef.Namespace)
+
+	serviceList, err := tcpServiceList(ctx, cluster, appRef.Namespace, appRef.Name)
+	if err != nil {
+		return err
+	}
+
+	// Services are identified by the port of their route. Their name may be
+	// derived from another application, for routes moved between applications.
+	existingServices := map[string]v1.Service{}
+	for _, service := range serviceList.Items {
+		existingServices[tcpRouteFromService(service).String()] = service
+	}
+
+	desiredServices := map[string]bool{}
+	for _, route := range tcpRoutes {
+		desiredServices[route.String()] = true
+
+		existing, ok := existingServices[route.String()]
+		if !ok {
+			log.Info("creating app tcp service", "namespace", appRef.Namespace, "app", appRef.Name, "route", route.String())
+
+			service := newTCPService(appRef, username, route)
+			service.SetOwnerReferences([]metav1.OwnerReference{owner})
+			_, err := services.Create(ctx, service, metav1.CreateOptions{})
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+			continue
+		}
+
+		if existing.Annotations[routes.OptionsAnnotation] == route.Options.String() {
+			continue
+		}
+		log.Info("updating app tcp service", "namespace", appRef.Namespace, "app", appRef.Name, "route", route.String())
+
+		err := updateTCPService(ctx, cluster, existing, appRef, owner, username, route)
+		if err != nil {
+			return err
+		}
+	}
+
+	for route, service := range existingServices {
+		if desiredServices[route] {
+			continue
+		}
+		err := services.Delete(ctx, service.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("deleted app tcp service", "name", service.Name)
+	}
+
+	return nil
+}
+
+// updateTCPService replaces the existing service of a TCP route with the
+// service for the route and application. The allocated addresses and ports of
+// the existing service are kept.
+func updateTCPService(ctx context.Context, cluster *kubernetes.Cluster, existing v1.Service,
+	appRef models.AppRef, owner metav1.OwnerReference, username string, route routes.Route) error {
+
+	service := newTCPService(appRef, username, route)
+	service.SetOwnerReferences([]metav1.OwnerReference{owner})
+	service.Name = existing.Name
+	service.ResourceVersion = existing.ResourceVersion
+	service.Spec.ClusterIP = existing.Spec.ClusterIP
+	if existing.Spec.Type == service.Spec.Type {
+		service.Spec.Ports[0].NodePort = existing.Spec.Ports[0].NodePort
+	}
+
+	_, err := cluster.Kubectl.CoreV1().Services(appRef.Namespace).Update(ctx, service, metav1.UpdateOptions{})
+	return err
+}
+
+// tcpServiceList returns the services exposing the TCP routes of the named
+// application. An empty name returns the services of all applications. An
+// empty namespace returns the services of all namespaces.
+func tcpServiceList(ctx context.Context, cluster *kubernetes.Cluster, namespace, appName string) (*v1.ServiceList, error) {
+	selector := map[string]string{
+		"app.kubernetes.io/component": TCPRouteComponent,
+	}
+	if appName != "" {
+		selector["app.kubernetes.io/name"] = appName
+	}
+
+	return cluster.Kubectl.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set(selector).AsSelector().String(),
+	})
+}
+
+// newTCPService returns the service exposing the TCP route of the application.
+func newTCPService(appRef models.AppRef, username string, route routes.Route) *v1.Service {
+	serviceType := v1.ServiceTypeLoadBalancer
+	if route.Options.Expose == routes.ExposeNodePort {
+		serviceType = v1.ServiceTypeNodePort
+	}
+
+	target := route.Port
+	if route.Options.TargetPort > 0 {
+		target = route.Options.TargetPort
+	}
+
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.TCPServiceName(fmt.Sprintf("%s-%d", appRef.Name, route.Port)),
+			Namespace: appRef.Namespace,
+			Annotations: map[string]string{
+				routes.OptionsAnnotation: route.Options.String(),
+			},
+			Labels: map[string]string{
+				"app.kubernetes.io/component":  TCPRouteComponent,
+				"app.kubernetes.io/managed-by": "epinio",
+				"app.kubernetes.io/name":       appRef.Name,
+				"app.kubernetes.io/part-of":    appRef.Namespace,
+				"app.kubernetes.io/created-by": username,
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Type: serviceType,
+			Ports: []v1.ServicePort{
+				{
+					Name:       "tcp",
+					Port:       route.Port,
+					Protocol:   v1.ProtocolTCP,
+					TargetPort: intstr.FromInt(int(target)),
+				},
+			},
+			Selector: map[string]string{
+				"app.kubernetes.io/component": "application",
+				"app.kubernetes.io/name":      appRef.Name,
+			},
+			LoadBalancerSourceRanges: route.Options.AllowList,
+		},
+	}
+}
+
+// tcpRouteFromService returns the TCP route exposed by the service.
+func tcpRouteFromService(service v1.Service) routes.Route {
+	options, _ := routes.ParseOptions(service.Annotations[routes.OptionsAnnotation])
+
+	return routes.Route{
+		Type:    routes.TypeTCP,
+		Port:    service.Spec.Ports[0].Port,
+		Options: options,
+	}
+}
+
+// tcpEndpoint returns the address clients connect to, for the TCP route exposed
+// by the service.
+func tcpEndpoint(service v1.Service) string {
+	port := service.Spec.Ports[0]
+
+	if service.Spec.Type == v1.ServiceTypeNodePort {
+		return fmt.Sprintf("any node, port %d", port.NodePort)
+	}
+
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		host := ingress.IP
+		if host == "" {
+			host = ingress.Hostname
+		}
+		return fmt.Sprintf("%s:%d", host, port.Port)
+	}
+
+	return "pending, no load balancer address yet"
+}
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	v1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TCP routes", func() {
+	appRef := models.NewAppRef("broker", "workspace")
+
+	It("exposes the route through a load balancer by default", func() {
+		route := routes.FromString("tcp://1883?allow=10.0.0.0/8")
+		service := newTCPService(appRef, "admin", route)
+
+		Expect(service.Namespace).To(Equal("workspace"))
+		Expect(service.Spec.Type).To(Equal(v1.ServiceTypeLoadBalancer))
+		Expect(service.Spec.Ports[0].Port).To(Equal(int32(1883)))
+		Expect(service.Spec.Ports[0].TargetPort.IntValue()).To(Equal(1883))
+		Expect(service.Spec.LoadBalancerSourceRanges).To(Equal([]string{"10.0.0.0/8"}))
+		Expect(tcpRouteFromService(*service)).To(Equal(route))
+	})
+
+	It("exposes the route through a node port on request", func() {
+		route := routes.FromString("tcp://1883?expose=nodeport&target=1884")
+		service := newTCPService(appRef, "admin", route)
+
+		Expect(service.Spec.Type).To(Equal(v1.ServiceTypeNodePort))
+		Expect(service.Spec.Ports[0].TargetPort.IntValue()).To(Equal(1884))
+		Expect(tcpRouteFromService(*service)).To(Equal(route))
+
+		service.Spec.Ports[0].NodePort = 31883
+		Expect(tcpEndpoint(*service)).To(Equal("any node, port 31883"))
+	})
+
+	It("reports the load balancer address as endpoint", func() {
+		service := newTCPService(appRef, "admin", routes.FromString("tcp://1883"))
+		Expect(tcpEndpoint(*service)).To(Equal("pending, no load balancer address yet"))
+
+		service.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: "172.18.0.2"}}
+		Expect(tcpEndpoint(*service)).To(Equal("172.18.0.2:1883"))
+	})
+
+	It("ignores tcp routes in conflicts", func() {
+		owners := map[string]models.AppRef{
+			"tcp://1883": models.NewAppRef("other", "workspace"),
+		}
+		Expect(routeConflicts(models.NewAppRef("app", "workspace"), owners, []string{"tcp://1883"})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,254 @@
+package application
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// BoundVolumes returns the volumes bound to the application, and their mount paths.
+func BoundVolumes(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (models.VolumeMounts, error) {
+	volSecret, err := volLoad(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.VolumeMounts{}
+	for name, path := range volSecret.Data {
+		result[name] = string(path)
+	}
+
+	return result, nil
+}
+
+// BoundVolumesSet replaces or adds the specified volumes to the named application.
+// When the function returns the volume set will be extended. Adding a known volume
+// changes its mount path.
+func BoundVolumesSet(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, mounts models.VolumeMounts, replace bool) error {
+	return volUpdate(ctx, cluster, appRef, func(volSecret *v1.Secret) {
+		// Replacement is adding to a clear structure
+		if replace {
+			volSecret.Data = make(map[string][]byte)
+		}
+		for name, path := range mounts {
+			volSecret.Data[name] = []byte(path)
+		}
+	})
+}
+
+// BoundVolumesUnset removes the specified volume from the named application.
+// When the function returns the volume set will be shrunk.
+// Removing an unknown volume is a no-op.
+func BoundVolumesUnset(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, volumeName string) error {
+	return volUpdate(ctx, cluster, appRef, func(volSecret *v1.Secret) {
+		delete(volSecret.Data, volumeName)
+	})
+}
+
+// BoundAppsNamesForVolume returns the names of the applications of the namespace the
+// named volume is bound to.
+func BoundAppsNamesForVolume(ctx context.Context, cluster *kubernetes.Cluster, namespace, volumeName string) ([]string, error) {
+	result := []string{}
+
+	// locate volume bindings managed by epinio applications
+	selector := EpinioApplicationAreaLabel + "=volume"
+	selector += ",app.kubernetes.io/component=application"
+	selector += ",app.kubernetes.io/managed-by=epinio"
+
+	appBindings, err := cluster.Kubectl.CoreV1().Secrets(namespace).List(ctx,
+		metav1.ListOptions{
+			LabelSelector: selector,
+		})
+	if err != nil {
+		return result, err
+	}
+
+	for _, binding := range appBindings.Items {
+		if _, ok := binding.Data[volumeName]; ok {
+			result = append(result, binding.ObjectMeta.Labels["app.kubernetes.io/name"])
+		}
+	}
+
+	return result, nil
+}
+
+// VolumesArray returns the pod volumes for the bound volumes. Each pod volume
+// is named like the claim it references.
+func VolumesArray(mounts models.VolumeMounts) []v1.Volume {
+	volumes := []v1.Volume{}
+
+	for _, name := range mounts.Names() {
+		claimName := names.VolumeName(name)
+		volumes = append(volumes, v1.Volume{
+			Name: claimName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+				},
+			},
+		})
+	}
+
+	return volumes
+}
+
+// VolumeMountsArray returns the container mounts for the bound volumes.
+func VolumeMountsArray(mounts models.VolumeMounts) []v1.VolumeMount {
+	result := []v1.VolumeMount{}
+
+	for _, name := range mounts.Names() {
+		result = append(result, v1.VolumeMount{
+			Name:      names.VolumeName(name),
+			MountPath: mounts[name],
+		})
+	}
+
+	return result
+}
+
+// BoundVolumesChange imports the currently bound volumes into the deployment.
+// The pod volumes backed by claims are replaced wholesale, leaving all others,
+// i.e. those of the bound services, alone.
+// A deployment with bound volumes is updated by recreating its pods, as the
+// ReadWriteOnce claims cannot be shared between the old and the new pods.
+func (a *Workload) BoundVolumesChange(ctx context.Context, mounts models.VolumeMounts) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Retrieve the latest version of Deployment before attempting update
+		// RetryOnConflict uses exponential backoff to avoid exhausting the apiserver
+		deployment, err := a.Deployment(ctx)
+		if err != nil {
+			return err
+		}
+
+		changeVolumes(deployment, mounts)
+
+		_, err = a.cluster.Kubectl.AppsV1().Deployments(a.app.Namespace).Update(
+			ctx, deployment, metav1.UpdateOptions{})
+
+		return err
+	})
+}
+
+// changeVolumes is a helper for BoundVolumesChange. It replaces the claim-backed
+// volumes and mounts of the deployment, and sets its update strategy.
+func changeVolumes(deployment *appsv1.Deployment, mounts models.VolumeMounts) {
+	claimVolumes := map[string]struct{}{}
+	newVolumes := []v1.Volume{}
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claimVolumes[volume.Name] = struct{}{}
+			continue
+		}
+		newVolumes = append(newVolumes, volume)
+	}
+
+	// TODO: Iterate over containers and find the one matching the app name
+	newMounts := []v1.VolumeMount{}
+	for _, mount := range deployment.Spec.Template.Spec.Containers[0].VolumeMounts {
+		if _, ok := claimVolumes[mount.Name]; ok {
+			continue
+		}
+		newMounts = append(newMounts, mount)
+	}
+
+	deployment.Spec.Template.Spec.Volumes = append(newVolumes, VolumesArray(mounts)...)
+	deployment.Spec.Template.Spec.Containers[0].VolumeMounts = append(newMounts, VolumeMountsArray(mounts)...)
+	deployment.Spec.Strategy = DeploymentStrategy(mounts)
+}
+
+// DeploymentStrategy returns the update strategy of a deployment with the bound
+// volumes. Rolling updates are used when no volumes are bound.
+func DeploymentStrategy(mounts models.VolumeMounts) appsv1.DeploymentStrategy {
+	if len(mounts) == 0 {
+		return appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+		}
+	}
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RecreateDeploymentStrategyType,
+	}
+}
+
+// volUpdate is a helper for the public functions. It encapsulates the read/modify/write cycle
+// necessary to update the application's kube resource holding the application's bound volumes.
+func volUpdate(ctx context.Context, cluster *kubernetes.Cluster,
+	appRef models.AppRef, modifyBoundVolumes func(*v1.Secret)) error {
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		volSecret, err := volLoad(ctx, cluster, appRef)
+		if err != nil {
+			return err
+		}
+
+		if volSecret.Data == nil {
+			volSecret.Data = make(map[string][]byte)
+		}
+
+		modifyBoundVolumes(volSecret)
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(appRef.Namespace).Update(
+			ctx, volSecret, metav1.UpdateOptions{})
+
+		return err
+	})
+}
+
+// volLoad locates and returns the kube secret storing the referenced application's bound volumes,
+// and their mount paths. If necessary it creates that secret.
+func volLoad(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (*v1.Secret, error) {
+	secretName := appRef.MakeVolumeSecretName()
+
+	volSecret, err := cluster.GetSecret(ctx, appRef.Namespace, secretName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		// Error is `Not Found`. Create the secret.
+
+		app, err := Get(ctx, cluster, appRef)
+		if err != nil {
+			// Should not happen. The application was validated to exist already somewhere
+			// by this function's callers.
+			return nil, err
+		}
+
+		owner := metav1.OwnerReference{
+			APIVersion: app.GetAPIVersion(),
+			Kind:       app.GetKind(),
+			Name:       app.GetName(),
+			UID:        app.GetUID(),
+		}
+
+		volSecret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: appRef.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					owner,
+				},
+				Labels: map[string]string{
+					"app.kubernetes.io/name":       appRef.Name,
+					"app.kubernetes.io/part-of":    appRef.Namespace,
+					"app.kubernetes.io/managed-by": "epinio",
+					"app.kubernetes.io/component":  "application",
+					EpinioApplicationAreaLabel:     "volume",
+				},
+			},
+		}
+		err = cluster.CreateSecret(ctx, appRef.Namespace, *volSecret)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return volSecret, nil
+}
@@ -0,0 +1,79 @@
+package application
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bound volumes", func() {
+	serviceVolume := v1.Volume{
+		Name: "mydb",
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: "mydb-binding"},
+		},
+	}
+	serviceMount := v1.VolumeMount{Name: "mydb", ReadOnly: true, MountPath: "/services/mydb"}
+
+	claimVolume := func(name string) v1.Volume {
+		return v1.Volume{
+			Name: name,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: name},
+			},
+		}
+	}
+
+	deployment := func() *appsv1.Deployment {
+		d := &appsv1.Deployment{}
+		d.Spec.Template.Spec.Volumes = []v1.Volume{serviceVolume, claimVolume("v-old")}
+		d.Spec.Template.Spec.Containers = []v1.Container{{
+			Name: "myapp",
+			VolumeMounts: []v1.VolumeMount{
+				serviceMount,
+				{Name: "v-old", MountPath: "/old"},
+			},
+		}}
+		return d
+	}
+
+	Describe("VolumesArray", func() {
+		It("references the claims of the volumes, ordered by name", func() {
+			mounts := models.VolumeMounts{"uploads": "/app/uploads", "cache": "/cache"}
+
+			Expect(VolumesArray(mounts)).To(Equal([]v1.Volume{claimVolume("v-cache"), claimVolume("v-uploads")}))
+			Expect(VolumeMountsArray(mounts)).To(Equal([]v1.VolumeMount{
+				{Name: "v-cache", MountPath: "/cache"},
+				{Name: "v-uploads", MountPath: "/app/uploads"},
+			}))
+		})
+	})
+
+	Describe("changeVolumes", func() {
+		It("replaces the volumes, keeping the bound services", func() {
+			d := deployment()
+
+			changeVolumes(d, models.VolumeMounts{"uploads": "/app/uploads"})
+
+			Expect(d.Spec.Template.Spec.Volumes).To(Equal([]v1.Volume{serviceVolume, claimVolume("v-uploads")}))
+			Expect(d.Spec.Template.Spec.Containers[0].VolumeMounts).To(Equal([]v1.VolumeMount{
+				serviceMount,
+				{Name: "v-uploads", MountPath: "/app/uploads"},
+			}))
+			Expect(d.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+		})
+
+		It("goes back to rolling updates without volumes", func() {
+			d := deployment()
+
+			changeVolumes(d, models.VolumeMounts{})
+
+			Expect(d.Spec.Template.Spec.Volumes).To(Equal([]v1.Volume{serviceVolume}))
+			Expect(d.Spec.Template.Spec.Containers[0].VolumeMounts).To(Equal([]v1.VolumeMount{serviceMount}))
+			Expect(d.Spec.Strategy.Type).To(Equal(appsv1.RollingUpdateDeploymentStrategyType))
+		})
+	})
+})
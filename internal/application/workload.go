@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/namespaces"
 	"github.com/epinio/epinio/internal/services"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 
 	pkgerrors "github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	resource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -201,8 +203,20 @@ func (a *Workload) BoundServicesChange(ctx context.Context, userName string, old
 // deployment. This requires only the names of the currently existing
 // environment variables, not the values, as the import is internally
 // done as pod env specifications using secret key references.
-func (a *Workload) EnvironmentChange(ctx context.Context, varNames []string) error {
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+// The default variables of the namespace are imported as well, for
+// all names not set by the application itself.
+// The result reports if the deployment changed, i.e. a rollout of the
+// application was started. An unchanged set of names leaves the deployment
+// as is. Changed values then require a Restart.
+func (a *Workload) EnvironmentChange(ctx context.Context, varNames []string) (bool, error) {
+	defaults, err := namespaces.Environment(ctx, a.cluster, a.app.Namespace)
+	if err != nil {
+		return false, err
+	}
+	defaultList := defaults.Without(varNames).List()
+
+	changed := false
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		// Retrieve the latest version of Deployment before attempting update
 		// RetryOnConflict uses exponential backoff to avoid exhausting the apiserver
 		deployment, err := a.Deployment(ctx)
@@ -212,8 +226,8 @@ func (a *Workload) EnvironmentChange(ctx context.Context, varNames []string) err
 
 		evSecretName := a.app.MakeEnvSecretName()
 
-		// 1. Remove all the old EVs referencing the app's EV secret.
-		// 2. Add entries for the new set of EV's (S.a varNames).
+		// 1. Remove all the old EVs referencing the app's or namespace's EV secret.
+		// 2. Add entries for the new set of EV's (S.a varNames), and namespace defaults.
 		// 3. Replace container spec
 		//
 		// Note: While 1+2 could be optimized to only remove entries of
@@ -228,10 +242,11 @@ func (a *Workload) EnvironmentChange(ctx context.Context, varNames []string) err
 		newEnvironment := []corev1.EnvVar{}
 
 		for _, ev := range deployment.Spec.Template.Spec.Containers[0].Env {
-			// Drop EV if pulled from EV secret of the app
+			// Drop EV if pulled from EV secret of the app, or namespace
 			if ev.ValueFrom != nil &&
 				ev.ValueFrom.SecretKeyRef != nil &&
-				ev.ValueFrom.SecretKeyRef.Name == evSecretName {
+				(ev.ValueFrom.SecretKeyRef.Name == evSecretName ||
+					ev.ValueFrom.SecretKeyRef.Name == namespaces.EnvSecretName) {
 				continue
 			}
 			// Keep everything else.
@@ -252,13 +267,25 @@ func (a *Workload) EnvironmentChange(ctx context.Context, varNames []string) err
 			})
 		}
 
+		newEnvironment = append(newEnvironment, defaultList.ToSecretRefArray(namespaces.EnvSecretName)...)
+
+		if equality.Semantic.DeepEqual(deployment.Spec.Template.Spec.Containers[0].Env, newEnvironment) {
+			return nil
+		}
+
 		deployment.Spec.Template.Spec.Containers[0].Env = newEnvironment
 
 		_, err = a.cluster.Kubectl.AppsV1().Deployments(a.app.Namespace).Update(
 			ctx, deployment, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
 
-		return err
+		changed = true
+		return nil
 	})
+
+	return changed, err
 }
 
 // Scale changes the number of instances (replicas) for the
@@ -390,12 +417,31 @@ func (a *Workload) Get(ctx context.Context) (*models.AppDeployment, error) {
 
 	stageID := deployment.Spec.Template.ObjectMeta.Labels["epinio.suse.org/stage-id"]
 	username := deployment.Spec.Template.ObjectMeta.Labels["app.kubernetes.io/created-by"]
+	imageURL := deployment.Spec.Template.Spec.Containers[0].Image
 
 	routes, err := ListRoutes(ctx, a.cluster, a.app)
 	if err != nil {
 		routes = []string{err.Error()}
 	}
 
+	// Certificate details are informational. Failure to get them is not fatal.
+	certificates, err := RouteCertificates(ctx, a.cluster, a.app)
+	if err != nil {
+		certificates = nil
+	}
+
+	// Endpoints are informational. Failure to get them is not fatal.
+	endpoints, err := RouteEndpoints(ctx, a.cluster, a.app)
+	if err != nil {
+		endpoints = nil
+	}
+
+	// The internal url is informational. Failure to get it is not fatal.
+	internalURL, err := InternalURL(ctx, a.cluster, a.app)
+	if err != nil {
+		internalURL = ""
+	}
+
 	replicas, err := a.Replicas(ctx)
 	if err != nil {
 		status = pkgerrors.Wrap(err, "failed to get replica details").Error()
@@ -407,8 +453,12 @@ func (a *Workload) Get(ctx context.Context) (*models.AppDeployment, error) {
 		Replicas:        replicas,
 		Username:        username,
 		StageID:         stageID,
+		ImageURL:        imageURL,
 		Status:          status,
 		Routes:          routes,
+		Certificates:    certificates,
+		Endpoints:       endpoints,
+		InternalURL:     internalURL,
 		DesiredReplicas: desiredReplicas,
 		ReadyReplicas:   readyReplicas,
 	}, nil
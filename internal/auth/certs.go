@@ -89,6 +89,22 @@ func CreateCertificate(
 	return nil
 }
 
+// DeleteCertificate removes the named certificate resource. A missing
+// certificate is not an error.
+func DeleteCertificate(ctx context.Context, cluster *kubernetes.Cluster, namespace, name string) error {
+	client, err := cluster.ClientCertificate()
+	if err != nil {
+		return err
+	}
+
+	err = client.Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
 // newCertificate creates a proper certificate resource from the
 // specified parameters. The result is suitable for upload to the
 // cluster.
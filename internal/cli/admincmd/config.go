@@ -38,6 +38,10 @@ func New() (*Admin, error) {
 		return nil, err
 	}
 
+	if err := termui.ValidateOutputFormat(termui.OutputFormat()); err != nil {
+		return nil, err
+	}
+
 	uiUI := termui.NewUI()
 
 	logger := tracelog.NewLogger().WithName("EpinioConfig").V(3)
@@ -0,0 +1,194 @@
+package admincmd
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/epinio/epinio/internal/cli/config"
+	"github.com/pkg/errors"
+)
+
+// ContextInfo describes a context of the configuration, without its secrets.
+type ContextInfo struct {
+	Name         string `json:"name"`
+	Current      bool   `json:"current"`
+	API          string `json:"api"`
+	WSS          string `json:"wss"`
+	User         string `json:"user"`
+	Namespace    string `json:"namespace"`
+	Certificates bool   `json:"certificates"`
+}
+
+// ContextOptions are the settings of a new context. The certificates are
+// read from the CertFile, if set.
+type ContextOptions struct {
+	API       string
+	WSS       string
+	User      string
+	Password  string
+	Namespace string
+	CertFile  string
+}
+
+// Contexts lists the contexts of the configuration
+func (a *Admin) Contexts() error {
+	log := a.Log.WithName("Contexts")
+	log.Info("start")
+	defer log.Info("return")
+
+	a.ui.Note().
+		WithStringValue("Config", a.Config.Location).
+		Msg("Listing contexts")
+
+	contexts := []ContextInfo{}
+	for _, name := range a.Config.ContextNames() {
+		context := a.Config.Contexts[name]
+		if name == a.Config.ActiveContext() {
+			// May carry overrides from the environment
+			context = a.Config.Context
+		}
+
+		contexts = append(contexts, ContextInfo{
+			Name:         name,
+			Current:      name == a.Config.Current,
+			API:          context.API,
+			WSS:          context.WSS,
+			User:         context.User,
+			Namespace:    context.Namespace,
+			Certificates: context.Certs != "",
+		})
+	}
+
+	if a.ui.MachineReadable() {
+		return a.ui.Result(contexts)
+	}
+
+	msg := a.ui.Success().WithTable("", "Name", "API", "User", "Namespace")
+
+	for _, context := range contexts {
+		current := ""
+		if context.Current {
+			current = "*"
+		}
+		msg = msg.WithTableRow(current, context.Name, context.API, context.User, context.Namespace)
+	}
+
+	msg.Msg("Epinio Contexts:")
+
+	return nil
+}
+
+// ContextUse makes the named context the current one
+func (a *Admin) ContextUse(name string) error {
+	log := a.Log.WithName("ContextUse").WithValues("Context", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	a.ui.Note().
+		WithStringValue("Name", name).
+		Msg("Switching context...")
+
+	if err := a.Config.UseContext(name); err != nil {
+		return err
+	}
+	if err := a.Config.Save(); err != nil {
+		return errors.Wrap(err, "failed to save configuration")
+	}
+
+	a.ui.Success().
+		WithStringValue("API", a.Config.API).
+		WithStringValue("Namespace", a.Config.Namespace).
+		Msg("Context switched.")
+
+	return nil
+}
+
+// ContextAdd adds a context to the configuration. Without an API location it
+// can be completed by `epinio config update` from a cluster.
+func (a *Admin) ContextAdd(name string, options ContextOptions) error {
+	log := a.Log.WithName("ContextAdd").WithValues("Context", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	a.ui.Note().
+		WithStringValue("Name", name).
+		WithStringValue("API", options.API).
+		WithStringValue("User", options.User).
+		Msg("Adding context...")
+
+	context := config.Context{
+		Namespace: options.Namespace,
+		User:      options.User,
+		Password:  options.Password,
+		API:       options.API,
+		WSS:       options.WSS,
+	}
+
+	if context.WSS == "" && strings.HasPrefix(context.API, "https://") {
+		context.WSS = "wss://" + strings.TrimPrefix(context.API, "https://")
+	}
+
+	if options.CertFile != "" {
+		certs, err := ioutil.ReadFile(options.CertFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read certificates")
+		}
+		context.Certs = string(certs)
+	}
+
+	if err := a.Config.AddContext(name, context); err != nil {
+		return err
+	}
+	if err := a.Config.Save(); err != nil {
+		return errors.Wrap(err, "failed to save configuration")
+	}
+
+	a.ui.Success().Msg("Context added.")
+
+	return nil
+}
+
+// ContextRemove removes the named context from the configuration
+func (a *Admin) ContextRemove(name string) error {
+	log := a.Log.WithName("ContextRemove").WithValues("Context", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	a.ui.Note().
+		WithStringValue("Name", name).
+		Msg("Removing context...")
+
+	if err := a.Config.RemoveContext(name); err != nil {
+		return err
+	}
+	if err := a.Config.Save(); err != nil {
+		return errors.Wrap(err, "failed to save configuration")
+	}
+
+	a.ui.Success().Msg("Context removed.")
+
+	return nil
+}
+
+// ContextRename renames a context of the configuration
+func (a *Admin) ContextRename(from, to string) error {
+	log := a.Log.WithName("ContextRename").WithValues("Context", from, "Target", to)
+	log.Info("start")
+	defer log.Info("return")
+
+	a.ui.Note().
+		WithStringValue("Name", from).
+		WithStringValue("New Name", to).
+		Msg("Renaming context...")
+
+	if err := a.Config.RenameContext(from, to); err != nil {
+		return err
+	}
+	if err := a.Config.Save(); err != nil {
+		return errors.Wrap(err, "failed to save configuration")
+	}
+
+	a.ui.Success().Msg("Context renamed.")
+
+	return nil
+}
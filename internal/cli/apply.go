@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/epinio/epinio/internal/manifest"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	CmdApply.Flags().StringP("file", "f", "", "Path to the stack file declaring the services and applications")
+	CmdApply.Flags().Bool("prune", false, "Delete the services and applications of the namespace not declared by the stack")
+	CmdApply.Flags().Bool("dry-run", false, "Show the plan without executing it")
+	// nolint:errcheck // Unable to handle error in init block
+	CmdApply.MarkFlagRequired("file")
+}
+
+// CmdApply implements the command: epinio apply
+var CmdApply = &cobra.Command{
+	Use:   "apply -f PATH_TO_STACK_FILE",
+	Short: "Apply the services and applications declared in the specified stack file",
+	Long: `Bring the targeted namespace to the state declared by the stack file.
+
+The stack file declares services, like "service create", and applications, like
+the manifest of "push". Application sources without origin are taken from the
+directory of the stack file.
+
+The plan of the changes is shown before it is executed. Services are created
+and updated before the applications binding them. With --prune the services
+and applications not declared by the stack are deleted.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		stackPath, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --file")
+		}
+		prune, err := cmd.Flags().GetBool("prune")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --prune")
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --dry-run")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		stack, err := manifest.GetStack(stackPath)
+		if err != nil {
+			cmd.SilenceUsage = false
+			return errors.Wrap(err, "Stack error")
+		}
+
+		err = client.Apply(cmd.Context(), stack, prune, dryRun)
+		if err != nil {
+			return errors.Wrap(err, "error applying stack")
+		}
+
+		return nil
+	},
+}
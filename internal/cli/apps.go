@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/epinio/epinio/internal/cli/usercmd"
 	"github.com/epinio/epinio/internal/manifest"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -20,14 +23,18 @@ var CmdApp = &cobra.Command{
 
 func init() {
 	CmdAppList.Flags().Bool("all", false, "list all applications")
+	CmdAppEvents.Flags().Bool("follow", false, "follow the events of the application")
 	CmdAppLogs.Flags().Bool("follow", false, "follow the logs of the application")
 	CmdAppLogs.Flags().Bool("staging", false, "show the staging logs of the application")
+	logOptions(CmdAppLogs)
 	CmdAppExec.Flags().StringP("instance", "i", "", "The name of the instance to shell to")
 	CmdAppPortForward.Flags().StringSliceVar(&portForwardAddress, "address", []string{"localhost"}, "Addresses to listen on (comma separated). Only accepts IP addresses or localhost as a value. When localhost is supplied, kubectl will try to bind on both 127.0.0.1 and ::1 and will fail if neither of these addresses are available to bind.")
 	CmdAppPortForward.Flags().StringVarP(&portForwardInstance, "instance", "i", "", "The name of the instance to shell to")
 
 	routeOption(CmdAppCreate)
 	routeOption(CmdAppUpdate)
+	internalOption(CmdAppCreate)
+	internalOption(CmdAppUpdate)
 	bindOption(CmdAppCreate)
 	bindOption(CmdAppUpdate)
 	envOption(CmdAppCreate)
@@ -36,7 +43,10 @@ func init() {
 	instancesOption(CmdAppUpdate)
 
 	CmdApp.AddCommand(CmdAppCreate)
-	CmdApp.AddCommand(CmdAppEnv) // See env.go for implementation
+	CmdApp.AddCommand(CmdAppDiff)
+	CmdApp.AddCommand(CmdAppDrain) // See drains.go for implementation
+	CmdApp.AddCommand(CmdAppEnv)   // See env.go for implementation
+	CmdApp.AddCommand(CmdAppEvents)
 	CmdApp.AddCommand(CmdAppList)
 	CmdApp.AddCommand(CmdAppLogs)
 	CmdApp.AddCommand(CmdAppExec)
@@ -46,7 +56,8 @@ func init() {
 	CmdApp.AddCommand(CmdAppShow)
 	CmdApp.AddCommand(CmdAppUpdate)
 	CmdApp.AddCommand(CmdAppDelete)
-	CmdApp.AddCommand(CmdAppPush) // See push.go for implementation
+	CmdApp.AddCommand(CmdAppPush)  // See push.go for implementation
+	CmdApp.AddCommand(CmdAppRoute) // See routes.go for implementation
 }
 
 // CmdAppList implements the command: epinio app list
@@ -126,6 +137,37 @@ var CmdAppShow = &cobra.Command{
 	},
 }
 
+// CmdAppEvents implements the command: epinio apps events
+var CmdAppEvents = &cobra.Command{
+	Use:   "events NAME",
+	Short: "Show the events of the application",
+	Long: `Show the kubernetes events of the deployment, replicasets, instances, and ingresses
+of the named application, and the state of its instances. For each instance the last
+termination of the application is shown, with its reason and exit code. Instances
+killed for exceeding their memory limit are called out.
+
+With --follow new events and terminations are shown as they happen, until interrupted.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		follow, err := cmd.Flags().GetBool("follow")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --follow")
+		}
+
+		err = client.AppEvents(cmd.Context(), args[0], follow)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing app events")
+	},
+}
+
 // CmdAppLogs implements the command: epinio apps logs
 var CmdAppLogs = &cobra.Command{
 	Use:   "logs NAME",
@@ -149,6 +191,11 @@ var CmdAppLogs = &cobra.Command{
 			return errors.Wrap(err, "error reading option --staging")
 		}
 
+		params, output, err := logParameters(cmd)
+		if err != nil {
+			return err
+		}
+
 		stageID, err := client.AppStageID(args[0])
 		if err != nil {
 			return errors.Wrap(err, "error checking app")
@@ -159,7 +206,7 @@ var CmdAppLogs = &cobra.Command{
 			stageID = ""
 		}
 
-		err = client.AppLogs(args[0], stageID, follow, nil)
+		err = client.AppLogs(args[0], stageID, follow, params, output, nil)
 		// Note: errors.Wrap (nil, "...") == nil
 		return errors.Wrap(err, "error streaming application logs")
 	},
@@ -250,6 +297,69 @@ var CmdAppUpdate = &cobra.Command{
 	},
 }
 
+// CmdAppDiff implements the command: epinio apps diff
+var CmdAppDiff = &cobra.Command{
+	Use:   "diff [PATH_TO_APPLICATION_MANIFEST]",
+	Short: "Compare an application manifest with the deployed application",
+	Long: `Compare the manifest (default: epinio.yml in the working directory) with the
+deployed application of the same name, in the targeted namespace.
+
+Compared are the instances, environment variables, bound services, routes,
+builder and origin. The values of environment variables are masked. Parts left
+out of the manifest are not compared, nor are the paths of local sources, which
+depend on the checkout.
+
+The exit code is 0 when the application matches the manifest, 1 when it
+drifted from it, and different from both on errors.`,
+	Args: cobra.RangeArgs(0, 1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return errors.Wrap(err, "working directory not accessible")
+		}
+
+		manifestPath := filepath.Join(wd, "epinio.yml")
+		if len(args) == 1 {
+			manifestPath = args[0]
+		}
+
+		m, err := manifest.Get(manifestPath)
+		if err != nil {
+			cmd.SilenceUsage = false
+			return errors.Wrap(err, "Manifest error")
+		}
+
+		if m.Name == "" {
+			cmd.SilenceUsage = false
+			return errors.New("Name required, not found in manifest")
+		}
+
+		// Same fallback as push: Without origin the sources are in the working directory
+		if m.Origin.Kind == models.OriginNone {
+			m.Origin.Kind = models.OriginPath
+			m.Origin.Path = wd
+		}
+
+		drift, err := client.AppDiff(m)
+		if err != nil {
+			return errors.Wrap(err, "error comparing app with manifest")
+		}
+
+		if drift {
+			os.Exit(1)
+		}
+
+		return nil
+	},
+}
+
 // CmdAppManifest implements the command: epinio apps manifest
 var CmdAppManifest = &cobra.Command{
 	Use:               "manifest NAME MANIFESTPATH",
@@ -3,7 +3,9 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/epinio/epinio/internal/cli/config"
 	"github.com/epinio/epinio/internal/cli/usercmd"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/kubernetes"
@@ -76,3 +78,25 @@ func matchingNamespaceFinder(cmd *cobra.Command, args []string, toComplete strin
 
 	return matches, cobra.ShellCompDirectiveNoFileComp
 }
+
+// matchingContextFinder returns the names of the contexts matching the prefix
+// for completion. Only the first argument is completed.
+func matchingContextFinder(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	theConfig, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	matches := []string{}
+	for _, name := range theConfig.ContextNames() {
+		if strings.HasPrefix(name, toComplete) {
+			matches = append(matches, name)
+		}
+	}
+
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
@@ -96,6 +96,19 @@ var CmdConfigShow = &cobra.Command{
 
 		ui.Note().WithStringValue("Config", theConfig.Location).Msg("Show Configuration")
 
+		if ui.MachineReadable() {
+			return ui.Result(map[string]interface{}{
+				"colors":       theConfig.Colors,
+				"context":      theConfig.ActiveContext(),
+				"namespace":    theConfig.Namespace,
+				"user":         theConfig.User,
+				"password":     theConfig.Password,
+				"api":          theConfig.API,
+				"wss":          theConfig.WSS,
+				"certificates": theConfig.Certs != "",
+			})
+		}
+
 		certInfo := color.CyanString("None defined")
 		if theConfig.Certs != "" {
 			certInfo = color.BlueString("Present")
@@ -104,6 +117,7 @@ var CmdConfigShow = &cobra.Command{
 		ui.Success().
 			WithTable("Key", "Value").
 			WithTableRow("Colorized Output", color.MagentaString("%t", theConfig.Colors)).
+			WithTableRow("Context", color.CyanString(theConfig.ActiveContext())).
 			WithTableRow("Current Namespace", color.CyanString(theConfig.Namespace)).
 			WithTableRow("API User Name", color.BlueString(theConfig.User)).
 			WithTableRow("API Password", color.BlueString(theConfig.Password)).
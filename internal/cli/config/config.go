@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -13,6 +14,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/epinio/epinio/helpers/tracelog"
 	"github.com/epinio/epinio/internal/auth"
@@ -22,20 +24,39 @@ var (
 	defaultConfigFilePath = os.ExpandEnv("${HOME}/.config/epinio/config.yaml")
 )
 
+const (
+	// DefaultContext is the name of the context holding the settings of a
+	// configuration file written before contexts existed.
+	DefaultContext = "default"
+
+	defaultNamespace = "workspace"
+)
+
+// Context holds the location of an Epinio installation, the credentials for
+// it, and the namespace targeted in it.
+type Context struct {
+	Namespace string `mapstructure:"namespace" yaml:"namespace"`
+	User      string `mapstructure:"user" yaml:"user"`
+	Password  string `mapstructure:"pass" yaml:"pass"`
+	API       string `mapstructure:"api" yaml:"api"`
+	WSS       string `mapstructure:"wss" yaml:"wss"`
+	Certs     string `mapstructure:"certs" yaml:"certs"`
+}
+
 // Config represents a epinio config
 type Config struct {
-	Namespace string `mapstructure:"namespace"`
-	User      string `mapstructure:"user"`
-	Password  string `mapstructure:"pass"`
-	API       string `mapstructure:"api"`
-	WSS       string `mapstructure:"wss"`
-	Certs     string `mapstructure:"certs"`
-	Colors    bool   `mapstructure:"colors"`
+	// The active context. At the top level of the file are the settings
+	// written before contexts existed.
+	Context `mapstructure:",squash"`
+
+	Colors   bool               `mapstructure:"colors"`
+	Current  string             `mapstructure:"current-context"` // Name of the current context
+	Contexts map[string]Context `mapstructure:"contexts"`
 
 	Location string // Origin of data, file which was loaded
 
-	v   *viper.Viper
-	log logr.Logger
+	active string // Name of the active context, i.e. the current one, or the one chosen by --context
+	log    logr.Logger
 }
 
 // DefaultLocation returns the standard location for the configuration file
@@ -89,9 +110,13 @@ func LoadFrom(file string) (*Config, error) {
 		return nil, errors.Wrap(err, "failed to unmarshal config file")
 	}
 
-	cfg.v = v
 	cfg.Location = file
 
+	err = cfg.activate(viper.GetString("context"))
+	if err != nil {
+		return nil, err
+	}
+
 	if cfg.Certs != "" {
 		auth.ExtendLocalTrust(cfg.Certs)
 	}
@@ -126,33 +151,173 @@ func LoadFrom(file string) (*Config, error) {
 	return cfg, nil
 }
 
+// activate makes the named context the active one, or the current context if
+// no name is given. A configuration without contexts is turned into the
+// default context. The environment overrides the settings of the active
+// context.
+func (c *Config) activate(name string) error {
+	if len(c.Contexts) == 0 {
+		c.Contexts = map[string]Context{DefaultContext: c.Context}
+		c.Current = DefaultContext
+	}
+
+	if name == "" {
+		name = c.Current
+	}
+	if name == "" {
+		return errors.New("no current context, please select one with epinio context use")
+	}
+
+	context, ok := c.Contexts[name]
+	if !ok {
+		return errors.Errorf("context '%s' does not exist", name)
+	}
+
+	for key, value := range map[string]*string{
+		"EPINIO_NAMESPACE": &context.Namespace,
+		"EPINIO_USER":      &context.User,
+		"EPINIO_PASS":      &context.Password,
+		"EPINIO_API":       &context.API,
+		"EPINIO_WSS":       &context.WSS,
+		"EPINIO_CERTS":     &context.Certs,
+	} {
+		if env, ok := os.LookupEnv(key); ok {
+			*value = env
+		}
+	}
+	if context.Namespace == "" {
+		context.Namespace = defaultNamespace
+	}
+
+	c.active = name
+	c.Context = context
+	return nil
+}
+
+// ActiveContext returns the name of the active context.
+func (c *Config) ActiveContext() string {
+	return c.active
+}
+
+// ContextNames returns the names of all contexts, sorted.
+func (c *Config) ContextNames() []string {
+	names := []string{}
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddContext adds a new context.
+func (c *Config) AddContext(name string, context Context) error {
+	if err := validateContextName(name); err != nil {
+		return err
+	}
+	if _, ok := c.Contexts[name]; ok {
+		return errors.Errorf("context '%s' already exists", name)
+	}
+	if context.Namespace == "" {
+		context.Namespace = defaultNamespace
+	}
+
+	c.Contexts[name] = context
+	return nil
+}
+
+// UseContext makes the named context the current and active one.
+func (c *Config) UseContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return errors.Errorf("context '%s' does not exist", name)
+	}
+
+	c.Contexts[c.active] = c.Context
+	c.Current = name
+	c.active = name
+	c.Context = c.Contexts[name]
+	return nil
+}
+
+// RemoveContext removes the named context. The current and the active
+// context cannot be removed.
+func (c *Config) RemoveContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return errors.Errorf("context '%s' does not exist", name)
+	}
+	if name == c.Current || name == c.active {
+		return errors.Errorf("context '%s' is in use, please switch to another context first", name)
+	}
+
+	delete(c.Contexts, name)
+	return nil
+}
+
+// RenameContext renames a context, keeping it current and active, if it was.
+func (c *Config) RenameContext(from, to string) error {
+	if err := validateContextName(to); err != nil {
+		return err
+	}
+	context, ok := c.Contexts[from]
+	if !ok {
+		return errors.Errorf("context '%s' does not exist", from)
+	}
+	if _, ok := c.Contexts[to]; ok {
+		return errors.Errorf("context '%s' already exists", to)
+	}
+
+	delete(c.Contexts, from)
+	c.Contexts[to] = context
+	if c.Current == from {
+		c.Current = to
+	}
+	if c.active == from {
+		c.active = to
+	}
+	return nil
+}
+
+// validateContextName checks that the name is usable as a context name. Names
+// are lowercase, as the configuration keys are case-insensitive.
+func validateContextName(name string) error {
+	errorMsgs := validation.IsDNS1123Label(name)
+	if len(errorMsgs) > 0 {
+		return fmt.Errorf("%s: %s", "context name incorrect", strings.Join(errorMsgs, "\n"))
+	}
+	return nil
+}
+
 // Generates a string representation of the configuration (for debugging)
 func (c *Config) String() string {
 	return fmt.Sprintf(
-		"namespace=(%s), user=(%s), pass=(%s), api=(%s), wss=(%s), color=(%v), @(%s)",
-		c.Namespace, c.User, c.Password, c.API, c.WSS, c.Colors, c.Location)
+		"context=(%s), namespace=(%s), user=(%s), pass=(%s), api=(%s), wss=(%s), color=(%v), @(%s)",
+		c.active, c.Namespace, c.User, c.Password, c.API, c.WSS, c.Colors, c.Location)
 }
 
-// Save saves the Epinio config
+// Save saves the Epinio config. The settings of the active context are stored
+// under its name.
 func (c *Config) Save() error {
-	c.v.Set("namespace", c.Namespace)
-	c.v.Set("user", c.User)
-	c.v.Set("pass", c.Password)
-	c.v.Set("api", c.API)
-	c.v.Set("wss", c.WSS)
-	c.v.Set("certs", c.Certs)
-	c.v.Set("colors", c.Colors)
+	c.Contexts[c.active] = c.Context
+
+	// A fresh viper drops the top-level settings of a file written before
+	// contexts existed.
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigFile(c.Location)
+
+	v.Set("colors", c.Colors)
+	v.Set("current-context", c.Current)
+	v.Set("contexts", c.Contexts)
 
-	c.log.Info("Saving", "to", c.v.ConfigFileUsed())
+	c.log.Info("Saving", "to", c.Location)
 
-	err := os.MkdirAll(filepath.Dir(c.v.ConfigFileUsed()), 0700)
+	err := os.MkdirAll(filepath.Dir(c.Location), 0700)
 	if err != nil {
-		return errors.Wrapf(err, "failed to create config dir '%s'", filepath.Dir(c.v.ConfigFileUsed()))
+		return errors.Wrapf(err, "failed to create config dir '%s'", filepath.Dir(c.Location))
 	}
 
-	err = c.v.WriteConfig()
+	err = v.WriteConfig()
 	if err != nil {
-		return errors.Wrapf(err, "failed to write config file '%s'", c.v.ConfigFileUsed())
+		return errors.Wrapf(err, "failed to write config file '%s'", c.Location)
 	}
 
 	c.log.Info("Saved", "value", c.String())
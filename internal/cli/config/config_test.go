@@ -0,0 +1,172 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/epinio/epinio/internal/cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+)
+
+var _ = Describe("Config", func() {
+	var dir, file string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "epinio-config")
+		Expect(err).ToNot(HaveOccurred())
+		file = filepath.Join(dir, "config.yaml")
+	})
+
+	AfterEach(func() {
+		viper.Set("context", "")
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	write := func(content string) {
+		Expect(ioutil.WriteFile(file, []byte(content), 0600)).To(Succeed())
+	}
+
+	Describe("LoadFrom", func() {
+		It("turns a configuration without contexts into the default context", func() {
+			write("api: https://epinio.dev\nwss: wss://epinio.dev\nuser: admin\npass: secret\nnamespace: dev\ncolors: false\n")
+
+			cfg, err := config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ActiveContext()).To(Equal(config.DefaultContext))
+			Expect(cfg.Current).To(Equal(config.DefaultContext))
+			Expect(cfg.ContextNames()).To(Equal([]string{config.DefaultContext}))
+			Expect(cfg.API).To(Equal("https://epinio.dev"))
+			Expect(cfg.Password).To(Equal("secret"))
+			Expect(cfg.Namespace).To(Equal("dev"))
+			Expect(cfg.Colors).To(BeFalse())
+		})
+
+		It("starts with an empty default context without a file", func() {
+			cfg, err := config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ActiveContext()).To(Equal(config.DefaultContext))
+			Expect(cfg.API).To(BeEmpty())
+			Expect(cfg.Namespace).To(Equal("workspace"))
+		})
+
+		It("activates the current context", func() {
+			write(`current-context: prod
+contexts:
+  dev:
+    api: https://epinio.dev
+  prod:
+    api: https://epinio.prod
+    namespace: shop
+`)
+
+			cfg, err := config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ActiveContext()).To(Equal("prod"))
+			Expect(cfg.API).To(Equal("https://epinio.prod"))
+			Expect(cfg.Namespace).To(Equal("shop"))
+		})
+
+		It("activates the context chosen by the --context option", func() {
+			write("current-context: prod\ncontexts:\n  dev:\n    api: https://epinio.dev\n  prod:\n    api: https://epinio.prod\n")
+			viper.Set("context", "dev")
+
+			cfg, err := config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ActiveContext()).To(Equal("dev"))
+			Expect(cfg.Current).To(Equal("prod"))
+			Expect(cfg.API).To(Equal("https://epinio.dev"))
+			Expect(cfg.Namespace).To(Equal("workspace"))
+		})
+
+		It("fails for an unknown context", func() {
+			write("current-context: prod\ncontexts:\n  dev:\n    api: https://epinio.dev\n")
+
+			_, err := config.LoadFrom(file)
+			Expect(err).To(MatchError("context 'prod' does not exist"))
+		})
+	})
+
+	Describe("Save", func() {
+		It("stores the settings of the active context under its name", func() {
+			write("api: https://epinio.dev\nnamespace: dev\n")
+
+			cfg, err := config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+			cfg.Namespace = "other"
+			Expect(cfg.AddContext("prod", config.Context{API: "https://epinio.prod"})).To(Succeed())
+			Expect(cfg.Save()).To(Succeed())
+
+			content, err := ioutil.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).ToNot(ContainSubstring("\napi:"))
+
+			cfg, err = config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ContextNames()).To(Equal([]string{config.DefaultContext, "prod"}))
+			Expect(cfg.ActiveContext()).To(Equal(config.DefaultContext))
+			Expect(cfg.API).To(Equal("https://epinio.dev"))
+			Expect(cfg.Namespace).To(Equal("other"))
+			Expect(cfg.Contexts["prod"].Namespace).To(Equal("workspace"))
+		})
+
+		It("keeps the current context when another one is active", func() {
+			write("current-context: prod\ncontexts:\n  dev:\n    api: https://epinio.dev\n  prod:\n    api: https://epinio.prod\n")
+			viper.Set("context", "dev")
+
+			cfg, err := config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+			cfg.Namespace = "other"
+			Expect(cfg.Save()).To(Succeed())
+
+			viper.Set("context", "")
+			cfg, err = config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ActiveContext()).To(Equal("prod"))
+			Expect(cfg.Contexts["dev"].Namespace).To(Equal("other"))
+		})
+	})
+
+	Describe("context management", func() {
+		var cfg *config.Config
+
+		BeforeEach(func() {
+			write("current-context: dev\ncontexts:\n  dev:\n    api: https://epinio.dev\n  prod:\n    api: https://epinio.prod\n")
+
+			var err error
+			cfg, err = config.LoadFrom(file)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("rejects bad and duplicate names", func() {
+			Expect(cfg.AddContext("Prod", config.Context{})).To(MatchError(ContainSubstring("context name incorrect")))
+			Expect(cfg.AddContext("prod", config.Context{})).To(MatchError("context 'prod' already exists"))
+			Expect(cfg.RenameContext("dev", "prod")).To(MatchError("context 'prod' already exists"))
+		})
+
+		It("switches the active context", func() {
+			cfg.Namespace = "changed"
+			Expect(cfg.UseContext("prod")).To(Succeed())
+			Expect(cfg.Current).To(Equal("prod"))
+			Expect(cfg.API).To(Equal("https://epinio.prod"))
+			Expect(cfg.Contexts["dev"].Namespace).To(Equal("changed"))
+			Expect(cfg.UseContext("staging")).To(MatchError("context 'staging' does not exist"))
+		})
+
+		It("does not remove the context in use", func() {
+			Expect(cfg.RemoveContext("dev")).To(MatchError(ContainSubstring("is in use")))
+			Expect(cfg.RemoveContext("prod")).To(Succeed())
+			Expect(cfg.ContextNames()).To(Equal([]string{"dev"}))
+		})
+
+		It("keeps a renamed context current", func() {
+			Expect(cfg.RenameContext("dev", "staging")).To(Succeed())
+			Expect(cfg.Current).To(Equal("staging"))
+			Expect(cfg.ActiveContext()).To(Equal("staging"))
+			Expect(cfg.ContextNames()).To(Equal([]string{"prod", "staging"}))
+		})
+	})
+})
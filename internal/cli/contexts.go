@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/admincmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdContext implements the command: epinio context
+var CmdContext = &cobra.Command{
+	Use:     "context",
+	Aliases: []string{"contexts"},
+	Short:   "Epinio contexts",
+	Long: `Manage the contexts of the epinio cli configuration.
+
+A context holds the location of an Epinio installation, the credentials for it,
+and the namespace targeted in it. Commands use the current context, or the one
+named by the global --context option.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+func init() {
+	CmdContext.AddCommand(CmdContextList)
+	CmdContext.AddCommand(CmdContextUse)
+	CmdContext.AddCommand(CmdContextAdd)
+	CmdContext.AddCommand(CmdContextRemove)
+	CmdContext.AddCommand(CmdContextRename)
+
+	flags := CmdContextAdd.Flags()
+	flags.String("api", "", "url of the Epinio API server, e.g. https://epinio.example.com")
+	flags.String("wss", "", "url of the Epinio websocket server (default: derived from --api)")
+	flags.String("user", "", "name of the Epinio user")
+	flags.String("password", "", "password of the Epinio user")
+	flags.String("namespace", "", "namespace to target (default: workspace)")
+	flags.String("cert-file", "", "file with the certificates to trust for the Epinio servers")
+}
+
+// CmdContextList implements the command: epinio context list
+var CmdContextList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the contexts",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := admincmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.Contexts()
+		if err != nil {
+			return errors.Wrap(err, "error listing contexts")
+		}
+
+		return nil
+	},
+}
+
+// CmdContextUse implements the command: epinio context use
+var CmdContextUse = &cobra.Command{
+	Use:               "use NAME",
+	Short:             "Makes the named context the current one",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingContextFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := admincmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ContextUse(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error switching context")
+		}
+
+		return nil
+	},
+}
+
+// CmdContextAdd implements the command: epinio context add
+var CmdContextAdd = &cobra.Command{
+	Use:   "add NAME",
+	Short: "Adds a context",
+	Long: `Adds a context for the Epinio installation at the given location.
+
+A context added without --api can be completed from the cluster of the
+installation, by running "epinio --context NAME config update".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		options := admincmd.ContextOptions{}
+		for name, value := range map[string]*string{
+			"api":       &options.API,
+			"wss":       &options.WSS,
+			"user":      &options.User,
+			"password":  &options.Password,
+			"namespace": &options.Namespace,
+			"cert-file": &options.CertFile,
+		} {
+			var err error
+			*value, err = cmd.Flags().GetString(name)
+			if err != nil {
+				return errors.Wrapf(err, "error reading option --%s", name)
+			}
+		}
+
+		client, err := admincmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ContextAdd(args[0], options)
+		if err != nil {
+			return errors.Wrap(err, "error adding context")
+		}
+
+		return nil
+	},
+}
+
+// CmdContextRemove implements the command: epinio context remove
+var CmdContextRemove = &cobra.Command{
+	Use:               "remove NAME",
+	Short:             "Removes a context",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingContextFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := admincmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ContextRemove(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error removing context")
+		}
+
+		return nil
+	},
+}
+
+// CmdContextRename implements the command: epinio context rename
+var CmdContextRename = &cobra.Command{
+	Use:               "rename NAME NEW-NAME",
+	Short:             "Renames a context",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: matchingContextFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := admincmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ContextRename(args[0], args[1])
+		if err != nil {
+			return errors.Wrap(err, "error renaming context")
+		}
+
+		return nil
+	},
+}
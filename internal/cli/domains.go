@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdDomain implements the command: epinio domain
+var CmdDomain = &cobra.Command{
+	Use:     "domain",
+	Aliases: []string{"domains"},
+	Short:   "Epinio domain registry",
+	Long: `Manage the domains available to the routes of applications.
+
+A domain "example.com" covers the host "example.com" and the hosts one level
+below it, like "myapp.example.com". A wildcard domain "*.example.com" covers
+all hosts below "example.com".
+
+A registered domain can only be used by the namespace it is assigned to. Hosts
+not covered by a domain assigned to a namespace can be used by all namespaces.
+Domains sharing hosts cannot be assigned to different namespaces. The default routes of applications are placed
+under the default domain of their namespace, or the main domain.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+func init() {
+	CmdDomain.AddCommand(CmdDomainList)
+	CmdDomain.AddCommand(CmdDomainCreate)
+	CmdDomain.AddCommand(CmdDomainAssign)
+	CmdDomain.AddCommand(CmdDomainUnassign)
+	CmdDomain.AddCommand(CmdDomainDelete)
+
+	CmdDomainCreate.Flags().String("namespace", "", "namespace to assign the domain to")
+	CmdDomainCreate.Flags().Bool("default", false, "make the domain the default of the namespace")
+	CmdDomainAssign.Flags().Bool("default", false, "make the domain the default of the namespace")
+}
+
+// CmdDomainList implements the command: epinio domain list
+var CmdDomainList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the registered domains",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.Domains()
+		if err != nil {
+			return errors.Wrap(err, "error listing domains")
+		}
+
+		return nil
+	},
+}
+
+// CmdDomainCreate implements the command: epinio domain create
+var CmdDomainCreate = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Registers a domain",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		namespace, err := cmd.Flags().GetString("namespace")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --namespace")
+		}
+		isDefault, err := cmd.Flags().GetBool("default")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --default")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.CreateDomain(args[0], namespace, isDefault)
+		if err != nil {
+			return errors.Wrap(err, "error registering domain")
+		}
+
+		return nil
+	},
+}
+
+// CmdDomainAssign implements the command: epinio domain assign
+var CmdDomainAssign = &cobra.Command{
+	Use:   "assign NAME NAMESPACE",
+	Short: "Assigns a registered domain to a namespace",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		isDefault, err := cmd.Flags().GetBool("default")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --default")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AssignDomain(args[0], args[1], isDefault)
+		if err != nil {
+			return errors.Wrap(err, "error assigning domain")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return matchingNamespaceFinder(cmd, nil, toComplete)
+	},
+}
+
+// CmdDomainUnassign implements the command: epinio domain unassign
+var CmdDomainUnassign = &cobra.Command{
+	Use:   "unassign NAME",
+	Short: "Releases a registered domain from its namespace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AssignDomain(args[0], "", false)
+		if err != nil {
+			return errors.Wrap(err, "error releasing domain")
+		}
+
+		return nil
+	},
+}
+
+// CmdDomainDelete implements the command: epinio domain delete
+var CmdDomainDelete = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Removes a domain from the registry",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.DeleteDomain(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error removing domain")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// drainHelp is the description of log drains shared by the app and namespace commands
+const drainHelp = `A log drain is an endpoint the Epinio server forwards application logs to.
+Supported are syslog endpoints, over tcp, tls, or udp, and http endpoints:
+
+  syslog://HOST:PORT
+  syslog+tls://HOST:PORT
+  syslog+udp://HOST:PORT
+  https://HOST/PATH
+
+Syslog drains receive RFC 5424 messages, one per log line. Http drains receive
+batches of log lines, posted as JSON arrays. Failed deliveries are retried with
+backoff, the state of the forwarding is shown by the list command.
+
+Drains are identified by their url, or the id shown when listing them.`
+
+// CmdAppDrain implements the command: epinio app drain
+var CmdAppDrain = &cobra.Command{
+	Use:           "drain",
+	Short:         "Epinio application log drains",
+	Long:          "Manage the log drains of applications.\n\n" + drainHelp,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+// CmdNamespaceDrain implements the command: epinio namespace drain
+var CmdNamespaceDrain = &cobra.Command{
+	Use:   "drain",
+	Short: "Epinio namespace log drains",
+	Long: "Manage the log drains of namespaces. They receive the logs of all applications of the namespace.\n\n" +
+		drainHelp,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+func init() {
+	CmdAppDrain.AddCommand(CmdAppDrainList)
+	CmdAppDrain.AddCommand(CmdAppDrainAdd)
+	CmdAppDrain.AddCommand(CmdAppDrainRemove)
+
+	CmdNamespaceDrain.AddCommand(CmdNamespaceDrainList)
+	CmdNamespaceDrain.AddCommand(CmdNamespaceDrainAdd)
+	CmdNamespaceDrain.AddCommand(CmdNamespaceDrainRemove)
+}
+
+// CmdAppDrainList implements the command: epinio app drain list
+var CmdAppDrainList = &cobra.Command{
+	Use:               "list APPNAME",
+	Short:             "Lists application log drains",
+	Long:              "Lists the log drains of the named application, and the state of the forwarding to them",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppDrains(cmd.Context(), args[0])
+		if err != nil {
+			return errors.Wrap(err, "error listing app log drains")
+		}
+
+		return nil
+	},
+}
+
+// CmdAppDrainAdd implements the command: epinio app drain add
+var CmdAppDrainAdd = &cobra.Command{
+	Use:               "add APPNAME URL",
+	Short:             "Add application log drain",
+	Long:              "Forward the logs of the named application to the drain at the url",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppDrainAdd(cmd.Context(), args[0], args[1])
+		if err != nil {
+			return errors.Wrap(err, "error adding app log drain")
+		}
+
+		return nil
+	},
+}
+
+// CmdAppDrainRemove implements the command: epinio app drain remove
+var CmdAppDrainRemove = &cobra.Command{
+	Use:               "remove APPNAME DRAIN",
+	Short:             "Remove application log drain",
+	Long:              "Stop forwarding the logs of the named application to the drain, given by url or id",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppDrainRemove(cmd.Context(), args[0], args[1])
+		if err != nil {
+			return errors.Wrap(err, "error removing app log drain")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceDrainList implements the command: epinio namespace drain list
+var CmdNamespaceDrainList = &cobra.Command{
+	Use:               "list NAME",
+	Short:             "Lists namespace log drains",
+	Long:              "Lists the log drains of the named namespace, and the state of the forwarding to them",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceDrains(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error listing namespace log drains")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceDrainAdd implements the command: epinio namespace drain add
+var CmdNamespaceDrainAdd = &cobra.Command{
+	Use:   "add NAME URL",
+	Short: "Add namespace log drain",
+	Long:  "Forward the logs of all applications of the named namespace to the drain at the url",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceDrainAdd(args[0], args[1])
+		if err != nil {
+			return errors.Wrap(err, "error adding namespace log drain")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return matchingNamespaceFinder(cmd, args, toComplete)
+	},
+}
+
+// CmdNamespaceDrainRemove implements the command: epinio namespace drain remove
+var CmdNamespaceDrainRemove = &cobra.Command{
+	Use:   "remove NAME DRAIN",
+	Short: "Remove namespace log drain",
+	Long:  "Stop forwarding the logs of the named namespace to the drain, given by url or id",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceDrainRemove(args[0], args[1])
+		if err != nil {
+			return errors.Wrap(err, "error removing namespace log drain")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return matchingNamespaceFinder(cmd, args, toComplete)
+	},
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"hash/fnv"
 	"os"
+	"strings"
 	"text/template"
 
 	"github.com/epinio/epinio/helpers/termui"
 	"github.com/fatih/color"
+	"github.com/pkg/errors"
 )
 
 var colorList = [][2]*color.Color{
@@ -23,6 +25,33 @@ var colorList = [][2]*color.Color{
 
 type LogPrinter struct {
 	Tmpl *template.Template
+
+	// Plain prints the expanded template as is, one line per log entry,
+	// without any decoration. For output processed by other tools.
+	Plain bool
+}
+
+// New returns the printer for the output format. The empty format uses the
+// default template, in color. The other formats are `json` (one object per
+// line), `raw` (the messages only), and `template=TEMPLATE`, for a custom Go
+// template over the fields of Log.
+func New(output string, defaultTemplate *template.Template) (LogPrinter, error) {
+	switch {
+	case output == "":
+		return LogPrinter{Tmpl: defaultTemplate}, nil
+	case output == "json":
+		return LogPrinter{Tmpl: newTemplate("{{json .}}"), Plain: true}, nil
+	case output == "raw":
+		return LogPrinter{Tmpl: newTemplate("{{.Message}}"), Plain: true}, nil
+	case strings.HasPrefix(output, "template="):
+		tmpl, err := parseTemplate(strings.TrimPrefix(output, "template="))
+		if err != nil {
+			return LogPrinter{}, errors.Wrap(err, "bad log template")
+		}
+		return LogPrinter{Tmpl: tmpl, Plain: true}, nil
+	}
+
+	return LogPrinter{}, errors.Errorf("unknown output format '%s', expected json, raw, or template=TEMPLATE", output)
 }
 
 // Log is the object which will be used together with the template to generate
@@ -40,12 +69,18 @@ type Log struct {
 	// ContainerName of the container
 	ContainerName string `json:"containerName"`
 
+	// AppName of the application the pod belongs to
+	AppName string `json:"appName"`
+
+	// Timestamp of the log message, if requested
+	Timestamp string `json:"timestamp,omitempty"`
+
 	PodColor       *color.Color `json:"-"`
 	ContainerColor *color.Color `json:"-"`
 }
 
 func (printer LogPrinter) Print(log Log, uiMsg *termui.Message) {
-	log.PodColor, log.ContainerColor = determineColor(log.PodName)
+	log.PodColor, log.ContainerColor = determineColor(log)
 
 	var result bytes.Buffer
 	err := printer.Tmpl.Execute(&result, log)
@@ -54,12 +89,35 @@ func (printer LogPrinter) Print(log Log, uiMsg *termui.Message) {
 		return
 	}
 
+	if printer.Plain {
+		fmt.Fprintln(os.Stdout, result.String())
+		return
+	}
+
 	uiMsg.Msg(result.String() + " ")
 }
 
-func determineColor(podName string) (podColor, containerColor *color.Color) {
+// SplitTimestamp returns the timestamp prefixed to the message by kube, and the
+// message without it.
+func SplitTimestamp(message string) (string, string) {
+	parts := strings.SplitN(message, " ", 2)
+	if len(parts) < 2 {
+		return "", message
+	}
+	return parts[0], parts[1]
+}
+
+// determineColor returns the colors of the log line. All lines of an
+// application have the same colors. Lines without application are colored by
+// their pod.
+func determineColor(log Log) (podColor, containerColor *color.Color) {
+	key := log.AppName
+	if key == "" {
+		key = log.PodName
+	}
+
 	hash := fnv.New32()
-	hash.Write([]byte(podName))
+	hash.Write([]byte(key))
 	idx := hash.Sum32() % uint32(len(colorList))
 
 	colors := colorList[idx]
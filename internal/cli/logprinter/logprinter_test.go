@@ -0,0 +1,81 @@
+package logprinter
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LogPrinter", func() {
+	log := Log{
+		Message:       "GET /healthz 200",
+		Namespace:     "workspace",
+		PodName:       "myapp-5d8f7c-x2x9z",
+		ContainerName: "myapp",
+		AppName:       "myapp",
+		Timestamp:     "2021-11-02T10:04:05.123456789Z",
+	}
+
+	expand := func(printer LogPrinter) string {
+		var result bytes.Buffer
+		Expect(printer.Tmpl.Execute(&result, log)).To(Succeed())
+		return result.String()
+	}
+
+	Describe("New", func() {
+		It("uses the default template in color", func() {
+			printer, err := New("", DefaultSingleNamespaceTemplate())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(printer.Plain).To(BeFalse())
+		})
+
+		It("prints one json object per line", func() {
+			printer, err := New("json", DefaultSingleNamespaceTemplate())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(printer.Plain).To(BeTrue())
+			Expect(expand(printer)).To(MatchJSON(`{
+				"message": "GET /healthz 200",
+				"namespace": "workspace",
+				"podName": "myapp-5d8f7c-x2x9z",
+				"containerName": "myapp",
+				"appName": "myapp",
+				"timestamp": "2021-11-02T10:04:05.123456789Z"
+			}`))
+		})
+
+		It("prints the raw messages", func() {
+			printer, err := New("raw", DefaultSingleNamespaceTemplate())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expand(printer)).To(Equal("GET /healthz 200"))
+		})
+
+		It("prints custom templates", func() {
+			printer, err := New("template={{.AppName}}/{{.PodName}}: {{.Message}}", DefaultSingleNamespaceTemplate())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expand(printer)).To(Equal("myapp/myapp-5d8f7c-x2x9z: GET /healthz 200"))
+		})
+
+		It("rejects bad templates and unknown formats", func() {
+			_, err := New("template={{.AppName", DefaultSingleNamespaceTemplate())
+			Expect(err).To(MatchError(ContainSubstring("bad log template")))
+
+			_, err = New("yaml", DefaultSingleNamespaceTemplate())
+			Expect(err).To(MatchError(ContainSubstring("unknown output format")))
+		})
+	})
+
+	Describe("SplitTimestamp", func() {
+		It("separates the timestamp from the message", func() {
+			timestamp, message := SplitTimestamp("2021-11-02T10:04:05.123456789Z GET /healthz 200")
+			Expect(timestamp).To(Equal("2021-11-02T10:04:05.123456789Z"))
+			Expect(message).To(Equal("GET /healthz 200"))
+		})
+
+		It("keeps messages without timestamp", func() {
+			timestamp, message := SplitTimestamp("starting")
+			Expect(timestamp).To(Equal(""))
+			Expect(message).To(Equal("starting"))
+		})
+	})
+})
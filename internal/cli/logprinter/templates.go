@@ -11,8 +11,30 @@ import (
 // DefaultSingleNamespaceTemplate returns a printing template used when
 // printing with colors and watching resources in a single namespace
 func DefaultSingleNamespaceTemplate() *template.Template {
-	t := "[{{ color .PodColor .PodName}}] {{color .ContainerColor .ContainerName}} {{.Message}}"
+	return newTemplate("[{{ color .PodColor .PodName}}] {{color .ContainerColor .ContainerName}} {{with .Timestamp}}{{.}} {{end}}{{.Message}}")
+}
+
+// DefaultMultiAppTemplate returns a printing template used when printing with
+// colors and watching several applications in a single namespace. Each line
+// starts with the name of its application.
+func DefaultMultiAppTemplate() *template.Template {
+	return newTemplate("{{color .PodColor .AppName}} [{{ color .PodColor .PodName}}] {{color .ContainerColor .ContainerName}} {{with .Timestamp}}{{.}} {{end}}{{.Message}}")
+}
+
+// newTemplate returns the printing template for the text, with the helper
+// functions the templates use.
+func newTemplate(t string) *template.Template {
+	template, err := parseTemplate(t)
+	if err != nil {
+		panic(errors.Wrap(err, "unable to parse template"))
+	}
 
+	return template
+}
+
+// parseTemplate is a helper for newTemplate, and the custom templates of users.
+// It returns the printing template for the text.
+func parseTemplate(t string) (*template.Template, error) {
 	funs := map[string]interface{}{
 		"json": func(in interface{}) (string, error) {
 			b, err := json.Marshal(in)
@@ -25,10 +47,5 @@ func DefaultSingleNamespaceTemplate() *template.Template {
 			return color.SprintFunc()(text)
 		},
 	}
-	template, err := template.New("log").Funcs(funs).Parse(t)
-	if err != nil {
-		panic(errors.Wrap(err, "unable to parse template"))
-	}
-
-	return template
+	return template.New("log").Funcs(funs).Parse(t)
 }
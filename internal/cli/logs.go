@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	CmdLogs.Flags().Bool("follow", false, "follow the logs of the applications")
+	CmdLogs.Flags().String("namespace", "", "namespace of the applications (default: the targeted namespace)")
+	logOptions(CmdLogs)
+
+	// nolint:errcheck // Unable to handle error in init block
+	CmdLogs.RegisterFlagCompletionFunc("namespace", matchingNamespaceFinder)
+}
+
+// CmdLogs implements the command: epinio logs
+var CmdLogs = &cobra.Command{
+	Use:   "logs [--namespace NAMESPACE] [APPNAME...]",
+	Short: "Streams the logs of several applications",
+	Long: `Streams the logs of the named applications of the namespace, interleaved.
+Without names the logs of all applications of the namespace are streamed.
+Each log line starts with the name of its application.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		follow, err := cmd.Flags().GetBool("follow")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --follow")
+		}
+		namespace, err := cmd.Flags().GetString("namespace")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --namespace")
+		}
+		params, output, err := logParameters(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceLogs(namespace, args, follow, params, output, nil)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error streaming logs")
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		app, err := usercmd.New()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return app.AppsMatching(toComplete), cobra.ShellCompDirectiveNoFileComp
+	},
+}
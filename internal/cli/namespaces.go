@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -41,6 +42,42 @@ func init() {
 	CmdNamespace.AddCommand(CmdNamespaceList)
 	CmdNamespace.AddCommand(CmdNamespaceDelete)
 	CmdNamespace.AddCommand(CmdNamespaceShow)
+	CmdNamespace.AddCommand(CmdNamespaceQuota)
+	CmdNamespace.AddCommand(CmdNamespaceNetwork)
+	CmdNamespace.AddCommand(CmdNamespaceEnv)
+	CmdNamespace.AddCommand(CmdNamespaceDrain) // See drains.go for implementation
+	CmdNamespace.AddCommand(CmdNamespaceClone)
+
+	CmdNamespaceClone.Flags().String("domain", "", "domain to move the routes of the cloned applications to")
+
+	CmdNamespaceQuota.AddCommand(CmdNamespaceQuotaShow)
+	CmdNamespaceQuota.AddCommand(CmdNamespaceQuotaSet)
+	CmdNamespaceQuota.AddCommand(CmdNamespaceQuotaDelete)
+
+	CmdNamespaceNetwork.AddCommand(CmdNamespaceNetworkShow)
+	CmdNamespaceNetwork.AddCommand(CmdNamespaceNetworkIsolate)
+	CmdNamespaceNetwork.AddCommand(CmdNamespaceNetworkOpen)
+	CmdNamespaceNetwork.AddCommand(CmdNamespaceNetworkAllow)
+	CmdNamespaceNetwork.AddCommand(CmdNamespaceNetworkRevoke)
+
+	for _, cmd := range []*cobra.Command{CmdNamespaceNetworkAllow, CmdNamespaceNetworkRevoke} {
+		cmd.Flags().StringSlice("from", []string{}, "namespaces whose applications may reach the namespace")
+		// nolint:errcheck // Unable to handle error in init block
+		cmd.MarkFlagRequired("from")
+	}
+
+	CmdNamespaceEnv.AddCommand(CmdNamespaceEnvList)
+	CmdNamespaceEnv.AddCommand(CmdNamespaceEnvSet)
+	CmdNamespaceEnv.AddCommand(CmdNamespaceEnvUnset)
+
+	quotaFlags := CmdNamespaceQuotaSet.Flags()
+	quotaFlags.String("cpu", "", "total cpu limit of all application instances, e.g. 2 or 1500m")
+	quotaFlags.String("memory", "", "total memory limit of all application instances, e.g. 4Gi")
+	quotaFlags.Int32("apps", 0, "maximum number of applications (0: unlimited)")
+	quotaFlags.Int32("services", 0, "maximum number of services (0: unlimited)")
+	quotaFlags.Int32("instances", 0, "maximum number of application instances (0: unlimited)")
+	quotaFlags.String("instance-cpu", "", "cpu limit of each application instance")
+	quotaFlags.String("instance-memory", "", "memory limit of each application instance")
 }
 
 // CmdNamespaces implements the command: epinio namespace list
@@ -146,6 +183,422 @@ var CmdNamespaceShow = &cobra.Command{
 	},
 }
 
+// CmdNamespaceQuota implements the command: epinio namespace quota
+var CmdNamespaceQuota = &cobra.Command{
+	Use:           "quota",
+	Short:         "Epinio namespace quotas",
+	Long:          `Manage the resource limits of epinio-controlled namespaces`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+// CmdNamespaceQuotaShow implements the command: epinio namespace quota show
+var CmdNamespaceQuotaShow = &cobra.Command{
+	Use:               "show NAME",
+	Short:             "Shows the quota of an epinio-controlled namespace, and its usage",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ShowNamespaceQuota(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error showing namespace quota")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceQuotaSet implements the command: epinio namespace quota set
+var CmdNamespaceQuotaSet = &cobra.Command{
+	Use:   "set NAME",
+	Short: "Sets the quota of an epinio-controlled namespace",
+	Long: `Sets the quota of an epinio-controlled namespace. The quota is replaced as a whole,
+limits not specified are removed.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		flags := cmd.Flags()
+		limits := models.NamespaceQuota{}
+		var err error
+
+		for name, value := range map[string]*string{
+			"cpu":             &limits.CPU,
+			"memory":          &limits.Memory,
+			"instance-cpu":    &limits.InstanceCPU,
+			"instance-memory": &limits.InstanceMemory,
+		} {
+			*value, err = flags.GetString(name)
+			if err != nil {
+				return errors.Wrap(err, "error reading option --"+name)
+			}
+		}
+
+		for name, value := range map[string]*int32{
+			"apps":      &limits.Apps,
+			"services":  &limits.Services,
+			"instances": &limits.Instances,
+		} {
+			*value, err = flags.GetInt32(name)
+			if err != nil {
+				return errors.Wrap(err, "error reading option --"+name)
+			}
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.SetNamespaceQuota(args[0], limits)
+		if err != nil {
+			return errors.Wrap(err, "error setting namespace quota")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceQuotaDelete implements the command: epinio namespace quota delete
+var CmdNamespaceQuotaDelete = &cobra.Command{
+	Use:               "delete NAME",
+	Short:             "Removes the quota of an epinio-controlled namespace",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.DeleteNamespaceQuota(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error removing namespace quota")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceNetwork implements the command: epinio namespace network
+var CmdNamespaceNetwork = &cobra.Command{
+	Use:   "network",
+	Short: "Epinio namespace network isolation",
+	Long: `Manage the network isolation of epinio-controlled namespaces.
+
+An isolated namespace denies traffic from the applications of other namespaces.
+It admits traffic from within itself, from the ingress controller, and from the
+namespaces it explicitly allows. The commands operate on the targeted namespace
+when no NAME is given.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+// CmdNamespaceNetworkShow implements the command: epinio namespace network show
+var CmdNamespaceNetworkShow = &cobra.Command{
+	Use:               "show [NAME]",
+	Short:             "Shows the network isolation of an epinio-controlled namespace",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ShowNamespaceNetwork(networkNamespace(client, args))
+		if err != nil {
+			return errors.Wrap(err, "error showing namespace network")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceNetworkIsolate implements the command: epinio namespace network isolate
+var CmdNamespaceNetworkIsolate = &cobra.Command{
+	Use:               "isolate [NAME]",
+	Short:             "Isolates the network of an epinio-controlled namespace",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.IsolateNamespaceNetwork(networkNamespace(client, args))
+		if err != nil {
+			return errors.Wrap(err, "error isolating namespace network")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceNetworkOpen implements the command: epinio namespace network open
+var CmdNamespaceNetworkOpen = &cobra.Command{
+	Use:               "open [NAME]",
+	Short:             "Removes the network isolation of an epinio-controlled namespace",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.OpenNamespaceNetwork(networkNamespace(client, args))
+		if err != nil {
+			return errors.Wrap(err, "error removing namespace network isolation")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceNetworkAllow implements the command: epinio namespace network allow
+var CmdNamespaceNetworkAllow = &cobra.Command{
+	Use:   "allow [NAME] --from NAMESPACE",
+	Short: "Allows other namespaces to reach an epinio-controlled namespace",
+	Long: `Allows the applications of the namespaces given by --from to reach the
+applications of the namespace. This isolates the namespace, if it was not
+isolated yet.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		from, err := cmd.Flags().GetStringSlice("from")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --from")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AllowNamespaceNetwork(networkNamespace(client, args), from)
+		if err != nil {
+			return errors.Wrap(err, "error allowing namespace network access")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceNetworkRevoke implements the command: epinio namespace network revoke
+var CmdNamespaceNetworkRevoke = &cobra.Command{
+	Use:               "revoke [NAME] --from NAMESPACE",
+	Short:             "Revokes the access of other namespaces to an epinio-controlled namespace",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		from, err := cmd.Flags().GetStringSlice("from")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --from")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.RevokeNamespaceNetwork(networkNamespace(client, args), from)
+		if err != nil {
+			return errors.Wrap(err, "error revoking namespace network access")
+		}
+
+		return nil
+	},
+}
+
+// networkNamespace returns the namespace the network commands operate on, i.e.
+// the named one, or the targeted namespace.
+func networkNamespace(client *usercmd.EpinioClient, args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return client.Config.Namespace
+}
+
+// CmdNamespaceClone implements the command: epinio namespace clone
+var CmdNamespaceClone = &cobra.Command{
+	Use:   "clone SRC DST",
+	Short: "Creates a copy of an epinio-controlled namespace",
+	Long: `Creates the namespace DST as a copy of the namespace SRC.
+
+The default environment, services and applications of SRC are copied. Active
+applications are deployed with the image they currently run, without restaging.
+
+The routes of the applications are moved to the domain given by --domain,
+keeping the first label of each host. Without --domain the name of DST is
+inserted after the first label, i.e. "myapp.example.com" becomes
+"myapp.DST.example.com".
+
+Secrets are not copied. Routes lose their basic-auth option, and routes using
+a certificate from a secret use the default issuer in DST instead.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		domain, err := cmd.Flags().GetString("domain")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --domain")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.CloneNamespace(args[0], args[1], domain)
+		if err != nil {
+			return errors.Wrap(err, "error cloning namespace")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return matchingNamespaceFinder(cmd, args, toComplete)
+	},
+}
+
+// CmdNamespaceEnv implements the command: epinio namespace env
+var CmdNamespaceEnv = &cobra.Command{
+	Use:           "env",
+	Short:         "Epinio namespace environment",
+	Long:          `Manage the default environment variables of epinio-controlled namespaces`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+// CmdNamespaceEnvList implements the command: epinio namespace env list
+var CmdNamespaceEnvList = &cobra.Command{
+	Use:               "list NAME",
+	Short:             "Lists namespace environment",
+	Long:              "Lists the default environment variables of the named namespace",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingNamespaceFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceEnvList(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error listing namespace environment")
+		}
+
+		return nil
+	},
+}
+
+// CmdNamespaceEnvSet implements the command: epinio namespace env set
+var CmdNamespaceEnvSet = &cobra.Command{
+	Use:   "set NAME VAR VALUE",
+	Short: "Extend namespace environment",
+	Long: `Add or change a default environment variable of the named namespace.
+All applications of the namespace inherit it, unless they set the variable themselves.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceEnvSet(args[0], args[1], args[2])
+		if err != nil {
+			return errors.Wrap(err, "error setting into namespace environment")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return matchingNamespaceFinder(cmd, args, toComplete)
+	},
+}
+
+// CmdNamespaceEnvUnset implements the command: epinio namespace env unset
+var CmdNamespaceEnvUnset = &cobra.Command{
+	Use:   "unset NAME VAR",
+	Short: "Shrink namespace environment",
+	Long:  "Remove a default environment variable from the named namespace",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.NamespaceEnvUnset(args[0], args[1])
+		if err != nil {
+			return errors.Wrap(err, "error removing from namespace environment")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return matchingNamespaceFinder(cmd, args, toComplete)
+	},
+}
+
 // askConfirmation is a helper for CmdNamespaceDelete to confirm a deletion request
 func askConfirmation(cmd *cobra.Command) bool {
 	reader := bufio.NewReader(os.Stdin)
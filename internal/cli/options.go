@@ -5,6 +5,8 @@ import (
 
 	"github.com/epinio/epinio/internal/api/v1/application"
 	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -14,8 +16,14 @@ func instancesOption(cmd *cobra.Command) {
 		"The number of instances the application should have")
 }
 
+// routeOption initializes the --route/-r option for the provided command
 func routeOption(cmd *cobra.Command) {
-	cmd.Flags().StringSliceP("route", "r", []string{}, "Custom route to use for the application (a subdomain of the default domain will be used if this is not set). Can be set multiple times to use multiple routes with the same application.")
+	cmd.Flags().StringSliceP("route", "r", []string{}, "Custom route to use for the application (a subdomain of the default domain will be used if this is not set). Can be set multiple times to use multiple routes with the same application. Options follow the path in query syntax, e.g. 'myapp.example.com/api?https-redirect&timeout=30s&max-body-size=10M&allow=10.0.0.0/8&basic-auth=SECRET'. Use 'grpc://myapp.example.com' for gRPC applications, and 'tcp://PORT[?expose=nodeport&target=APPPORT]' for plain TCP ports.")
+}
+
+// internalOption initializes the --internal option for the provided command
+func internalOption(cmd *cobra.Command) {
+	cmd.Flags().Bool("internal", false, "Make the application internal, i.e. without any routes, reachable from within the cluster only. Use --internal=false to make it public again.")
 }
 
 // bindOption initializes the --bind/-b option for the provided command
@@ -66,3 +74,62 @@ func bindOption(cmd *cobra.Command) {
 func envOption(cmd *cobra.Command) {
 	cmd.Flags().StringSliceP("env", "e", []string{}, "environment variables to be used")
 }
+
+// logOptions initializes the options restricting the log lines for the provided command
+func logOptions(cmd *cobra.Command) {
+	cmd.Flags().Duration("since", 0, "only show log lines younger than the duration, e.g. 1h (default: the configured log history)")
+	cmd.Flags().Int64("tail", -1, "only show the last lines of each instance (default: all lines)")
+	cmd.Flags().StringArray("grep", []string{}, "only show log lines matching the regular expression. Can be set multiple times, a line has to match one of them")
+	cmd.Flags().StringArray("exclude", []string{}, "do not show log lines matching the regular expression. Can be set multiple times")
+	cmd.Flags().String("instance", "", "only show the logs of the named instance")
+	cmd.Flags().Bool("timestamps", false, "show the timestamp of each log line")
+	cmd.Flags().StringP("output", "o", "", "format of the log lines: json (one object per line), raw (the messages only), or template=TEMPLATE (a Go template over the fields of a log line, see the json output for their names)")
+}
+
+// logParameters returns the log parameters, and the output format, for the options initialized by logOptions
+func logParameters(cmd *cobra.Command) (models.LogParameters, string, error) {
+	params := models.LogParameters{}
+
+	since, err := cmd.Flags().GetDuration("since")
+	if err != nil {
+		return params, "", errors.Wrap(err, "error reading option --since")
+	}
+	if since < 0 {
+		return params, "", errors.New("option --since has to be a positive duration")
+	}
+	if since > 0 {
+		params.Since = since.String()
+	}
+
+	tail, err := cmd.Flags().GetInt64("tail")
+	if err != nil {
+		return params, "", errors.Wrap(err, "error reading option --tail")
+	}
+	if tail >= 0 {
+		params.Tail = &tail
+	}
+
+	params.Include, err = cmd.Flags().GetStringArray("grep")
+	if err != nil {
+		return params, "", errors.Wrap(err, "error reading option --grep")
+	}
+	params.Exclude, err = cmd.Flags().GetStringArray("exclude")
+	if err != nil {
+		return params, "", errors.Wrap(err, "error reading option --exclude")
+	}
+	params.Instance, err = cmd.Flags().GetString("instance")
+	if err != nil {
+		return params, "", errors.Wrap(err, "error reading option --instance")
+	}
+	params.Timestamps, err = cmd.Flags().GetBool("timestamps")
+	if err != nil {
+		return params, "", errors.Wrap(err, "error reading option --timestamps")
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return params, "", errors.Wrap(err, "error reading option --output")
+	}
+
+	return params, output, nil
+}
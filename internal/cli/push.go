@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/epinio/epinio/helpers"
 	"github.com/epinio/epinio/internal/cli/usercmd"
 	"github.com/epinio/epinio/internal/manifest"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -21,7 +22,12 @@ func init() {
 	CmdAppPush.Flags().StringP("path", "p", "", "Path to application sources.")
 	CmdAppPush.Flags().String("builder-image", "", "Paketo builder image to use for staging")
 
+	// The following options select the sources to upload, in addition to the .epinioignore files
+	CmdAppPush.Flags().StringSlice("exclude", []string{}, "Pattern of sources to leave out of the upload, like in .gitignore (multiple times)")
+	CmdAppPush.Flags().Bool("gitignore", false, "Leave the sources matched by the .gitignore files out of the upload")
+
 	routeOption(CmdAppPush)
+	internalOption(CmdAppPush)
 	bindOption(CmdAppPush)
 	envOption(CmdAppPush)
 	instancesOption(CmdAppPush)
@@ -99,8 +105,21 @@ var CmdAppPush = &cobra.Command{
 			}
 		}
 
+		excludes, err := cmd.Flags().GetStringSlice("exclude")
+		if err != nil {
+			return errors.Wrap(err, "failed to read option --exclude")
+		}
+		gitIgnore, err := cmd.Flags().GetBool("gitignore")
+		if err != nil {
+			return errors.Wrap(err, "failed to read option --gitignore")
+		}
+
 		params := usercmd.PushParams{
 			ApplicationManifest: m,
+			Sources: helpers.TarOptions{
+				Excludes:  excludes,
+				GitIgnore: gitIgnore,
+			},
 		}
 
 		err = client.Push(cmd.Context(), params)
@@ -8,6 +8,7 @@ import (
 	"runtime"
 
 	"github.com/epinio/epinio/helpers/kubernetes/config"
+	"github.com/epinio/epinio/helpers/termui"
 	"github.com/epinio/epinio/helpers/tracelog"
 	pconfig "github.com/epinio/epinio/internal/cli/config"
 	"github.com/epinio/epinio/internal/duration"
@@ -51,6 +52,10 @@ func init() {
 	viper.BindPFlag("config-file", pf.Lookup("config-file"))
 	argToEnv["config-file"] = "EPINIO_CONFIG"
 
+	pf.StringP("context", "", "", "use the named context of the configuration instead of the current one")
+	viper.BindPFlag("context", pf.Lookup("context"))
+	argToEnv["context"] = "EPINIO_CONTEXT"
+
 	config.KubeConfigFlags(pf, argToEnv)
 	tracelog.LoggerFlags(pf, argToEnv)
 	duration.Flags(pf, argToEnv)
@@ -67,16 +72,29 @@ func init() {
 	viper.BindPFlag("no-colors", pf.Lookup("no-colors"))
 	argToEnv["colors"] = "EPINIO_COLORS"
 
+	// The format is bound to its own key, `output` is the log format of the server.
+	pf.StringP("output", "o", termui.OutputTable, "(EPINIO_OUTPUT) Output format of the command results: table, json, or yaml. "+
+		"For json and yaml the results are printed to stdout, and all other messages to stderr. "+
+		"The logs and server commands use the flag for the format of their log lines instead")
+	viper.BindPFlag("output-format", pf.Lookup("output"))
+	viper.BindEnv("output-format", "EPINIO_OUTPUT")
+
 	config.AddEnvToUsage(rootCmd, argToEnv)
 
 	rootCmd.AddCommand(CmdCompletion)
 	rootCmd.AddCommand(CmdConfig)
+	rootCmd.AddCommand(CmdContext)
 	rootCmd.AddCommand(CmdInfo)
 	rootCmd.AddCommand(CmdNamespace)
+	rootCmd.AddCommand(CmdDomain)
+	rootCmd.AddCommand(CmdRoute)
 	rootCmd.AddCommand(CmdAppPush) // shorthand access to `app push`.
+	rootCmd.AddCommand(CmdApply)
 	rootCmd.AddCommand(CmdApp)
+	rootCmd.AddCommand(CmdLogs)
 	rootCmd.AddCommand(CmdTarget)
 	rootCmd.AddCommand(CmdService)
+	rootCmd.AddCommand(CmdVolume)
 	rootCmd.AddCommand(CmdServer)
 	rootCmd.AddCommand(cmdVersion)
 	// Hidden command providing developer tools
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdAppRoute implements the command: epinio app route
+var CmdAppRoute = &cobra.Command{
+	Use:           "route",
+	Short:         "Epinio application routes",
+	Long:          `Manage the routes of epinio applications`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+func init() {
+	CmdAppRoute.AddCommand(CmdAppRouteCert)
+
+	certFlags := CmdAppRouteCert.Flags()
+	certFlags.String("secret", "", "existing TLS secret in the namespace of the application")
+	certFlags.String("issuer", "", "cert-manager cluster issuer to request the certificate from")
+	certFlags.String("cert", "", "PEM file of the certificate to upload")
+	certFlags.String("key", "", "PEM file of the private key to upload")
+}
+
+// CmdAppRouteCert implements the command: epinio app route cert
+var CmdAppRouteCert = &cobra.Command{
+	Use:   "cert APPNAME ROUTE",
+	Short: "Configure the certificate of an application route",
+	Long: `Configure where the certificate of an application route comes from.
+
+Use --secret to reference an existing TLS secret, --cert and --key to upload a
+certificate and its key, or --issuer to request the certificate from a specific
+cluster issuer. Without any of these the route returns to the default issuer.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		options := map[string]string{}
+		for _, name := range []string{"secret", "issuer", "cert", "key"} {
+			value, err := cmd.Flags().GetString(name)
+			if err != nil {
+				return errors.Wrap(err, "error reading option --"+name)
+			}
+			options[name] = value
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppRouteCert(args[0], args[1],
+			options["secret"], options["issuer"], options["cert"], options["key"])
+		if err != nil {
+			return errors.Wrap(err, "error configuring route certificate")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return matchingAppsFinder(cmd, args, toComplete)
+	},
+}
+
+// CmdRoute implements the command: epinio route
+var CmdRoute = &cobra.Command{
+	Use:           "route",
+	Short:         "Epinio routes",
+	Long:          `Inspect and move the routes of epinio applications`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+func init() {
+	CmdRoute.AddCommand(CmdRouteList)
+	CmdRoute.AddCommand(CmdRouteMove)
+
+	moveFlags := CmdRouteMove.Flags()
+	moveFlags.String("from", "", "application currently using the route")
+	moveFlags.String("to", "", "application to use the route")
+	// nolint:errcheck // Unable to handle error in init block
+	CmdRouteMove.MarkFlagRequired("from")
+	// nolint:errcheck // Unable to handle error in init block
+	CmdRouteMove.MarkFlagRequired("to")
+}
+
+// CmdRouteList implements the command: epinio route list
+var CmdRouteList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all routes",
+	Long:  "Lists the active routes of all applications in all namespaces, with their owning application and TLS status",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.Routes()
+		if err != nil {
+			return errors.Wrap(err, "error listing routes")
+		}
+
+		return nil
+	},
+}
+
+// CmdRouteMove implements the command: epinio route move
+var CmdRouteMove = &cobra.Command{
+	Use:   "move HOST[/PATH] --from APPNAME --to APPNAME",
+	Short: "Move a route between applications",
+	Long: `Move a route from one application of the targeted namespace to another, in one operation.
+
+The active route is re-pointed at the new application, keeping its options and
+certificate. It is not removed first, i.e. it is served without interruption.
+The new application has to be deployed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --from")
+		}
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --to")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.RouteMove(args[0], from, to)
+		if err != nil {
+			return errors.Wrap(err, "error moving route")
+		}
+
+		return nil
+	},
+}
@@ -12,9 +12,13 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/helpers/termui"
 	"github.com/epinio/epinio/helpers/tracelog"
+	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server"
+	"github.com/epinio/epinio/internal/drains"
+	"github.com/epinio/epinio/internal/ingress"
 	"github.com/epinio/epinio/internal/version"
 	"github.com/go-logr/logr"
 
@@ -52,6 +56,34 @@ func init() {
 	flags.String("ingress-class-name", "", "(INGRESS_CLASS_NAME) Name of the ingress class to use for apps. Leave empty to add no ingressClassName to the ingress.")
 	viper.BindPFlag("ingress-class-name", flags.Lookup("ingress-class-name"))
 	viper.BindEnv("ingress-class-name", "INGRESS_CLASS_NAME")
+
+	flags.String("ingress-provider", ingress.TraefikName, "(INGRESS_PROVIDER) The ingress controller exposing the apps [traefik,nginx,gateway]")
+	viper.BindPFlag("ingress-provider", flags.Lookup("ingress-provider"))
+	viper.BindEnv("ingress-provider", "INGRESS_PROVIDER")
+
+	flags.String("gateway-name", "", "(GATEWAY_NAME) Name of the Gateway API gateway to attach app routes to. Used by the gateway ingress provider.")
+	viper.BindPFlag("gateway-name", flags.Lookup("gateway-name"))
+	viper.BindEnv("gateway-name", "GATEWAY_NAME")
+
+	flags.String("gateway-namespace", "", "(GATEWAY_NAMESPACE) Namespace of the Gateway API gateway. Leave empty for the namespace of the app. Isolated namespaces admit traffic from it.")
+	viper.BindPFlag("gateway-namespace", flags.Lookup("gateway-namespace"))
+	viper.BindEnv("gateway-namespace", "GATEWAY_NAMESPACE")
+
+	flags.Bool("namespace-isolation", false, "(NAMESPACE_ISOLATION) Isolate the network of new namespaces from the other namespaces")
+	viper.BindPFlag("namespace-isolation", flags.Lookup("namespace-isolation"))
+	viper.BindEnv("namespace-isolation", "NAMESPACE_ISOLATION")
+
+	flags.String("ingress-namespace", "traefik", "(INGRESS_NAMESPACE) Namespace of the ingress controller. Isolated namespaces admit traffic from it.")
+	viper.BindPFlag("ingress-namespace", flags.Lookup("ingress-namespace"))
+	viper.BindEnv("ingress-namespace", "INGRESS_NAMESPACE")
+
+	flags.StringSlice("cluster-cidrs", []string{}, "(CLUSTER_CIDRS) Pod and service networks of the cluster. Isolated namespaces may reach all other addresses. Leave empty to deny them egress to external addresses.")
+	viper.BindPFlag("cluster-cidrs", flags.Lookup("cluster-cidrs"))
+	viper.BindEnv("cluster-cidrs", "CLUSTER_CIDRS")
+
+	flags.String("cluster-domain", application.DefaultClusterDomain, "(CLUSTER_DOMAIN) DNS domain of the cluster, for the in-cluster host names of the apps")
+	viper.BindPFlag("cluster-domain", flags.Lookup("cluster-domain"))
+	viper.BindEnv("cluster-domain", "CLUSTER_DOMAIN")
 }
 
 // CmdServer implements the command: epinio server
@@ -71,11 +103,30 @@ var CmdServer = &cobra.Command{
 		}
 		logger = logger.WithName("EpinioServer")
 
+		provider, err := ingress.Lookup(viper.GetString("ingress-provider"))
+		if err != nil {
+			return errors.Wrap(err, "error checking ingress provider")
+		}
+		if provider.Name() == ingress.GatewayName && viper.GetString("gateway-name") == "" {
+			return errors.New("the gateway ingress provider requires a gateway name")
+		}
+
 		handler, err := server.NewHandler(logger)
 		if err != nil {
 			return errors.Wrap(err, "error creating handler")
 		}
 
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cluster, err := kubernetes.GetCluster(ctx)
+		if err != nil {
+			return errors.Wrap(err, "error getting cluster")
+		}
+
+		// Forward application logs to their drains, see `epinio app drain`
+		drains.Start(ctx, cluster, logger.WithName("LogDrains"))
+
 		port := viper.GetInt("port")
 		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 		if err != nil {
@@ -20,7 +20,20 @@ func init() {
 	CmdService.AddCommand(CmdServiceUnbind)
 	CmdService.AddCommand(CmdServiceList)
 
+	CmdService.AddCommand(CmdServiceExport)
+	CmdService.AddCommand(CmdServiceImport)
+
 	CmdServiceList.Flags().Bool("all", false, "list all services")
+	CmdServiceList.Flags().StringP("selector", "l", "", "only list services whose labels match the selector, e.g. env=prod")
+	CmdServiceList.Flags().Bool("wide", false, "show description, labels, and number of bound applications")
+
+	labelOptions(CmdServiceCreate)
+	labelOptions(CmdServiceUpdate)
+	CmdServiceUpdate.Flags().StringSlice("unlabel", []string{}, "service labels to remove")
+
+	CmdServiceExport.Flags().String("key", "", "file whose contents are the key to encrypt the service values with")
+	CmdServiceImport.Flags().StringP("file", "f", "", "export document to import")
+	CmdServiceImport.Flags().String("key", "", "file whose contents are the key to decrypt the service values with")
 
 	changeOptions(CmdServiceUpdate)
 }
@@ -177,12 +190,39 @@ var CmdServiceUnbind = &cobra.Command{
 
 // CmdServiceList implements the command: epinio service list
 var CmdServiceList = &cobra.Command{
-	Use:   "list [--all]",
+	Use:   "list [--all] [--selector SELECTOR]",
 	Short: "Lists services",
 	Long:  "Lists services in the targeted namespace, or all",
 	RunE:  ServiceList,
 }
 
+// CmdServiceExport implements the command: epinio service export
+var CmdServiceExport = &cobra.Command{
+	Use:   "export [NAME...]",
+	Short: "Export services",
+	Long:  "Write the named services, or all services of the targeted namespace, as YAML document to stdout",
+	RunE:  ServiceExport,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		epinioClient, err := usercmd.New()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		matches := epinioClient.ServiceMatching(context.Background(), toComplete)
+
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+// CmdServiceImport implements the command: epinio service import
+var CmdServiceImport = &cobra.Command{
+	Use:   "import -f FILE",
+	Short: "Import services",
+	Long:  "Create the services of an export document in the targeted namespace. Existing services are replaced.",
+	Args:  cobra.ExactArgs(0),
+	RunE:  ServiceImport,
+}
+
 // ServiceShow is the backend of command: epinio service show
 func ServiceShow(cmd *cobra.Command, args []string) error {
 	cmd.SilenceUsage = true
@@ -214,7 +254,17 @@ func ServiceList(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "error reading option --all")
 	}
 
-	err = client.Services(all)
+	selector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		return errors.Wrap(err, "error reading option --selector")
+	}
+
+	wide, err := cmd.Flags().GetBool("wide")
+	if err != nil {
+		return errors.Wrap(err, "error reading option --wide")
+	}
+
+	err = client.Services(all, selector, wide)
 	if err != nil {
 		return errors.Wrap(err, "error listing services")
 	}
@@ -231,7 +281,17 @@ func ServiceCreate(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "error initializing cli")
 	}
 
-	err = client.CreateService(args[0], args[1:])
+	labels, err := readLabels(cmd)
+	if err != nil {
+		return err
+	}
+
+	description, err := cmd.Flags().GetString("description")
+	if err != nil {
+		return errors.Wrap(err, "error reading option --description")
+	}
+
+	err = client.CreateService(args[0], args[1:], labels, description)
 	if err != nil {
 		return errors.Wrap(err, "error creating service")
 	}
@@ -269,7 +329,26 @@ func ServiceUpdate(cmd *cobra.Command, args []string) error {
 		assignments[pieces[0]] = pieces[1]
 	}
 
-	err = client.UpdateService(args[0], removedKeys, assignments)
+	removedLabels, err := cmd.Flags().GetStringSlice("unlabel")
+	if err != nil {
+		return errors.Wrap(err, "failed to read option --unlabel")
+	}
+
+	labelAssignments, err := readLabels(cmd)
+	if err != nil {
+		return err
+	}
+
+	var description *string
+	if cmd.Flags().Changed("description") {
+		d, err := cmd.Flags().GetString("description")
+		if err != nil {
+			return errors.Wrap(err, "failed to read option --description")
+		}
+		description = &d
+	}
+
+	err = client.UpdateService(args[0], removedKeys, assignments, removedLabels, labelAssignments, description)
 	if err != nil {
 		return errors.Wrap(err, "error creating service")
 	}
@@ -333,6 +412,58 @@ func ServiceUnbind(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// ServiceExport is the backend of command: epinio service export
+func ServiceExport(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	key, err := cmd.Flags().GetString("key")
+	if err != nil {
+		return errors.Wrap(err, "error reading option --key")
+	}
+
+	client, err := usercmd.New()
+	if err != nil {
+		return errors.Wrap(err, "error initializing cli")
+	}
+
+	err = client.ExportServices(args, key)
+	if err != nil {
+		return errors.Wrap(err, "error exporting services")
+	}
+
+	return nil
+}
+
+// ServiceImport is the backend of command: epinio service import
+func ServiceImport(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return errors.Wrap(err, "error reading option --file")
+	}
+	if file == "" {
+		return errors.New("option --file is required")
+	}
+
+	key, err := cmd.Flags().GetString("key")
+	if err != nil {
+		return errors.Wrap(err, "error reading option --key")
+	}
+
+	client, err := usercmd.New()
+	if err != nil {
+		return errors.Wrap(err, "error initializing cli")
+	}
+
+	err = client.ImportServices(file, key)
+	if err != nil {
+		return errors.Wrap(err, "error importing services")
+	}
+
+	return nil
+}
+
 // changeOptions initializes the --remove/-r and --set/-s options for
 // the provided command.
 func changeOptions(cmd *cobra.Command) {
@@ -343,3 +474,29 @@ func changeOptions(cmd *cobra.Command) {
 	// its details so that the keys to remove can be matched. And add/modify cannot
 	// check anyway.
 }
+
+// labelOptions initializes the --label and --description options for
+// the provided command.
+func labelOptions(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("label", []string{}, "service labels to assign, as key=value")
+	cmd.Flags().String("description", "", "service description")
+}
+
+// readLabels processes the --label option into a map of label assignments.
+func readLabels(cmd *cobra.Command) (map[string]string, error) {
+	kvLabels, err := cmd.Flags().GetStringSlice("label")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read option --label")
+	}
+
+	labels := map[string]string{}
+	for _, assignment := range kvLabels {
+		pieces := strings.Split(assignment, "=")
+		if len(pieces) != 2 {
+			return nil, errors.New("Bad --label assignment `" + assignment + "`, expected `key=value` as value")
+		}
+		labels[pieces[0]] = pieces[1]
+	}
+
+	return labels, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"sort"
 	"strconv"
@@ -46,13 +47,13 @@ func (c *EpinioClient) AppCreate(appName string, appConfig models.ApplicationUpd
 		Configuration: appConfig,
 	}
 
-	_, err := c.API.AppCreate(request, c.Config.Namespace)
+	resp, err := c.API.AppCreate(request, c.Config.Namespace)
 	if err != nil {
 		return err
 	}
 
 	c.ui.Success().Msg("Ok")
-	return nil
+	return c.ui.Result(resp)
 }
 
 // AppsMatching returns all Epinio apps having the specified prefix
@@ -123,6 +124,10 @@ func (c *EpinioClient) Apps(all bool) error {
 
 	sort.Sort(apps)
 
+	if c.ui.MachineReadable() {
+		return c.ui.Result(apps)
+	}
+
 	if all {
 		msg = c.ui.Success().WithTable("Namespace", "Name", "Status", "Routes", "Services", "Status Details")
 
@@ -143,7 +148,7 @@ func (c *EpinioClient) Apps(all bool) error {
 					app.Meta.Namespace,
 					app.Meta.Name,
 					app.Workload.Status,
-					strings.Join(app.Workload.Routes, ", "),
+					routesDetails(app),
 					strings.Join(app.Configuration.Services, ", "),
 					app.StatusMessage,
 				)
@@ -167,7 +172,7 @@ func (c *EpinioClient) Apps(all bool) error {
 				msg = msg.WithTableRow(
 					app.Meta.Name,
 					app.Workload.Status,
-					strings.Join(app.Workload.Routes, ", "),
+					routesDetails(app),
 					strings.Join(app.Configuration.Services, ", "),
 					app.StatusMessage,
 				)
@@ -203,6 +208,10 @@ func (c *EpinioClient) AppShow(appName string) error {
 		return err
 	}
 
+	if c.ui.MachineReadable() {
+		return c.ui.Result(app)
+	}
+
 	if err := c.printAppDetails(app); err != nil {
 		return err
 	}
@@ -287,6 +296,10 @@ func (c *EpinioClient) AppUpdate(appName string, appConfig models.ApplicationUpd
 		}
 	}
 
+	if appConfig.Internal != nil {
+		msg = msg.WithBoolValue("Internal", *appConfig.Internal)
+	}
+
 	msg.Msg("Update application")
 
 	if err := c.TargetOk(); err != nil {
@@ -295,14 +308,14 @@ func (c *EpinioClient) AppUpdate(appName string, appConfig models.ApplicationUpd
 
 	details.Info("update application")
 
-	_, err := c.API.AppUpdate(appConfig, c.Config.Namespace, appName)
+	resp, err := c.API.AppUpdate(appConfig, c.Config.Namespace, appName)
 	if err != nil {
 		return err
 	}
 
 	c.ui.Success().Msg("Successfully updated application")
 
-	return nil
+	return c.ui.Result(resp)
 }
 
 // AppLogs streams the logs of all the application instances, in the targeted namespace
@@ -330,16 +343,27 @@ func (c *EpinioClient) AppUpdate(appName string, appConfig models.ApplicationUpd
 // 5. The main thread returns
 // When the connection is closed (e.g. from the server side), the process is the
 // same but starts from #2 above.
-func (c *EpinioClient) AppLogs(appName, stageID string, follow bool, interrupt chan bool) error {
+// The parameters restrict the streamed log lines, see models.LogParameters.
+// The output selects the format of the log lines, see logprinter.New.
+func (c *EpinioClient) AppLogs(appName, stageID string, follow bool, params models.LogParameters, output string, interrupt chan bool) error {
 	log := c.Log.WithName("Apps").WithValues("Namespace", c.Config.Namespace, "Application", appName)
 	log.Info("start")
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
-	c.ui.Note().
-		WithStringValue("Namespace", c.Config.Namespace).
-		WithStringValue("Application", appName).
-		Msg("Streaming application logs")
+	printer, err := logprinter.New(output, logprinter.DefaultSingleNamespaceTemplate())
+	if err != nil {
+		return err
+	}
+	params = logOutputParameters(params, output)
+
+	// Structured output is for other tools, keep it free of decorations
+	if !printer.Plain {
+		c.ui.Note().
+			WithStringValue("Namespace", c.Config.Namespace).
+			WithStringValue("Application", appName).
+			Msg("Streaming application logs")
+	}
 
 	if err := c.TargetOk(); err != nil {
 		return err
@@ -356,6 +380,7 @@ func (c *EpinioClient) AppLogs(appName, stageID string, follow bool, interrupt c
 	urlArgs = append(urlArgs, fmt.Sprintf("follow=%t", follow))
 	urlArgs = append(urlArgs, fmt.Sprintf("stage_id=%s", stageID))
 	urlArgs = append(urlArgs, fmt.Sprintf("authtoken=%s", token))
+	urlArgs = append(urlArgs, logQuery(params)...)
 
 	var endpoint string
 	if stageID == "" {
@@ -363,6 +388,16 @@ func (c *EpinioClient) AppLogs(appName, stageID string, follow bool, interrupt c
 	} else {
 		endpoint = api.WsRoutes.Path("StagingLogs", c.Config.Namespace, stageID)
 	}
+
+	return c.streamLogs(endpoint, urlArgs, printer, params.Timestamps, interrupt)
+}
+
+// streamLogs is a helper for AppLogs and NamespaceLogs. It connects to the
+// websocket endpoint and prints the received log lines, until the connection
+// is closed, or something is sent to the interrupt channel. See AppLogs for
+// the details. With timestamps the timestamp prefixed to the log lines is
+// separated from the messages.
+func (c *EpinioClient) streamLogs(endpoint string, urlArgs []string, printer logprinter.LogPrinter, timestamps bool, interrupt chan bool) error {
 	webSocketConn, resp, err := websocket.DefaultDialer.Dial(
 		fmt.Sprintf("%s%s/%s?%s", c.API.WsURL, api.WsRoot, endpoint, strings.Join(urlArgs, "&")), http.Header{})
 	if err != nil {
@@ -400,7 +435,6 @@ func (c *EpinioClient) AppLogs(appName, stageID string, follow bool, interrupt c
 	}()
 
 	var logLine tailer.ContainerLogLine
-	printer := logprinter.LogPrinter{Tmpl: logprinter.DefaultSingleNamespaceTemplate()}
 	for {
 		_, message, err := webSocketConn.ReadMessage()
 		if err != nil {
@@ -418,12 +452,18 @@ func (c *EpinioClient) AppLogs(appName, stageID string, follow bool, interrupt c
 			return err
 		}
 
-		printer.Print(logprinter.Log{
+		entry := logprinter.Log{
 			Message:       logLine.Message,
 			Namespace:     logLine.Namespace,
 			PodName:       logLine.PodName,
 			ContainerName: logLine.ContainerName,
-		}, c.ui.ProgressNote().Compact())
+			AppName:       logLine.AppName,
+		}
+		if timestamps {
+			entry.Timestamp, entry.Message = logprinter.SplitTimestamp(entry.Message)
+		}
+
+		printer.Print(entry, c.ui.ProgressNote().Compact())
 	}
 }
 
@@ -517,7 +557,7 @@ func (c *EpinioClient) Delete(ctx context.Context, appname string) error {
 
 	c.ui.Success().Msg("Application deleted.")
 
-	return nil
+	return c.ui.Result(response)
 }
 
 func (c *EpinioClient) printAppDetails(app models.App) error {
@@ -543,6 +583,34 @@ func (c *EpinioClient) printAppDetails(app models.App) error {
 			for _, r := range app.Workload.Routes {
 				msg = msg.WithTableRow("", r)
 			}
+		} else if isInternal(app) {
+			msg = msg.WithTableRow("", "none, internal application")
+		}
+
+		if app.Workload.InternalURL != "" {
+			msg = msg.WithTableRow("Internal URL", app.Workload.InternalURL)
+		}
+
+		if len(app.Workload.Endpoints) > 0 {
+			msg = msg.WithTableRow("Endpoints", "")
+
+			sort.Slice(app.Workload.Endpoints, func(i, j int) bool {
+				return app.Workload.Endpoints[i].Route < app.Workload.Endpoints[j].Route
+			})
+			for _, endpoint := range app.Workload.Endpoints {
+				msg = msg.WithTableRow("", fmt.Sprintf("%s: %s", endpoint.Route, endpoint.Endpoint))
+			}
+		}
+
+		if len(app.Workload.Certificates) > 0 {
+			msg = msg.WithTableRow("Certificates", "")
+
+			sort.Slice(app.Workload.Certificates, func(i, j int) bool {
+				return app.Workload.Certificates[i].Route < app.Workload.Certificates[j].Route
+			})
+			for _, cert := range app.Workload.Certificates {
+				msg = msg.WithTableRow("", certificateDetails(cert))
+			}
 		}
 	} else {
 		if app.StageID == "" {
@@ -557,12 +625,15 @@ func (c *EpinioClient) printAppDetails(app models.App) error {
 			for _, route := range app.Configuration.Routes {
 				msg = msg.WithTableRow("", route)
 			}
+		} else if isInternal(app) {
+			msg = msg.WithTableRow("", "none, internal application")
 		}
 	}
 
 	msg = msg.
 		WithTableRow("Desired Instances", fmt.Sprintf("%d", *app.Configuration.Instances)).
 		WithTableRow("Bound Services", strings.Join(app.Configuration.Services, ", ")).
+		WithTableRow("Bound Volumes", volumeDetails(app.Configuration.Volumes)).
 		WithTableRow("Environment", "")
 
 	if len(app.Configuration.Environment) > 0 {
@@ -576,6 +647,97 @@ func (c *EpinioClient) printAppDetails(app models.App) error {
 	return nil
 }
 
+// isInternal returns true if the app has no public routes.
+func isInternal(app models.App) bool {
+	return app.Configuration.Internal != nil && *app.Configuration.Internal
+}
+
+// routesDetails is a helper for Apps. It formats the active routes of an app.
+func routesDetails(app models.App) string {
+	if isInternal(app) {
+		return "internal"
+	}
+	return strings.Join(app.Workload.Routes, ", ")
+}
+
+// logOutputParameters is a helper for AppLogs and NamespaceLogs. It returns
+// the parameters required by the output format. JSON output always carries the
+// timestamps of the log lines.
+func logOutputParameters(params models.LogParameters, output string) models.LogParameters {
+	if output == "json" {
+		params.Timestamps = true
+	}
+	return params
+}
+
+// logQuery is a helper for AppLogs. It returns the query arguments for the
+// parameters restricting the log lines. Unset parameters are left out.
+func logQuery(params models.LogParameters) []string {
+	query := url.Values{}
+	if params.Since != "" {
+		query.Set("since", params.Since)
+	}
+	if params.Tail != nil {
+		query.Set("tail", strconv.FormatInt(*params.Tail, 10))
+	}
+	for _, include := range params.Include {
+		query.Add("include", include)
+	}
+	for _, exclude := range params.Exclude {
+		query.Add("exclude", exclude)
+	}
+	if params.Instance != "" {
+		query.Set("instance", params.Instance)
+	}
+	if params.Container != "" {
+		query.Set("container", params.Container)
+	}
+	if params.Timestamps {
+		query.Set("timestamps", "true")
+	}
+
+	if len(query) == 0 {
+		return []string{}
+	}
+	return []string{query.Encode()}
+}
+
+// volumeDetails is a helper for printAppDetails. It formats the bound volumes
+// and their mount paths.
+func volumeDetails(mounts models.VolumeMounts) string {
+	volumes := []string{}
+	for _, name := range mounts.Names() {
+		volumes = append(volumes, fmt.Sprintf("%s (%s)", name, mounts[name]))
+	}
+	return strings.Join(volumes, ", ")
+}
+
+// certificateDetails is a helper for printAppDetails. It formats the source and
+// expiry of a route's certificate.
+func certificateDetails(cert models.RouteCertificate) string {
+	source := cert.Source
+	if source == "" {
+		source = "default issuer"
+	}
+
+	if cert.Expires == "" {
+		return fmt.Sprintf("%s: %s, not issued yet", cert.Route, source)
+	}
+
+	expires, err := time.Parse(time.RFC3339, cert.Expires)
+	if err != nil {
+		return fmt.Sprintf("%s: %s, expires %s", cert.Route, source, cert.Expires)
+	}
+
+	remaining := time.Until(expires)
+	if remaining < 0 {
+		return fmt.Sprintf("%s: %s, EXPIRED %s", cert.Route, source, expires.Format("2006-01-02"))
+	}
+
+	return fmt.Sprintf("%s: %s, expires %s (in %d days)", cert.Route, source,
+		expires.Format("2006-01-02"), int(remaining.Hours()/24))
+}
+
 func (c *EpinioClient) printReplicaDetails(app models.App) error {
 	if app.Workload == nil {
 		return nil
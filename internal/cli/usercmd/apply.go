@@ -0,0 +1,206 @@
+package usercmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/epinio/epinio/helpers"
+	"github.com/epinio/epinio/internal/duration"
+	"github.com/epinio/epinio/internal/manifest"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Apply brings the targeted namespace to the state declared by the stack. Declared
+// services and applications are created, or updated, and applications are restaged or
+// redeployed as needed. When pruning, services and applications not declared by the
+// stack are deleted. The plan is shown first. With dryRun nothing else is done.
+func (c *EpinioClient) Apply(ctx context.Context, stack models.StackManifest, prune, dryRun bool) error {
+	log := c.Log.WithName("Apply").WithValues("Namespace", c.Config.Namespace, "Stack", stack.Self)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Note().
+		WithStringValue("Stack", stack.Self).
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithIntValue("Services", len(stack.Services)).
+		WithIntValue("Applications", len(stack.Applications)).
+		WithBoolValue("Prune", prune).
+		Msg("Applying stack")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	if err := validateStack(stack, prune); err != nil {
+		return err
+	}
+
+	details.Info("list services")
+	services, err := c.API.Services(c.Config.Namespace)
+	if err != nil {
+		return err
+	}
+
+	details.Info("list applications")
+	apps, err := c.API.Apps(c.Config.Namespace)
+	if err != nil {
+		return err
+	}
+
+	plan := manifest.NewPlan(stack, services, apps, prune)
+
+	if len(plan) == 0 {
+		c.ui.Success().Msg("Nothing to do, the namespace is up to date.")
+		return c.ui.Result(plan)
+	}
+
+	msg := c.ui.Note().WithTable("Kind", "Name", "Action", "Reason")
+	for _, step := range plan {
+		msg = msg.WithTableRow(step.Kind, step.Name, step.Action, step.Reason)
+	}
+	msg.Msg("Plan:")
+
+	if dryRun {
+		return c.ui.Result(plan)
+	}
+
+	c.ui.Exclamation().
+		Timeout(duration.UserAbort()).
+		Msg("Hit Enter to continue or Ctrl+C to abort (the plan will be executed automatically in 5 seconds)")
+
+	desiredServices := map[string]models.ServiceCreateRequest{}
+	for _, service := range stack.Services {
+		desiredServices[service.Name] = service
+	}
+	currentServices := map[string]models.ServiceResponse{}
+	for _, service := range services {
+		currentServices[service.Meta.Name] = service
+	}
+	desiredApps := map[string]models.ApplicationManifest{}
+	for _, app := range stack.Applications {
+		desiredApps[app.Name] = app
+	}
+
+	for i, step := range plan {
+		c.ui.Normal().Msg(fmt.Sprintf("Step %d/%d: %s %s %s ...", i+1, len(plan), step.Action, step.Kind, step.Name))
+		details.Info("execute", "step", step)
+
+		if step.Kind == manifest.KindService {
+			err = c.applyService(step, desiredServices[step.Name], currentServices[step.Name])
+		} else {
+			err = c.applyApp(step, desiredApps[step.Name])
+		}
+		if err != nil {
+			return errors.Wrapf(err, "%s %s %s", step.Action, step.Kind, step.Name)
+		}
+	}
+
+	c.ui.Success().
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithStringValue("Steps", strconv.Itoa(len(plan))).
+		Msg("Stack applied.")
+
+	return c.ui.Result(plan)
+}
+
+// applyService executes a step of the plan for a service.
+func (c *EpinioClient) applyService(step manifest.Step, desired models.ServiceCreateRequest, current models.ServiceResponse) error {
+	var err error
+
+	switch step.Action {
+	case manifest.ActionCreate:
+		_, err = c.API.ServiceCreate(desired, c.Config.Namespace)
+	case manifest.ActionUpdate:
+		_, err = c.API.ServiceUpdate(manifest.ServiceUpdate(desired, current.Configuration),
+			c.Config.Namespace, step.Name)
+	case manifest.ActionDelete:
+		// The applications bound to the service are deleted already, see validateStack
+		_, err = c.API.ServiceDelete(models.ServiceDeleteRequest{Unbind: true}, c.Config.Namespace, step.Name,
+			func(response *http.Response, bodyBytes []byte, err error) error {
+				return err
+			})
+	default:
+		err = fmt.Errorf("unknown action %s", step.Action)
+	}
+
+	return err
+}
+
+// applyApp executes a step of the plan for an application, using the building blocks
+// of Push.
+func (c *EpinioClient) applyApp(step manifest.Step, desired models.ApplicationManifest) error {
+	log := c.Log.WithName("Apply").WithValues("Application", step.Name, "Action", step.Action)
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	appRef := models.NewAppRef(step.Name, c.Config.Namespace)
+
+	switch step.Action {
+	case manifest.ActionCreate:
+		if err := c.appUpsert(details, appRef, desired.Configuration); err != nil {
+			return err
+		}
+	case manifest.ActionUpdate, manifest.ActionRestage, manifest.ActionRedeploy:
+		if _, err := c.API.AppUpdate(desired.Configuration, appRef.Namespace, appRef.Name); err != nil {
+			return err
+		}
+	case manifest.ActionDelete:
+		_, err := c.API.AppDelete(appRef.Namespace, appRef.Name)
+		return err
+	default:
+		return fmt.Errorf("unknown action %s", step.Action)
+	}
+
+	if step.Action == manifest.ActionUpdate {
+		return nil
+	}
+
+	blobUID, err := c.appSources(log, details, appRef, desired.Origin, helpers.TarOptions{})
+	if err != nil {
+		return err
+	}
+
+	var stageResponse *models.StageResponse
+	if desired.Origin.Kind != models.OriginContainer {
+		stageResponse, err = c.appStage(log, details, appRef, blobUID, desired.Staging.Builder)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = c.appDeploy(details, appRef, desired.Origin, stageResponse)
+	return err
+}
+
+// validateStack checks the names of the applications, and that, when pruning, the
+// applications bind only services declared by the stack, as pruning deletes the others.
+func validateStack(stack models.StackManifest, prune bool) error {
+	declared := map[string]bool{}
+	for _, service := range stack.Services {
+		declared[service.Name] = true
+	}
+
+	for _, app := range stack.Applications {
+		errorMsgs := validation.IsDNS1123Subdomain(app.Name)
+		if len(errorMsgs) > 0 {
+			return fmt.Errorf("app name %s incorrect: %s", app.Name, strings.Join(errorMsgs, "\n"))
+		}
+
+		if !prune {
+			continue
+		}
+		for _, service := range app.Configuration.Services {
+			if !declared[service] {
+				return fmt.Errorf("application %s binds the undeclared service %s, which pruning deletes",
+					app.Name, service)
+			}
+		}
+	}
+
+	return nil
+}
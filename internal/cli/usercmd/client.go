@@ -28,6 +28,10 @@ func New() (*EpinioClient, error) {
 		return nil, err
 	}
 
+	if err := termui.ValidateOutputFormat(termui.OutputFormat()); err != nil {
+		return nil, err
+	}
+
 	uiUI := termui.NewUI()
 	apiClient, err := getEpinioAPIClient()
 	if err != nil {
@@ -72,8 +76,8 @@ func getEpinioAPIClient() (*epinioapi.Client, error) {
 		return epinioClientMemo, nil
 	}
 
-	// Check for information cached in the Epinio configuration,
-	// and return if such is found. Cache into memory as well.
+	// Check for information cached in the active context of the Epinio
+	// configuration, and return if such is found. Cache into memory as well.
 	log.Info("query configuration")
 
 	cfg, err := config.Load()
@@ -90,5 +94,6 @@ func getEpinioAPIClient() (*epinioapi.Client, error) {
 		return epinioClient, nil
 	}
 
-	return nil, errors.New("Epinio no longer queries the cluster, please run epinio config update or ask your operator for help")
+	return nil, errors.Errorf("context '%s' has no Epinio location. Epinio no longer queries the cluster, please run epinio config update or ask your operator for help",
+		cfg.ActiveContext())
 }
@@ -0,0 +1,53 @@
+package usercmd
+
+import (
+	"github.com/epinio/epinio/internal/manifest"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// AppDiff compares the manifest with the application of the same name, in the targeted
+// namespace, and shows the differences. The result is true if the application drifted
+// from the manifest.
+func (c *EpinioClient) AppDiff(m models.ApplicationManifest) (bool, error) {
+	log := c.Log.WithName("AppDiff").WithValues("Namespace", c.Config.Namespace, "Application", m.Name)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Note().
+		WithStringValue("Manifest", m.Self).
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithStringValue("Application", m.Name).
+		Msg("Comparing manifest with the deployed application")
+
+	if err := c.TargetOk(); err != nil {
+		return false, err
+	}
+
+	details.Info("show application")
+
+	app, err := c.API.AppShow(c.Config.Namespace, m.Name)
+	if err != nil {
+		return false, err
+	}
+
+	diffs := manifest.Diff(m, app)
+	drift := len(diffs) > 0
+
+	if c.ui.MachineReadable() {
+		return drift, c.ui.Result(diffs)
+	}
+
+	if !drift {
+		c.ui.Success().Msg("No drift, the application matches the manifest.")
+		return false, nil
+	}
+
+	msg := c.ui.Exclamation().WithTable("", "Section", "Field", "Deployed", "Manifest")
+	for _, diff := range diffs {
+		msg = msg.WithTableRow(diff.Change, diff.Section, diff.Field, diff.Deployed, diff.Manifest)
+	}
+	msg.Msg("The application drifted from the manifest:")
+
+	return true, nil
+}
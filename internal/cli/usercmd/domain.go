@@ -0,0 +1,125 @@
+package usercmd
+
+import (
+	"strconv"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Domains lists the registered domains, and the namespaces they are assigned to
+func (c *EpinioClient) Domains() error {
+	log := c.Log.WithName("Domains")
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Note().Msg("Listing domains")
+
+	details.Info("list domains")
+
+	domains, err := c.API.Domains()
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		return c.ui.Result(domains)
+	}
+
+	if len(domains) == 0 {
+		c.ui.Normal().Msg("No domains registered. All namespaces use the main domain.")
+		return nil
+	}
+
+	msg := c.ui.Success().WithTable("Name", "Namespace", "Default")
+
+	for _, domain := range domains {
+		msg = msg.WithTableRow(domain.Name, domain.Namespace, strconv.FormatBool(domain.Default))
+	}
+
+	msg.Msg("Epinio Domains:")
+
+	return nil
+}
+
+// CreateDomain registers a domain, optionally assigned to a namespace
+func (c *EpinioClient) CreateDomain(name, namespace string, isDefault bool) error {
+	log := c.Log.WithName("CreateDomain").WithValues("Domain", name, "Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", name).
+		WithStringValue("Namespace", namespace).
+		WithBoolValue("Default", isDefault).
+		Msg("Registering domain...")
+
+	resp, err := c.API.DomainCreate(models.Domain{
+		Name:      name,
+		Namespace: namespace,
+		Default:   isDefault,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Domain registered.")
+
+	return c.ui.Result(resp)
+}
+
+// AssignDomain assigns a registered domain to a namespace. An empty namespace
+// releases the domain.
+func (c *EpinioClient) AssignDomain(name, namespace string, isDefault bool) error {
+	log := c.Log.WithName("AssignDomain").WithValues("Domain", name, "Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	if namespace == "" {
+		c.ui.Note().
+			WithStringValue("Name", name).
+			Msg("Releasing domain...")
+	} else {
+		c.ui.Note().
+			WithStringValue("Name", name).
+			WithStringValue("Namespace", namespace).
+			WithBoolValue("Default", isDefault).
+			Msg("Assigning domain...")
+	}
+
+	resp, err := c.API.DomainAssign(name, models.DomainAssignRequest{
+		Namespace: namespace,
+		Default:   isDefault,
+	})
+	if err != nil {
+		return err
+	}
+
+	if namespace == "" {
+		c.ui.Success().Msg("Domain released.")
+	} else {
+		c.ui.Success().Msg("Domain assigned.")
+	}
+
+	return c.ui.Result(resp)
+}
+
+// DeleteDomain removes a domain from the registry
+func (c *EpinioClient) DeleteDomain(name string) error {
+	log := c.Log.WithName("DeleteDomain").WithValues("Domain", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", name).
+		Msg("Removing domain...")
+
+	resp, err := c.API.DomainDelete(name)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Domain removed.")
+
+	return c.ui.Result(resp)
+}
@@ -0,0 +1,191 @@
+package usercmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// AppDrains lists the log drains of the named application, and the state of
+// the forwarding to them
+func (c *EpinioClient) AppDrains(ctx context.Context, appName string) error {
+	log := c.Log.WithName("AppDrains").WithValues("Namespace", c.Config.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Listing log drains")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	drains, err := c.API.AppDrains(c.Config.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		return c.ui.Result(drains)
+	}
+
+	c.showDrains(drains)
+	return nil
+}
+
+// AppDrainAdd adds a log drain for the url to the named application
+func (c *EpinioClient) AppDrainAdd(ctx context.Context, appName, url string) error {
+	log := c.Log.WithName("AppDrainAdd").WithValues("Namespace", c.Config.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithStringValue("Application", appName).
+		WithStringValue("Drain", url).
+		Msg("Adding log drain...")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	resp, err := c.API.AppDrainCreate(models.DrainCreateRequest{URL: url}, c.Config.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().WithStringValue("ID", models.DrainID(url)).Msg("Log drain added.")
+	return c.ui.Result(resp)
+}
+
+// AppDrainRemove removes the log drain, given by url or id, from the named application
+func (c *EpinioClient) AppDrainRemove(ctx context.Context, appName, drain string) error {
+	log := c.Log.WithName("AppDrainRemove").WithValues("Namespace", c.Config.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithStringValue("Application", appName).
+		WithStringValue("Drain", drain).
+		Msg("Removing log drain...")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	resp, err := c.API.AppDrainDelete(c.Config.Namespace, appName, drainID(drain))
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Log drain removed.")
+	return c.ui.Result(resp)
+}
+
+// NamespaceDrains lists the log drains of the namespace, and the state of the
+// forwarding to them
+func (c *EpinioClient) NamespaceDrains(namespace string) error {
+	log := c.Log.WithName("NamespaceDrains").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg("Listing log drains")
+
+	drains, err := c.API.NamespaceDrains(namespace)
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		return c.ui.Result(drains)
+	}
+
+	c.showDrains(drains)
+	return nil
+}
+
+// NamespaceDrainAdd adds a log drain for the url to the namespace, covering
+// all its applications
+func (c *EpinioClient) NamespaceDrainAdd(namespace, url string) error {
+	log := c.Log.WithName("NamespaceDrainAdd").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		WithStringValue("Drain", url).
+		Msg("Adding log drain...")
+
+	resp, err := c.API.NamespaceDrainCreate(models.DrainCreateRequest{URL: url}, namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().WithStringValue("ID", models.DrainID(url)).Msg("Log drain added.")
+	return c.ui.Result(resp)
+}
+
+// NamespaceDrainRemove removes the log drain, given by url or id, from the namespace
+func (c *EpinioClient) NamespaceDrainRemove(namespace, drain string) error {
+	log := c.Log.WithName("NamespaceDrainRemove").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		WithStringValue("Drain", drain).
+		Msg("Removing log drain...")
+
+	resp, err := c.API.NamespaceDrainDelete(namespace, drainID(drain))
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Log drain removed.")
+	return c.ui.Result(resp)
+}
+
+// showDrains is a helper for AppDrains and NamespaceDrains. It shows the
+// drains as table.
+func (c *EpinioClient) showDrains(drains models.DrainList) {
+	if len(drains) == 0 {
+		c.ui.Normal().Msg("No log drains found")
+		return
+	}
+
+	msg := c.ui.Success().WithTable("ID", "URL", "Status", "Delivered", "Dropped", "Pending", "Last Delivery", "Last Error")
+
+	for _, drain := range drains {
+		status := "inactive"
+		if drain.Status.Active {
+			status = "active"
+			if drain.Status.LastError != "" {
+				status = "failing"
+			}
+		}
+
+		msg = msg.WithTableRow(drain.ID, drain.URL, status,
+			strconv.FormatInt(drain.Status.Delivered, 10),
+			strconv.FormatInt(drain.Status.Dropped, 10),
+			strconv.Itoa(drain.Status.Pending),
+			drain.Status.LastDelivery,
+			drain.Status.LastError)
+	}
+
+	msg.Msg("Log Drains:")
+}
+
+// drainID returns the id of the drain given by url or id.
+func drainID(drain string) string {
+	if strings.Contains(drain, "://") {
+		return models.DrainID(drain)
+	}
+	return drain
+}
@@ -7,7 +7,8 @@ import (
 )
 
 // EnvList displays a table of all environment variables and their
-// values for the named application.
+// values for the named application, including the defaults inherited
+// from the namespace, and where each value comes from.
 func (c *EpinioClient) EnvList(ctx context.Context, appName string) error {
 	log := c.Log.WithName("EnvList")
 	log.Info("start")
@@ -27,10 +28,29 @@ func (c *EpinioClient) EnvList(ctx context.Context, appName string) error {
 		return err
 	}
 
-	msg := c.ui.Success().WithTable("Variable", "Value")
+	if c.ui.MachineReadable() {
+		return c.ui.Result(eVariables)
+	}
+
+	defaults, err := c.API.NamespaceEnvList(c.Config.Namespace)
+	if err != nil {
+		return err
+	}
+
+	msg := c.ui.Success().WithTable("Variable", "Value", "Origin")
 
-	for _, ev := range eVariables.List() {
-		msg = msg.WithTableRow(ev.Name, ev.Value)
+	for _, ev := range defaults.Overlay(eVariables).List() {
+		_, isDefault := defaults[ev.Name]
+		_, isApp := eVariables[ev.Name]
+
+		origin := "namespace"
+		if isApp && isDefault {
+			origin = "application (overrides namespace)"
+		} else if isApp {
+			origin = "application"
+		}
+
+		msg = msg.WithTableRow(ev.Name, ev.Value, origin)
 	}
 
 	msg.Msg("Ok")
@@ -58,13 +78,13 @@ func (c *EpinioClient) EnvSet(ctx context.Context, appName, envName, envValue st
 	request := models.EnvVariableMap{}
 	request[envName] = envValue
 
-	_, err := c.API.EnvSet(request, c.Config.Namespace, appName)
+	resp, err := c.API.EnvSet(request, c.Config.Namespace, appName)
 	if err != nil {
 		return err
 	}
 
 	c.ui.Success().Msg("OK")
-	return nil
+	return c.ui.Result(resp)
 }
 
 // EnvShow shows the value of the specified environment variable in
@@ -89,6 +109,10 @@ func (c *EpinioClient) EnvShow(ctx context.Context, appName, envName string) err
 		return err
 	}
 
+	if c.ui.MachineReadable() {
+		return c.ui.Result(eVariable)
+	}
+
 	c.ui.Success().
 		WithStringValue("Value", eVariable.Value).
 		Msg("OK")
@@ -113,14 +137,14 @@ func (c *EpinioClient) EnvUnset(ctx context.Context, appName, envName string) er
 		return err
 	}
 
-	_, err := c.API.EnvUnset(c.Config.Namespace, appName, envName)
+	resp, err := c.API.EnvUnset(c.Config.Namespace, appName, envName)
 	if err != nil {
 		return err
 	}
 
 	c.ui.Success().Msg("OK")
 
-	return nil
+	return c.ui.Result(resp)
 }
 
 // EnvMatching retrieves all environment variables in the cluster, for
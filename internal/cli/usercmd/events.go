@@ -0,0 +1,194 @@
+package usercmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// eventsInterval is the time between the polls of `app events --follow`
+const eventsInterval = 2 * time.Second
+
+// AppEvents shows the events of the resources of the named application, and the
+// state of its instances, with the last container termination of each. When
+// following, the command polls for new events until interrupted. In the
+// machine-readable formats new events and terminations are printed as
+// separate documents.
+func (c *EpinioClient) AppEvents(ctx context.Context, appName string, follow bool) error {
+	log := c.Log.WithName("AppEvents").WithValues("Namespace", c.Config.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Show application events")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	resp, err := c.API.AppEvents(c.Config.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		if err := c.ui.Result(resp); err != nil {
+			return err
+		}
+	} else {
+		c.printInstanceDiagnostics(resp.Instances)
+		c.printEvents(resp.Events)
+	}
+
+	if !follow {
+		return nil
+	}
+
+	seen := eventKeys(resp.Events)
+	terminated := terminationKeys(resp.Instances)
+
+	ticker := time.NewTicker(eventsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		resp, err := c.API.AppEvents(c.Config.Namespace, appName)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range resp.Events {
+			key := eventKey(event)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if c.ui.MachineReadable() {
+				if err := c.ui.Result(event); err != nil {
+					return err
+				}
+				continue
+			}
+
+			msg := c.ui.Normal()
+			if event.Type != "Normal" {
+				msg = c.ui.Exclamation()
+			}
+			msg.Msg(fmt.Sprintf("%s %s %s %s/%s: %s", event.LastSeen, event.Type, event.Reason,
+				event.Kind, event.Object, event.Message))
+		}
+
+		for _, instance := range resp.Instances {
+			key := terminationKey(instance)
+			if key == "" || terminated[key] {
+				continue
+			}
+			terminated[key] = true
+
+			if c.ui.MachineReadable() {
+				if err := c.ui.Result(instance); err != nil {
+					return err
+				}
+				continue
+			}
+
+			c.ui.Exclamation().Msg(fmt.Sprintf("%s %s terminated: %s",
+				instance.LastTermination.FinishedAt, instance.Name, describeTermination(instance.LastTermination)))
+		}
+	}
+}
+
+// printInstanceDiagnostics is a helper for AppEvents. It shows the state of the
+// instances as table.
+func (c *EpinioClient) printInstanceDiagnostics(instances []models.InstanceDiagnostics) {
+	if len(instances) == 0 {
+		c.ui.Normal().Msg("No instances found")
+		return
+	}
+
+	msg := c.ui.Success().WithTable("Name", "Phase", "Ready", "Restarts", "Waiting", "Last Termination")
+	for _, instance := range instances {
+		msg = msg.WithTableRow(
+			instance.Name,
+			instance.Phase,
+			strconv.FormatBool(instance.Ready),
+			strconv.Itoa(int(instance.Restarts)),
+			instance.Waiting,
+			describeTermination(instance.LastTermination),
+		)
+	}
+	msg.Msg("Instances:")
+}
+
+// printEvents is a helper for AppEvents. It shows the events as table.
+func (c *EpinioClient) printEvents(events models.AppEventList) {
+	if len(events) == 0 {
+		c.ui.Normal().Msg("No events found")
+		return
+	}
+
+	msg := c.ui.Success().WithTable("Last Seen", "Type", "Reason", "Object", "Count", "Message")
+	for _, event := range events {
+		msg = msg.WithTableRow(event.LastSeen, event.Type, event.Reason,
+			event.Kind+"/"+event.Object, strconv.Itoa(int(event.Count)), event.Message)
+	}
+	msg.Msg("Events:")
+}
+
+// describeTermination returns a short description of the termination, with
+// the out-of-memory kills called out.
+func describeTermination(termination *models.ContainerTermination) string {
+	if termination == nil {
+		return ""
+	}
+
+	result := fmt.Sprintf("%s, exit code %d", termination.Reason, termination.ExitCode)
+	if termination.OOMKilled {
+		result = fmt.Sprintf("out of memory (OOMKilled), exit code %d", termination.ExitCode)
+	}
+	if termination.FinishedAt != "" {
+		result += ", at " + termination.FinishedAt
+	}
+	return result
+}
+
+// eventKey identifies an occurrence of an event. Repetitions of an event change
+// its count, and are new occurrences.
+func eventKey(event models.AppEvent) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%d", event.Kind, event.Object, event.Reason, event.Message, event.Count)
+}
+
+func eventKeys(events models.AppEventList) map[string]bool {
+	result := map[string]bool{}
+	for _, event := range events {
+		result[eventKey(event)] = true
+	}
+	return result
+}
+
+// terminationKey identifies the last termination of the instance, if any.
+func terminationKey(instance models.InstanceDiagnostics) string {
+	if instance.LastTermination == nil {
+		return ""
+	}
+	return instance.Name + "/" + instance.LastTermination.FinishedAt
+}
+
+func terminationKeys(instances []models.InstanceDiagnostics) map[string]bool {
+	result := map[string]bool{}
+	for _, instance := range instances {
+		result[terminationKey(instance)] = true
+	}
+	return result
+}
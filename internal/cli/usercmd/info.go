@@ -15,6 +15,10 @@ func (c *EpinioClient) Info() error {
 		return err
 	}
 
+	if c.ui.MachineReadable() {
+		return c.ui.Result(v)
+	}
+
 	c.ui.Success().
 		WithStringValue("Platform", v.Platform).
 		WithStringValue("Kubernetes Version", v.KubeVersion).
@@ -0,0 +1,65 @@
+package usercmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/internal/cli/logprinter"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// NamespaceLogs streams the logs of the named applications of the namespace,
+// interleaved. No names streams the logs of all applications of the namespace.
+// An empty namespace is the targeted namespace. Each line starts with the name
+// of its application. See AppLogs for the handling of the interrupt channel,
+// and the output formats.
+func (c *EpinioClient) NamespaceLogs(namespace string, apps []string, follow bool, params models.LogParameters, output string, interrupt chan bool) error {
+	if namespace == "" {
+		if err := c.TargetOk(); err != nil {
+			return err
+		}
+		namespace = c.Config.Namespace
+	}
+
+	log := c.Log.WithName("NamespaceLogs").WithValues("Namespace", namespace, "Applications", apps)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	printer, err := logprinter.New(output, logprinter.DefaultMultiAppTemplate())
+	if err != nil {
+		return err
+	}
+	params = logOutputParameters(params, output)
+
+	// Structured output is for other tools, keep it free of decorations
+	if !printer.Plain {
+		msg := c.ui.Note().
+			WithStringValue("Namespace", namespace)
+		if len(apps) > 0 {
+			msg = msg.WithStringValue("Applications", strings.Join(apps, ", "))
+		}
+		msg.Msg("Streaming namespace logs")
+	}
+
+	details.Info("namespace logs")
+
+	token, err := c.API.AuthToken()
+	if err != nil {
+		return err
+	}
+
+	var urlArgs = []string{}
+	urlArgs = append(urlArgs, fmt.Sprintf("follow=%t", follow))
+	urlArgs = append(urlArgs, fmt.Sprintf("authtoken=%s", token))
+	for _, app := range apps {
+		urlArgs = append(urlArgs, fmt.Sprintf("app=%s", url.QueryEscape(app)))
+	}
+	urlArgs = append(urlArgs, logQuery(params)...)
+
+	endpoint := api.WsRoutes.Path("NamespaceLogs", namespace)
+
+	return c.streamLogs(endpoint, urlArgs, printer, params.Timestamps, interrupt)
+}
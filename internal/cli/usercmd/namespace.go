@@ -3,8 +3,11 @@ package usercmd
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/epinio/epinio/internal/quota"
+	"github.com/epinio/epinio/internal/routes"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 
 	"github.com/pkg/errors"
@@ -26,14 +29,14 @@ func (c *EpinioClient) CreateNamespace(namespace string) error {
 		return fmt.Errorf("%s: %s", "namespace name incorrect", strings.Join(errorMsgs, "\n"))
 	}
 
-	_, err := c.API.NamespaceCreate(models.NamespaceCreateRequest{Name: namespace})
+	resp, err := c.API.NamespaceCreate(models.NamespaceCreateRequest{Name: namespace})
 	if err != nil {
 		return err
 	}
 
 	c.ui.Success().Msg("Namespace created.")
 
-	return nil
+	return c.ui.Result(resp)
 }
 
 // NamespacesMatching returns all Epinio namespaces having the specified prefix in their name
@@ -71,6 +74,10 @@ func (c *EpinioClient) Namespaces() error {
 	}
 
 	sort.Sort(namespaces)
+	if c.ui.MachineReadable() {
+		return c.ui.Result(namespaces)
+	}
+
 	msg := c.ui.Success().WithTable("Name", "Applications", "Services")
 
 	for _, namespace := range namespaces {
@@ -96,6 +103,9 @@ func (c *EpinioClient) Target(namespace string) error {
 
 	if namespace == "" {
 		details.Info("query config")
+		if c.ui.MachineReadable() {
+			return c.ui.Result(map[string]string{"namespace": c.Config.Namespace})
+		}
 		c.ui.Success().
 			WithStringValue("Currently targeted namespace", c.Config.Namespace).
 			Msg("")
@@ -135,14 +145,14 @@ func (c *EpinioClient) DeleteNamespace(namespace string) error {
 		WithStringValue("Name", namespace).
 		Msg("Deleting namespace...")
 
-	_, err := c.API.NamespaceDelete(namespace)
+	resp, err := c.API.NamespaceDelete(namespace)
 	if err != nil {
 		return err
 	}
 
 	c.ui.Success().Msg("Namespace deleted.")
 
-	return nil
+	return c.ui.Result(resp)
 }
 
 // ShowNamepsace shows a Namespace
@@ -160,6 +170,10 @@ func (c *EpinioClient) ShowNamespace(namespace string) error {
 		return err
 	}
 
+	if c.ui.MachineReadable() {
+		return c.ui.Result(space)
+	}
+
 	msg := c.ui.Success().WithTable("Key", "Value")
 
 	sort.Strings(space.Apps)
@@ -173,3 +187,518 @@ func (c *EpinioClient) ShowNamespace(namespace string) error {
 
 	return nil
 }
+
+// ShowNamespaceQuota shows the quota of a namespace, and the current usage
+func (c *EpinioClient) ShowNamespaceQuota(namespace string) error {
+	log := c.Log.WithName("ShowNamespaceQuota").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg("Showing namespace quota...")
+
+	resp, err := c.API.NamespaceQuota(namespace)
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		return c.ui.Result(resp)
+	}
+
+	limit := func(value string) string {
+		if value == "" {
+			return "unlimited"
+		}
+		return value
+	}
+	count := func(value int32) string {
+		if value == 0 {
+			return "unlimited"
+		}
+		return fmt.Sprintf("%d", value)
+	}
+	instance := func(value, defaultValue string) string {
+		if value == "" {
+			return defaultValue + " (default)"
+		}
+		return value
+	}
+
+	q := resp.Quota
+	u := resp.Usage
+
+	msg := c.ui.Success().WithTable("Resource", "Limit", "Used").
+		WithTableRow("CPU", limit(q.CPU), u.CPU).
+		WithTableRow("Memory", limit(q.Memory), u.Memory).
+		WithTableRow("Applications", count(q.Apps), fmt.Sprintf("%d", u.Apps)).
+		WithTableRow("Services", count(q.Services), fmt.Sprintf("%d", u.Services)).
+		WithTableRow("Instances", count(q.Instances), fmt.Sprintf("%d", u.Instances))
+
+	if q.CPU != "" || q.Memory != "" {
+		msg = msg.WithTable("Per Instance", "Limit").
+			WithTableRow("CPU", instance(q.InstanceCPU, quota.DefaultInstanceCPU)).
+			WithTableRow("Memory", instance(q.InstanceMemory, quota.DefaultInstanceMemory))
+	}
+
+	msg.Msg("Quota:")
+
+	return nil
+}
+
+// SetNamespaceQuota replaces the quota of a namespace
+func (c *EpinioClient) SetNamespaceQuota(namespace string, limits models.NamespaceQuota) error {
+	log := c.Log.WithName("SetNamespaceQuota").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		WithStringValue("CPU", limits.CPU).
+		WithStringValue("Memory", limits.Memory).
+		WithIntValue("Applications", int(limits.Apps)).
+		WithIntValue("Services", int(limits.Services)).
+		WithIntValue("Instances", int(limits.Instances)).
+		Msg("Setting namespace quota...")
+
+	resp, err := c.API.NamespaceQuotaSet(namespace, limits)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace quota set.")
+
+	return c.ui.Result(resp)
+}
+
+// DeleteNamespaceQuota removes the quota of a namespace
+func (c *EpinioClient) DeleteNamespaceQuota(namespace string) error {
+	log := c.Log.WithName("DeleteNamespaceQuota").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg("Removing namespace quota...")
+
+	resp, err := c.API.NamespaceQuotaDelete(namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace quota removed.")
+
+	return c.ui.Result(resp)
+}
+
+// ShowNamespaceNetwork shows the network isolation of a namespace
+func (c *EpinioClient) ShowNamespaceNetwork(namespace string) error {
+	log := c.Log.WithName("ShowNamespaceNetwork").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg("Showing namespace network...")
+
+	network, err := c.API.NamespaceNetwork(namespace)
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		return c.ui.Result(network)
+	}
+
+	allowed := "none"
+	if len(network.AllowFrom) > 0 {
+		allowed = strings.Join(network.AllowFrom, ", ")
+	}
+
+	c.ui.Success().
+		WithBoolValue("Isolated", network.Isolated).
+		WithStringValue("Allowed Namespaces", allowed).
+		Msg("Network:")
+
+	return nil
+}
+
+// IsolateNamespaceNetwork isolates the network of a namespace from the other
+// namespaces. Namespaces already allowed to reach it keep their access.
+func (c *EpinioClient) IsolateNamespaceNetwork(namespace string) error {
+	log := c.Log.WithName("IsolateNamespaceNetwork").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg("Isolating namespace network...")
+
+	network, err := c.API.NamespaceNetwork(namespace)
+	if err != nil {
+		return err
+	}
+	network.Isolated = true
+
+	resp, err := c.API.NamespaceNetworkSet(namespace, network)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace network isolated.")
+
+	return c.ui.Result(resp)
+}
+
+// OpenNamespaceNetwork removes the network isolation of a namespace
+func (c *EpinioClient) OpenNamespaceNetwork(namespace string) error {
+	log := c.Log.WithName("OpenNamespaceNetwork").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg("Removing namespace network isolation...")
+
+	resp, err := c.API.NamespaceNetworkDelete(namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace network isolation removed.")
+
+	return c.ui.Result(resp)
+}
+
+// AllowNamespaceNetwork allows the specified namespaces to reach the
+// namespace. This isolates the namespace, if it was not isolated yet.
+func (c *EpinioClient) AllowNamespaceNetwork(namespace string, from []string) error {
+	log := c.Log.WithName("AllowNamespaceNetwork").WithValues("Namespace", namespace, "From", from)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		WithStringValue("From", strings.Join(from, ", ")).
+		Msg("Allowing namespace network access...")
+
+	network, err := c.API.NamespaceNetwork(namespace)
+	if err != nil {
+		return err
+	}
+	network.Isolated = true
+
+	for _, name := range from {
+		known := false
+		for _, allowed := range network.AllowFrom {
+			if allowed == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			network.AllowFrom = append(network.AllowFrom, name)
+		}
+	}
+
+	resp, err := c.API.NamespaceNetworkSet(namespace, network)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace network access allowed.")
+
+	return c.ui.Result(resp)
+}
+
+// RevokeNamespaceNetwork removes the access of the specified namespaces to the
+// namespace. The namespace stays isolated.
+func (c *EpinioClient) RevokeNamespaceNetwork(namespace string, from []string) error {
+	log := c.Log.WithName("RevokeNamespaceNetwork").WithValues("Namespace", namespace, "From", from)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		WithStringValue("From", strings.Join(from, ", ")).
+		Msg("Revoking namespace network access...")
+
+	network, err := c.API.NamespaceNetwork(namespace)
+	if err != nil {
+		return err
+	}
+	if !network.Isolated {
+		return errors.New("namespace network is not isolated, there is no access to revoke")
+	}
+
+	revoked := map[string]bool{}
+	for _, name := range from {
+		revoked[name] = true
+	}
+	allowFrom := []string{}
+	for _, allowed := range network.AllowFrom {
+		if !revoked[allowed] {
+			allowFrom = append(allowFrom, allowed)
+		}
+	}
+	network.AllowFrom = allowFrom
+
+	resp, err := c.API.NamespaceNetworkSet(namespace, network)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace network access revoked.")
+
+	return c.ui.Result(resp)
+}
+
+// NamespaceEnvList displays a table of the default environment variables of a namespace
+func (c *EpinioClient) NamespaceEnvList(namespace string) error {
+	log := c.Log.WithName("NamespaceEnvList").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		Msg("Show Namespace Environment")
+
+	eVariables, err := c.API.NamespaceEnvList(namespace)
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		return c.ui.Result(eVariables)
+	}
+
+	msg := c.ui.Success().WithTable("Variable", "Value")
+
+	for _, ev := range eVariables.List() {
+		msg = msg.WithTableRow(ev.Name, ev.Value)
+	}
+
+	msg.Msg("Ok")
+	return nil
+}
+
+// NamespaceEnvSet adds or modifies a default environment variable of a namespace.
+// The workloads of the namespace are restarted.
+func (c *EpinioClient) NamespaceEnvSet(namespace, envName, envValue string) error {
+	log := c.Log.WithName("NamespaceEnvSet").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		WithStringValue("Variable", envName).
+		WithStringValue("Value", envValue).
+		Msg("Extend or modify namespace environment")
+
+	request := models.EnvVariableMap{}
+	request[envName] = envValue
+
+	resp, err := c.API.NamespaceEnvSet(request, namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("OK")
+	return c.ui.Result(resp)
+}
+
+// NamespaceEnvUnset removes a default environment variable from a namespace.
+// The workloads of the namespace are restarted.
+func (c *EpinioClient) NamespaceEnvUnset(namespace, envName string) error {
+	log := c.Log.WithName("NamespaceEnvUnset").WithValues("Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", namespace).
+		WithStringValue("Variable", envName).
+		Msg("Remove from namespace environment")
+
+	resp, err := c.API.NamespaceEnvUnset(namespace, envName)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("OK")
+	return c.ui.Result(resp)
+}
+
+// CloneNamespace creates the namespace dst as a copy of the namespace src. The
+// default environment, services and applications of the source are copied. The
+// routes of the applications are moved to the given domain. Without a domain
+// the name of the new namespace is inserted into each route, after the first
+// label. Active applications are deployed with the image they currently run,
+// without restaging. Kube secrets are not copied. The routes therefore lose
+// their basic-auth option, and certificates from secrets are replaced by the
+// default issuer, with a warning for each.
+func (c *EpinioClient) CloneNamespace(src, dst, domain string) error {
+	log := c.Log.WithName("CloneNamespace").WithValues("Source", src, "Target", dst, "Domain", domain)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Note().
+		WithStringValue("Source", src).
+		WithStringValue("Target", dst).
+		WithStringValue("Domain", domain).
+		Msg("Cloning namespace...")
+
+	errorMsgs := validation.IsDNS1123Subdomain(dst)
+	if len(errorMsgs) > 0 {
+		return fmt.Errorf("%s: %s", "namespace name incorrect", strings.Join(errorMsgs, "\n"))
+	}
+
+	// Read the entire source first, to not leave a partial copy behind
+	// when the source is not accessible.
+
+	details.Info("read source")
+
+	defaults, err := c.API.NamespaceEnvList(src)
+	if err != nil {
+		return errors.Wrapf(err, "namespace %s", src)
+	}
+
+	services, err := c.API.Services(src)
+	if err != nil {
+		return errors.Wrapf(err, "namespace %s", src)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Meta.Name < services[j].Meta.Name
+	})
+
+	apps, err := c.API.Apps(src)
+	if err != nil {
+		return errors.Wrapf(err, "namespace %s", src)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Meta.Name < apps[j].Meta.Name
+	})
+
+	c.ui.Normal().Msg(fmt.Sprintf("Creating namespace %s ...", dst))
+
+	_, err = c.API.NamespaceCreate(models.NamespaceCreateRequest{Name: dst})
+	if err != nil {
+		return err
+	}
+
+	if len(defaults) > 0 {
+		c.ui.Normal().Msg("Copying the default environment ...")
+
+		_, err = c.API.NamespaceEnvSet(defaults, dst)
+		if err != nil {
+			return errors.Wrap(err, "default environment")
+		}
+	}
+
+	for _, service := range services {
+		name := service.Meta.Name
+		c.ui.Normal().Msg(fmt.Sprintf("Copying service %s ...", name))
+
+		details.Info("show service", "name", name)
+		resp, err := c.API.ServiceShow(src, name)
+		if err != nil {
+			return errors.Wrapf(err, "service %s", name)
+		}
+
+		_, err = c.API.ServiceCreate(models.ServiceCreateRequest{
+			Name:        name,
+			Data:        resp.Configuration.Details,
+			Labels:      resp.Configuration.Labels,
+			Description: resp.Configuration.Description,
+		}, dst)
+		if err != nil {
+			return errors.Wrapf(err, "service %s", name)
+		}
+	}
+
+	for _, app := range apps {
+		name := app.Meta.Name
+		c.ui.Normal().Msg(fmt.Sprintf("Copying application %s ...", name))
+
+		configuration := app.Configuration
+		configuration.Routes = []string{}
+		for _, route := range app.Configuration.Routes {
+			clone, secret := cloneRoute(route, dst, domain)
+			if secret != "" {
+				c.ui.Exclamation().Msg(fmt.Sprintf("Application %s, route %s: dropped option basic-auth, secret %s is not copied",
+					name, clone, secret))
+			}
+			configuration.Routes = append(configuration.Routes, clone)
+		}
+		if app.Workload != nil {
+			for _, cert := range app.Workload.Certificates {
+				if !strings.HasPrefix(cert.Source, "secret=") {
+					continue
+				}
+				clone, _ := cloneRoute(cert.Route, dst, domain)
+				c.ui.Exclamation().Msg(fmt.Sprintf("Application %s, route %s: certificate secret %s is not copied, using the default issuer",
+					name, clone, strings.TrimPrefix(cert.Source, "secret=")))
+			}
+		}
+
+		_, err = c.API.AppCreate(models.ApplicationCreateRequest{
+			Name:          name,
+			Configuration: configuration,
+		}, dst)
+		if err != nil {
+			return errors.Wrapf(err, "application %s", name)
+		}
+
+		if app.Workload == nil || app.Workload.ImageURL == "" {
+			details.Info("skip deploy, inactive", "name", name)
+			continue
+		}
+
+		c.ui.Normal().Msg(fmt.Sprintf("Deploying application %s ...", name))
+
+		_, err = c.API.AppDeploy(models.DeployRequest{
+			App:      models.NewAppRef(name, dst),
+			Stage:    models.NewStage(app.Workload.StageID),
+			ImageURL: app.Workload.ImageURL,
+			Origin:   app.Origin,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "application %s", name)
+		}
+	}
+
+	if c.ui.MachineReadable() {
+		space, err := c.API.NamespaceShow(dst)
+		if err != nil {
+			return err
+		}
+		return c.ui.Result(space)
+	}
+
+	c.ui.Success().
+		WithStringValue("Source", src).
+		WithStringValue("Target", dst).
+		WithStringValue("Services", strconv.Itoa(len(services))).
+		WithStringValue("Applications", strconv.Itoa(len(apps))).
+		Msg("Namespace cloned.")
+
+	return nil
+}
+
+// cloneRoute is a helper for CloneNamespace. It moves the route to the domain,
+// or, without a domain, inserts the namespace after the first label of the host.
+// The basic-auth option is removed, as its secret is not copied. The name of
+// that secret is returned as well, empty if the route had none.
+func cloneRoute(route, namespace, domain string) (string, string) {
+	r := routes.FromString(route)
+	secret := r.Options.BasicAuthSecret
+	r.Options.BasicAuthSecret = ""
+	if domain == "" {
+		domain = namespace
+		if parent := r.ParentDomain(); parent != "" {
+			domain = namespace + "." + parent
+		}
+	}
+	return r.Rehost(domain).StringWithOptions(), secret
+}
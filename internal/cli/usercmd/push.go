@@ -15,12 +15,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/epinio/epinio/helpers"
+	"github.com/epinio/epinio/helpers/bytes"
 	"github.com/epinio/epinio/internal/duration"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 )
 
 type PushParams struct {
 	models.ApplicationManifest
+	Sources helpers.TarOptions // Sources to leave out of the upload of a path origin
 }
 
 // Push pushes an app
@@ -82,6 +84,10 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		}
 	}
 
+	if params.Configuration.Internal != nil {
+		msg = msg.WithBoolValue("Internal", *params.Configuration.Internal)
+	}
+
 	msg.Msg("About to push an application with the given setup")
 
 	c.ui.Exclamation().
@@ -94,12 +100,59 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		return fmt.Errorf("%s: %s", "app name incorrect", strings.Join(errorMsgs, "\n"))
 	}
 
-	// AppCreate
+	err := c.appUpsert(details, appRef, params.Configuration)
+	if err != nil {
+		return err
+	}
+
+	blobUID, err := c.appSources(log, details, appRef, params.Origin, params.Sources)
+	if err != nil {
+		return err
+	}
+
+	var stageResponse *models.StageResponse
+	if params.Origin.Kind != models.OriginContainer {
+		stageResponse, err = c.appStage(log, details, appRef, blobUID, params.Staging.Builder)
+		if err != nil {
+			return err
+		}
+	}
+
+	deployResponse, err := c.appDeploy(details, appRef, params.Origin, stageResponse)
+	if err != nil {
+		return err
+	}
+
+	routes := []string{}
+	for _, d := range deployResponse.Routes {
+		routes = append(routes, fmt.Sprintf("https://%s", d))
+	}
+
+	msg = c.ui.Success().
+		WithStringValue("Name", appRef.Name).
+		WithStringValue("Namespace", appRef.Namespace).
+		WithStringValue("Builder Image", params.Staging.Builder).
+		WithStringValue("Routes", "")
+
+	if len(routes) > 0 {
+		sort.Strings(routes)
+		for i, r := range routes {
+			msg = msg.WithStringValue(strconv.Itoa(i+1), r)
+		}
+	}
+	msg.Msg("App is online.")
+
+	return c.ui.Result(deployResponse)
+}
+
+// appUpsert creates the application resource, or updates its configuration, if the
+// application exists already.
+func (c *EpinioClient) appUpsert(details logr.Logger, appRef models.AppRef, configuration models.ApplicationUpdateRequest) error {
 	c.ui.Normal().Msg("Create the application resource ...")
 
 	request := models.ApplicationCreateRequest{
 		Name:          appRef.Name,
-		Configuration: params.Configuration,
+		Configuration: configuration,
 	}
 
 	_, err := c.API.AppCreate(request, appRef.Namespace)
@@ -118,36 +171,47 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		c.ui.Normal().Msg("Application exists, updating ...")
 		details.Info("app exists conflict")
 
-		_, err := c.API.AppUpdate(params.Configuration, appRef.Namespace, appRef.Name)
+		_, err := c.API.AppUpdate(configuration, appRef.Namespace, appRef.Name)
 		if err != nil {
 			return err
 		}
 	}
 
-	// AppUpload / AppImportGit
+	return nil
+}
+
+// appSources uploads the application sources of a path origin, or imports those of a
+// git origin. It returns the blob holding the sources. Container origins have no
+// sources, and no blob. The options select the sources of a path origin to upload.
+func (c *EpinioClient) appSources(log, details logr.Logger, appRef models.AppRef, origin models.ApplicationOrigin, options helpers.TarOptions) (string, error) {
 	var blobUID string
-	switch params.Origin.Kind {
+	switch origin.Kind {
 	case models.OriginNone:
-		return fmt.Errorf("%s", "No application origin")
+		return "", fmt.Errorf("%s", "No application origin")
 	case models.OriginPath:
 		c.ui.Normal().Msg("Collecting the application sources ...")
 
-		tmpDir, tarball, err := helpers.Tar(source)
+		tmpDir, tarball, stats, err := helpers.TarWithOptions(origin.String(), options)
 		defer func() {
 			if tmpDir != "" {
 				_ = os.RemoveAll(tmpDir)
 			}
 		}()
 		if err != nil {
-			return err
+			return "", err
 		}
 
+		c.ui.Note().
+			WithIntValue("Files", stats.Files).
+			WithStringValue("Size", bytes.ByteCountIEC(stats.Size)).
+			Msg("Application sources packaged.")
+
 		c.ui.Normal().Msg("Uploading application code ...")
 
 		details.Info("upload code")
 		upload, err := c.API.AppUpload(appRef.Namespace, appRef.Name, tarball)
 		if err != nil {
-			return err
+			return "", err
 		}
 		log.V(3).Info("upload response", "response", upload)
 
@@ -156,14 +220,14 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 	case models.OriginGit:
 		c.ui.Normal().Msg("Importing the application sources from Git ...")
 
-		gitOrigin := params.Origin.Git
+		gitOrigin := origin.Git
 		if gitOrigin == nil {
-			return errors.New("git origin is nil")
+			return "", errors.New("git origin is nil")
 		}
 
 		response, err := c.API.AppImportGit(appRef, *gitOrigin)
 		if err != nil {
-			return errors.Wrap(err, "importing git remote")
+			return "", errors.Wrap(err, "importing git remote")
 		}
 
 		blobUID = response.BlobUID
@@ -172,50 +236,55 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		// Nothing to upload (nor stage)
 	}
 
-	// AppStage
-	stageID := ""
-	var stageResponse *models.StageResponse
-	if params.Origin.Kind != models.OriginContainer {
-		c.ui.Normal().Msg("Staging application with code...")
+	return blobUID, nil
+}
 
-		req := models.StageRequest{
-			App:          appRef,
-			BlobUID:      blobUID,
-			BuilderImage: params.Staging.Builder,
-		}
-		details.Info("staging code", "Blob", blobUID)
-		stageResponse, err = c.API.AppStage(req)
-		if err != nil {
-			return err
-		}
-		stageID = stageResponse.Stage.ID
-		log.V(3).Info("stage response", "response", stageResponse)
+// appStage stages the application sources in the blob with the builder, and waits for
+// the staging to complete, while showing its logs.
+func (c *EpinioClient) appStage(log, details logr.Logger, appRef models.AppRef, blobUID, builder string) (*models.StageResponse, error) {
+	c.ui.Normal().Msg("Staging application with code...")
 
-		details.Info("start tailing logs", "StageID", stageResponse.Stage.ID)
-		err = c.stageLogs(details, appRef, stageResponse.Stage.ID)
-		if err != nil {
-			return err
-		}
+	req := models.StageRequest{
+		App:          appRef,
+		BlobUID:      blobUID,
+		BuilderImage: builder,
+	}
+	details.Info("staging code", "Blob", blobUID)
+	stageResponse, err := c.API.AppStage(req)
+	if err != nil {
+		return nil, err
 	}
+	log.V(3).Info("stage response", "response", stageResponse)
+
+	details.Info("start tailing logs", "StageID", stageResponse.Stage.ID)
+	err = c.stageLogs(details, appRef, stageResponse.Stage.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return stageResponse, nil
+}
 
-	// AppDeploy
+// appDeploy deploys the image of the staging, or the image of a container origin, and
+// waits for the application to run.
+func (c *EpinioClient) appDeploy(details logr.Logger, appRef models.AppRef, origin models.ApplicationOrigin, stageResponse *models.StageResponse) (*models.DeployResponse, error) {
 	c.ui.Normal().Msg("Deploying application ...")
 	deployRequest := models.DeployRequest{
 		App:    appRef,
-		Origin: params.Origin,
+		Origin: origin,
 	}
 	// If container param is specified, then we just take it into ImageURL
 	// If not, we take the one from the staging response
-	if params.Origin.Kind == models.OriginContainer {
-		deployRequest.ImageURL = params.Origin.Container
+	if origin.Kind == models.OriginContainer {
+		deployRequest.ImageURL = origin.Container
 	} else {
 		deployRequest.ImageURL = stageResponse.ImageURL
-		deployRequest.Stage = models.StageRef{ID: stageID}
+		deployRequest.Stage = models.StageRef{ID: stageResponse.Stage.ID}
 	}
 
 	deployResponse, err := c.API.AppDeploy(deployRequest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	details.Info("wait for application resources")
@@ -223,29 +292,10 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 
 	_, err = c.API.AppRunning(appRef)
 	if err != nil {
-		return errors.Wrap(err, "waiting for app failed")
-	}
-
-	routes := []string{}
-	for _, d := range deployResponse.Routes {
-		routes = append(routes, fmt.Sprintf("https://%s", d))
+		return nil, errors.Wrap(err, "waiting for app failed")
 	}
 
-	msg = c.ui.Success().
-		WithStringValue("Name", appRef.Name).
-		WithStringValue("Namespace", appRef.Namespace).
-		WithStringValue("Builder Image", params.Staging.Builder).
-		WithStringValue("Routes", "")
-
-	if len(routes) > 0 {
-		sort.Strings(routes)
-		for i, r := range routes {
-			msg = msg.WithStringValue(strconv.Itoa(i+1), r)
-		}
-	}
-	msg.Msg("App is online.")
-
-	return nil
+	return deployResponse, nil
 }
 
 func (c *EpinioClient) stageLogs(details logr.Logger, appRef models.AppRef, stageID string) error {
@@ -258,7 +308,7 @@ func (c *EpinioClient) stageLogs(details logr.Logger, appRef models.AppRef, stag
 	defer wg.Wait()
 	go func() {
 		defer wg.Done()
-		err := c.AppLogs(appRef.Name, stageID, true, stopChan)
+		err := c.AppLogs(appRef.Name, stageID, true, models.LogParameters{}, "", stopChan)
 		if err != nil {
 			c.ui.Problem().Msg(fmt.Sprintf("failed to tail logs: %s", err.Error()))
 		}
@@ -0,0 +1,138 @@
+package usercmd
+
+import (
+	"os"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+)
+
+// AppRouteCert configures the certificate of the specified route of the named
+// application. The certificate comes from the named secret, the PEM files of
+// certificate and key, or the named issuer. Without any of these the route
+// returns to the default issuer.
+func (c *EpinioClient) AppRouteCert(appName, route, secret, issuer, certFile, keyFile string) error {
+	log := c.Log.WithName("AppRouteCert").
+		WithValues("Namespace", c.Config.Namespace, "Application", appName, "Route", route)
+	log.Info("start")
+	defer log.Info("return")
+
+	msg := c.ui.Note().
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithStringValue("Application", appName).
+		WithStringValue("Route", route)
+
+	switch {
+	case secret != "":
+		msg = msg.WithStringValue("Secret", secret)
+	case issuer != "":
+		msg = msg.WithStringValue("Issuer", issuer)
+	case certFile != "" || keyFile != "":
+		msg = msg.WithStringValue("Certificate", certFile).WithStringValue("Key", keyFile)
+	default:
+		msg = msg.WithStringValue("Issuer", "(default)")
+	}
+	msg.Msg("Configure route certificate")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	request := models.RouteCertRequest{
+		Route:  route,
+		Secret: secret,
+		Issuer: issuer,
+	}
+
+	if certFile != "" {
+		cert, err := os.ReadFile(certFile)
+		if err != nil {
+			return errors.Wrap(err, "reading certificate")
+		}
+		request.Certificate = string(cert)
+	}
+	if keyFile != "" {
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return errors.Wrap(err, "reading key")
+		}
+		request.Key = string(key)
+	}
+
+	resp, err := c.API.AppRouteCert(request, c.Config.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Route certificate configured.")
+	return c.ui.Result(resp)
+}
+
+// Routes lists the active routes of all applications, with owner and certificate
+func (c *EpinioClient) Routes() error {
+	log := c.Log.WithName("Routes")
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Note().Msg("Listing routes")
+
+	details.Info("list routes")
+
+	routes, err := c.API.Routes()
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		return c.ui.Result(routes)
+	}
+
+	msg := c.ui.Success().WithTable("Route", "Namespace", "Application", "TLS")
+
+	for _, route := range routes {
+		tls := "none"
+		if route.Certificate != nil {
+			tls = certificateDetails(*route.Certificate)
+		}
+		msg = msg.WithTableRow(route.Route, route.Namespace, route.App, tls)
+	}
+
+	msg.Msg("Epinio Routes:")
+
+	return nil
+}
+
+// RouteMove moves the route from the named application to the other named
+// application, in the targeted namespace.
+func (c *EpinioClient) RouteMove(route, from, to string) error {
+	log := c.Log.WithName("RouteMove").
+		WithValues("Namespace", c.Config.Namespace, "Route", route, "From", from, "To", to)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Config.Namespace).
+		WithStringValue("Route", route).
+		WithStringValue("From", from).
+		WithStringValue("To", to).
+		Msg("Move route")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	request := models.RouteMoveRequest{
+		Route: route,
+		From:  from,
+		To:    to,
+	}
+
+	resp, err := c.API.RouteMove(request, c.Config.Namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Route moved.")
+	return c.ui.Result(resp)
+}
@@ -4,22 +4,33 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"sort"
 	"strings"
 
+	"github.com/epinio/epinio/helpers/secretcrypt"
+	"github.com/epinio/epinio/internal/manifest"
 	apierrors "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 )
 
-// Services gets all Epinio services in the targeted namespace
-func (c *EpinioClient) Services(all bool) error {
-	log := c.Log.WithName("Services").WithValues("Namespace", c.Config.Namespace)
+// Services gets all Epinio services in the targeted namespace, or all.
+// A non-empty selector restricts the list to the services whose labels
+// match it. When wide is set the description, labels, and bound app count
+// are shown as well.
+func (c *EpinioClient) Services(all bool, selector string, wide bool) error {
+	log := c.Log.WithName("Services").WithValues("Namespace", c.Config.Namespace, "Selector", selector)
 	log.Info("start")
 	defer log.Info("return")
 	details := log.V(1) // NOTE: Increment of level, not absolute.
 
 	msg := c.ui.Note()
+	if selector != "" {
+		msg = msg.WithStringValue("Selector", selector)
+	}
 	if all {
 		msg.Msg("Listing all services")
 	} else {
@@ -38,9 +49,9 @@ func (c *EpinioClient) Services(all bool) error {
 	var err error
 
 	if all {
-		services, err = c.API.AllServices()
+		services, err = c.API.AllServicesWithSelector(selector)
 	} else {
-		services, err = c.API.Services(c.Config.Namespace)
+		services, err = c.API.ServicesWithSelector(c.Config.Namespace, selector)
 	}
 	if err != nil {
 		return err
@@ -50,26 +61,38 @@ func (c *EpinioClient) Services(all bool) error {
 
 	sort.Sort(services)
 
+	if c.ui.MachineReadable() {
+		return c.ui.Result(services)
+	}
+
 	details.Info("show services")
 
-	msg = c.ui.Success()
+	headers := []string{}
 	if all {
-		msg = msg.WithTable("Namespace", "Name", "Applications")
+		headers = append(headers, "Namespace")
+	}
+	headers = append(headers, "Name", "Applications")
+	if wide {
+		headers = append(headers, "#Apps", "Labels", "Description")
+	}
 
-		for _, service := range services {
-			msg = msg.WithTableRow(
-				service.Meta.Namespace,
-				service.Meta.Name,
-				strings.Join(service.Configuration.BoundApps, ", "))
-		}
-	} else {
-		msg = msg.WithTable("Name", "Applications")
+	msg = c.ui.Success().WithTable(headers...)
 
-		for _, service := range services {
-			msg = msg.WithTableRow(
-				service.Meta.Name,
-				strings.Join(service.Configuration.BoundApps, ", "))
+	for _, service := range services {
+		row := []string{}
+		if all {
+			row = append(row, service.Meta.Namespace)
+		}
+		row = append(row,
+			service.Meta.Name,
+			strings.Join(service.Configuration.BoundApps, ", "))
+		if wide {
+			row = append(row,
+				fmt.Sprintf("%d", len(service.Configuration.BoundApps)),
+				formatLabels(service.Configuration.Labels),
+				service.Configuration.Description)
 		}
+		msg = msg.WithTableRow(row...)
 	}
 
 	msg.Msg("Epinio Services:")
@@ -77,6 +100,17 @@ func (c *EpinioClient) Services(all bool) error {
 	return nil
 }
 
+// formatLabels returns the labels as a comma-separated list of sorted
+// key=value assignments.
+func formatLabels(labels map[string]string) string {
+	assignments := []string{}
+	for key, value := range labels {
+		assignments = append(assignments, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(assignments)
+	return strings.Join(assignments, ", ")
+}
+
 // ServiceMatching returns all Epinio services having the specified prefix
 // in their name.
 func (c *EpinioClient) ServiceMatching(ctx context.Context, prefix string) []string {
@@ -135,6 +169,10 @@ func (c *EpinioClient) BindService(serviceName, appName string) error {
 	}
 
 	if len(br.WasBound) > 0 {
+		if c.ui.MachineReadable() {
+			return c.ui.Result(br)
+		}
+
 		c.ui.Success().
 			WithStringValue("Service", serviceName).
 			WithStringValue("Application", appName).
@@ -149,7 +187,7 @@ func (c *EpinioClient) BindService(serviceName, appName string) error {
 		WithStringValue("Application", appName).
 		WithStringValue("Namespace", c.Config.Namespace).
 		Msg("Service Bound to Application.")
-	return nil
+	return c.ui.Result(br)
 }
 
 // UnbindService detaches the service specified by name from the named
@@ -170,7 +208,7 @@ func (c *EpinioClient) UnbindService(serviceName, appName string) error {
 		return err
 	}
 
-	_, err := c.API.ServiceBindingDelete(c.Config.Namespace, appName, serviceName)
+	resp, err := c.API.ServiceBindingDelete(c.Config.Namespace, appName, serviceName)
 	if err != nil {
 		return err
 	}
@@ -180,7 +218,7 @@ func (c *EpinioClient) UnbindService(serviceName, appName string) error {
 		WithStringValue("Application", appName).
 		WithStringValue("Namespace", c.Config.Namespace).
 		Msg("Service Detached From Application.")
-	return nil
+	return c.ui.Result(resp)
 }
 
 // DeleteService deletes a service specified by name
@@ -205,7 +243,7 @@ func (c *EpinioClient) DeleteService(name string, unbind bool) error {
 
 	var bound []string
 
-	_, err := c.API.ServiceDelete(request, c.Config.Namespace, name,
+	resp, err := c.API.ServiceDelete(request, c.Config.Namespace, name,
 		func(response *http.Response, bodyBytes []byte, err error) error {
 			// nothing special for internal errors and the like
 			if response.StatusCode != http.StatusBadRequest {
@@ -232,6 +270,11 @@ func (c *EpinioClient) DeleteService(name string, unbind bool) error {
 	if len(bound) > 0 {
 		sort.Strings(bound)
 		sort.Strings(bound)
+		if c.ui.MachineReadable() {
+			return fmt.Errorf("service is still used by %s, use --unbind to force the issue",
+				strings.Join(bound, ", "))
+		}
+
 		msg := c.ui.Exclamation().WithTable("Bound Applications")
 
 		for _, app := range bound {
@@ -248,12 +291,14 @@ func (c *EpinioClient) DeleteService(name string, unbind bool) error {
 		WithStringValue("Name", name).
 		WithStringValue("Namespace", c.Config.Namespace).
 		Msg("Service Removed.")
-	return nil
+	return c.ui.Result(resp)
 }
 
 // UpdateService updates a service specified by name and information about removed keys and changed assignments.
+// Labels are removed and assigned the same way. A non-nil description replaces the current one.
 // TODO: Allow underscores in service names (right now they fail because of kubernetes naming rules for secrets)
-func (c *EpinioClient) UpdateService(name string, removedKeys []string, assignments map[string]string) error {
+func (c *EpinioClient) UpdateService(name string, removedKeys []string, assignments map[string]string,
+	removedLabels []string, labelAssignments map[string]string, description *string) error {
 	log := c.Log.WithName("Update Service").
 		WithValues("Name", name, "Namespace", c.Config.Namespace)
 	log.Info("start")
@@ -277,6 +322,29 @@ func (c *EpinioClient) UpdateService(name string, removedKeys []string, assignme
 	for _, key := range changed {
 		msg = msg.WithTableRow(key, "add/change", assignments[key])
 	}
+
+	if len(removedLabels) > 0 || len(labelAssignments) > 0 {
+		msg = msg.WithTable("Label", "Op", "Value")
+
+		for _, removed := range removedLabels {
+			msg = msg.WithTableRow(removed, "remove", "")
+		}
+
+		changed := []string{}
+		for key := range labelAssignments {
+			changed = append(changed, key)
+		}
+		sort.Strings(changed)
+
+		for _, key := range changed {
+			msg = msg.WithTableRow(key, "add/change", labelAssignments[key])
+		}
+	}
+
+	if description != nil {
+		msg = msg.WithStringValue("Description", *description)
+	}
+
 	msg.Msg("Update Service")
 
 	if err := c.TargetOk(); err != nil {
@@ -284,11 +352,14 @@ func (c *EpinioClient) UpdateService(name string, removedKeys []string, assignme
 	}
 
 	request := models.ServiceUpdateRequest{
-		Remove: removedKeys,
-		Set:    assignments,
+		Remove:       removedKeys,
+		Set:          assignments,
+		RemoveLabels: removedLabels,
+		SetLabels:    labelAssignments,
+		Description:  description,
 	}
 
-	_, err := c.API.ServiceUpdate(request, c.Config.Namespace, name)
+	resp, err := c.API.ServiceUpdate(request, c.Config.Namespace, name)
 	if err != nil {
 		return err
 	}
@@ -298,12 +369,12 @@ func (c *EpinioClient) UpdateService(name string, removedKeys []string, assignme
 		WithStringValue("Namespace", c.Config.Namespace).
 		Msg("Service Changes Saved.")
 
-	return nil
+	return c.ui.Result(resp)
 }
 
-// CreateService creates a service specified by name and key/value dictionary
+// CreateService creates a service specified by name and key/value dictionary, plus optional labels and description
 // TODO: Allow underscores in service names (right now they fail because of kubernetes naming rules for secrets)
-func (c *EpinioClient) CreateService(name string, dict []string) error {
+func (c *EpinioClient) CreateService(name string, dict []string, labels map[string]string, description string) error {
 	log := c.Log.WithName("Create Service").
 		WithValues("Name", name, "Namespace", c.Config.Namespace)
 	log.Info("start")
@@ -321,6 +392,12 @@ func (c *EpinioClient) CreateService(name string, dict []string) error {
 		msg = msg.WithTableRow(key, value, path)
 		data[key] = value
 	}
+	if len(labels) > 0 {
+		msg = msg.WithStringValue("Labels", formatLabels(labels))
+	}
+	if description != "" {
+		msg = msg.WithStringValue("Description", description)
+	}
 	msg.Msg("Create Service")
 
 	if err := c.TargetOk(); err != nil {
@@ -328,11 +405,13 @@ func (c *EpinioClient) CreateService(name string, dict []string) error {
 	}
 
 	request := models.ServiceCreateRequest{
-		Name: name,
-		Data: data,
+		Name:        name,
+		Data:        data,
+		Labels:      labels,
+		Description: description,
 	}
 
-	_, err := c.API.ServiceCreate(request, c.Config.Namespace)
+	resp, err := c.API.ServiceCreate(request, c.Config.Namespace)
 	if err != nil {
 		return err
 	}
@@ -344,7 +423,7 @@ func (c *EpinioClient) CreateService(name string, dict []string) error {
 		WithStringValue("Name", name).
 		WithStringValue("Namespace", c.Config.Namespace).
 		Msg("Service Saved.")
-	return nil
+	return c.ui.Result(resp)
 }
 
 // ServiceDetails shows the information of a service specified by name
@@ -367,6 +446,10 @@ func (c *EpinioClient) ServiceDetails(name string) error {
 	if err != nil {
 		return err
 	}
+	if c.ui.MachineReadable() {
+		return c.ui.Result(resp)
+	}
+
 	serviceDetails := resp.Configuration.Details
 	boundApps := resp.Configuration.BoundApps
 
@@ -375,6 +458,8 @@ func (c *EpinioClient) ServiceDetails(name string) error {
 	c.ui.Note().
 		WithStringValue("User", resp.Configuration.Username).
 		WithStringValue("Used-By", strings.Join(boundApps, ", ")).
+		WithStringValue("Labels", formatLabels(resp.Configuration.Labels)).
+		WithStringValue("Description", resp.Configuration.Description).
 		Msg("")
 
 	msg := c.ui.Success()
@@ -401,3 +486,204 @@ func (c *EpinioClient) ServiceDetails(name string) error {
 		Msg("Beware, the shown access paths are only available in the application's container")
 	return nil
 }
+
+// ExportServices writes the named services of the targeted namespace to
+// stdout, as a YAML export document. Without names all services of the
+// namespace are exported. When a key file is specified the values are
+// encrypted with it. Nothing but the document is written to stdout, to keep
+// the output usable through redirection.
+func (c *EpinioClient) ExportServices(names []string, keyPath string) error {
+	log := c.Log.WithName("ExportServices").
+		WithValues("Names", names, "Namespace", c.Config.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	var key *secretcrypt.Key
+	if keyPath != "" {
+		k, err := secretcrypt.LoadKey(keyPath)
+		if err != nil {
+			return err
+		}
+		key = &k
+	}
+
+	if len(names) == 0 {
+		details.Info("list services")
+
+		services, err := c.API.Services(c.Config.Namespace)
+		if err != nil {
+			return err
+		}
+		for _, service := range services {
+			names = append(names, service.Meta.Name)
+		}
+		sort.Strings(names)
+	}
+
+	document := models.ServiceExportDocument{
+		Services: []models.ServiceExport{},
+	}
+
+	for _, name := range names {
+		details.Info("export service", "name", name)
+
+		resp, err := c.API.ServiceShow(c.Config.Namespace, name)
+		if err != nil {
+			return errors.Wrapf(err, "service %s", name)
+		}
+
+		export := models.ServiceExport{
+			ServiceCreateRequest: models.ServiceCreateRequest{
+				Name:        name,
+				Data:        map[string]string{},
+				Labels:      resp.Configuration.Labels,
+				Description: resp.Configuration.Description,
+			},
+			Encrypted: key != nil,
+		}
+
+		for k, v := range resp.Configuration.Details {
+			if key != nil {
+				v, err = key.Encrypt(v)
+				if err != nil {
+					return errors.Wrapf(err, "service %s, key %s", name, k)
+				}
+			}
+			export.Data[k] = v
+		}
+
+		document.Services = append(document.Services, export)
+	}
+
+	out, err := yaml.Marshal(document)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize services")
+	}
+
+	fmt.Print(string(out))
+
+	return nil
+}
+
+// ImportServices reads an export document from the specified file and
+// creates its services in the targeted namespace. Services which already
+// exist are replaced with the imported data. Encrypted values are decrypted
+// with the key read from the key file.
+func (c *EpinioClient) ImportServices(path, keyPath string) error {
+	log := c.Log.WithName("ImportServices").
+		WithValues("File", path, "Namespace", c.Config.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Note().
+		WithStringValue("File", path).
+		WithStringValue("Namespace", c.Config.Namespace).
+		Msg("Import Services")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read export document")
+	}
+
+	var document models.ServiceExportDocument
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return errors.Wrap(err, "failed to parse export document")
+	}
+
+	var key *secretcrypt.Key
+	if keyPath != "" {
+		k, err := secretcrypt.LoadKey(keyPath)
+		if err != nil {
+			return err
+		}
+		key = &k
+	}
+
+	details.Info("list services")
+
+	services, err := c.API.Services(c.Config.Namespace)
+	if err != nil {
+		return err
+	}
+	known := map[string]models.ServiceShowResponse{}
+	for _, service := range services {
+		known[service.Meta.Name] = service.Configuration
+	}
+
+	msg := c.ui.Success().WithTable("Service", "Op")
+
+	for _, export := range document.Services {
+		if export.Name == "" {
+			return errors.New("export document contains a service without name")
+		}
+
+		data := map[string]string{}
+		for k, v := range export.Data {
+			if export.Encrypted {
+				if key == nil {
+					return fmt.Errorf("service %s has encrypted values, a key is required", export.Name)
+				}
+				v, err = key.Decrypt(v)
+				if err != nil {
+					return errors.Wrapf(err, "service %s, key %s", export.Name, k)
+				}
+			}
+			data[k] = v
+		}
+
+		if current, ok := known[export.Name]; ok {
+			details.Info("replace service", "name", export.Name)
+
+			_, err := c.API.ServiceReplace(models.ServiceReplaceRequest(data), c.Config.Namespace, export.Name)
+			if err != nil {
+				return errors.Wrapf(err, "service %s", export.Name)
+			}
+
+			// The data is replaced already. Bring labels and description in line
+			// with the export, removing the labels it does not have.
+			update := manifest.ServiceUpdate(models.ServiceCreateRequest{
+				Labels:      export.Labels,
+				Description: export.Description,
+			}, current)
+			update.Set = nil
+			update.Remove = nil
+
+			if len(update.SetLabels) > 0 || len(update.RemoveLabels) > 0 || update.Description != nil {
+				_, err := c.API.ServiceUpdate(update, c.Config.Namespace, export.Name)
+				if err != nil {
+					return errors.Wrapf(err, "service %s", export.Name)
+				}
+			}
+
+			msg = msg.WithTableRow(export.Name, "replaced")
+			continue
+		}
+
+		details.Info("create service", "name", export.Name)
+
+		_, err := c.API.ServiceCreate(models.ServiceCreateRequest{
+			Name:        export.Name,
+			Data:        data,
+			Labels:      export.Labels,
+			Description: export.Description,
+		}, c.Config.Namespace)
+		if err != nil {
+			return errors.Wrapf(err, "service %s", export.Name)
+		}
+		msg = msg.WithTableRow(export.Name, "created")
+	}
+
+	msg.Msg("Services Imported.")
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package usercmd
+
+import (
+	"strings"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Volumes lists the volumes of the targeted namespace, and the applications they are bound to
+func (c *EpinioClient) Volumes() error {
+	log := c.Log.WithName("Volumes").WithValues("Namespace", c.Config.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+	details := log.V(1) // NOTE: Increment of level, not absolute.
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Config.Namespace).
+		Msg("Listing volumes")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	details.Info("list volumes")
+
+	volumes, err := c.API.Volumes(c.Config.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if c.ui.MachineReadable() {
+		return c.ui.Result(volumes)
+	}
+
+	if len(volumes) == 0 {
+		c.ui.Normal().Msg("No volumes found")
+		return nil
+	}
+
+	msg := c.ui.Success().WithTable("Name", "Size", "Storage Class", "Status", "Applications")
+
+	for _, volume := range volumes {
+		msg = msg.WithTableRow(volume.Name, volume.Size, volume.StorageClass, volume.Status,
+			strings.Join(volume.BoundApps, ", "))
+	}
+
+	msg.Msg("Epinio Volumes:")
+
+	return nil
+}
+
+// CreateVolume creates a volume in the targeted namespace. An empty size or
+// storage class requests the defaults.
+func (c *EpinioClient) CreateVolume(name, size, storageClass string) error {
+	log := c.Log.WithName("CreateVolume").WithValues("Volume", name, "Namespace", c.Config.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	msg := c.ui.Note().
+		WithStringValue("Name", name).
+		WithStringValue("Namespace", c.Config.Namespace)
+	if size != "" {
+		msg = msg.WithStringValue("Size", size)
+	}
+	if storageClass != "" {
+		msg = msg.WithStringValue("Storage Class", storageClass)
+	}
+	msg.Msg("Creating volume...")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	resp, err := c.API.VolumeCreate(models.VolumeCreateRequest{
+		Name:         name,
+		Size:         size,
+		StorageClass: storageClass,
+	}, c.Config.Namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Volume created.")
+
+	return c.ui.Result(resp)
+}
+
+// DeleteVolume deletes a volume of the targeted namespace, and its data
+func (c *EpinioClient) DeleteVolume(name string) error {
+	log := c.Log.WithName("DeleteVolume").WithValues("Volume", name, "Namespace", c.Config.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", name).
+		WithStringValue("Namespace", c.Config.Namespace).
+		Msg("Deleting volume...")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	resp, err := c.API.VolumeDelete(c.Config.Namespace, name)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Volume deleted.")
+
+	return c.ui.Result(resp)
+}
+
+// BindVolume binds a volume to an application of the targeted namespace, at the mount path
+func (c *EpinioClient) BindVolume(volumeName, appName, path string) error {
+	log := c.Log.WithName("Bind Volume To Application").
+		WithValues("Name", volumeName, "Application", appName, "Namespace", c.Config.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Volume", volumeName).
+		WithStringValue("Application", appName).
+		WithStringValue("Path", path).
+		WithStringValue("Namespace", c.Config.Namespace).
+		Msg("Bind Volume")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	resp, err := c.API.VolumeBindingCreate(models.VolumeBindRequest{
+		Volume: volumeName,
+		Path:   path,
+	}, c.Config.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithStringValue("Volume", volumeName).
+		WithStringValue("Application", appName).
+		WithStringValue("Namespace", c.Config.Namespace).
+		Msg("Volume Bound to Application.")
+
+	return c.ui.Result(resp)
+}
+
+// UnbindVolume removes the binding between a volume and an application of the targeted namespace
+func (c *EpinioClient) UnbindVolume(volumeName, appName string) error {
+	log := c.Log.WithName("Unbind Volume").
+		WithValues("Name", volumeName, "Application", appName, "Namespace", c.Config.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Volume", volumeName).
+		WithStringValue("Application", appName).
+		WithStringValue("Namespace", c.Config.Namespace).
+		Msg("Unbind Volume from Application")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	resp, err := c.API.VolumeBindingDelete(c.Config.Namespace, appName, volumeName)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithStringValue("Volume", volumeName).
+		WithStringValue("Application", appName).
+		WithStringValue("Namespace", c.Config.Namespace).
+		Msg("Volume Detached From Application.")
+
+	return c.ui.Result(resp)
+}
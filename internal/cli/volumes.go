@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdVolume implements the command: epinio volume
+var CmdVolume = &cobra.Command{
+	Use:     "volume",
+	Aliases: []string{"volumes"},
+	Short:   "Epinio persistent volumes",
+	Long: `Manage the persistent volumes of the targeted namespace.
+
+A volume is a writable directory whose data survives the restarts of the
+applications it is bound to. Applications with bound volumes are updated by
+stopping the old instances before starting the new ones.
+
+Volumes bound to an application cannot be deleted. Neither can applications
+with bound volumes, unbind the volumes first.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+func init() {
+	CmdVolume.AddCommand(CmdVolumeList)
+	CmdVolume.AddCommand(CmdVolumeCreate)
+	CmdVolume.AddCommand(CmdVolumeDelete)
+	CmdVolume.AddCommand(CmdVolumeBind)
+	CmdVolume.AddCommand(CmdVolumeUnbind)
+
+	CmdVolumeCreate.Flags().String("size", "", "size of the volume, e.g. 10Gi (default 1Gi)")
+	CmdVolumeCreate.Flags().String("storage-class", "", "storage class of the volume (default: the default storage class of the cluster)")
+
+	CmdVolumeBind.Flags().String("path", "", "absolute path to mount the volume at")
+	// nolint:errcheck // Unable to handle error in init block
+	CmdVolumeBind.MarkFlagRequired("path")
+}
+
+// CmdVolumeList implements the command: epinio volume list
+var CmdVolumeList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the volumes of the targeted namespace",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.Volumes()
+		if err != nil {
+			return errors.Wrap(err, "error listing volumes")
+		}
+
+		return nil
+	},
+}
+
+// CmdVolumeCreate implements the command: epinio volume create
+var CmdVolumeCreate = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Creates a volume in the targeted namespace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		size, err := cmd.Flags().GetString("size")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --size")
+		}
+		storageClass, err := cmd.Flags().GetString("storage-class")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --storage-class")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.CreateVolume(args[0], size, storageClass)
+		if err != nil {
+			return errors.Wrap(err, "error creating volume")
+		}
+
+		return nil
+	},
+}
+
+// CmdVolumeDelete implements the command: epinio volume delete
+var CmdVolumeDelete = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Deletes a volume of the targeted namespace, and its data",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.DeleteVolume(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error deleting volume")
+		}
+
+		return nil
+	},
+}
+
+// CmdVolumeBind implements the command: epinio volume bind
+var CmdVolumeBind = &cobra.Command{
+	Use:   "bind NAME APP --path PATH",
+	Short: "Bind a volume to an application",
+	Long:  `Bind volume by name, to named application, mounted at the path. A running application is restarted.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		path, err := cmd.Flags().GetString("path")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --path")
+		}
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.BindVolume(args[0], args[1], path)
+		if err != nil {
+			return errors.Wrap(err, "error binding volume")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: volumeAppCompletion,
+}
+
+// CmdVolumeUnbind implements the command: epinio volume unbind
+var CmdVolumeUnbind = &cobra.Command{
+	Use:   "unbind NAME APP",
+	Short: "Unbind a volume from an application",
+	Long:  `Unbind volume by name, from named application. The data of the volume is kept. A running application is restarted.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New()
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.UnbindVolume(args[0], args[1])
+		if err != nil {
+			return errors.Wrap(err, "error unbinding volume")
+		}
+
+		return nil
+	},
+	ValidArgsFunction: volumeAppCompletion,
+}
+
+// volumeAppCompletion is a helper for the bind and unbind commands. It completes
+// the application argument.
+func volumeAppCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	app, err := usercmd.New()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return app.AppsMatching(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
@@ -17,13 +17,14 @@ import (
 var mainDomain = ""
 
 // AppDefaultRoute constructs and returns an application's default
-// route from the main domain and the name of the application
-func AppDefaultRoute(ctx context.Context, name string) (string, error) {
-	mainDomain, err := MainDomain(ctx)
+// route from the default domain of the namespace and the name of the
+// application. See NamespaceDefault.
+func AppDefaultRoute(ctx context.Context, cluster *kubernetes.Cluster, name, namespace string) (string, error) {
+	defaultDomain, err := NamespaceDefault(ctx, cluster, namespace)
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("%s.%s", name, mainDomain), nil
+	return fmt.Sprintf("%s.%s", name, defaultDomain), nil
 }
 
 // MainDomain determines the name of the main domain of the currently
@@ -0,0 +1,332 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/retry"
+)
+
+// The domain registry is kept in a secret of the epinio namespace, as a JSON
+// encoded list of models.Domain.
+//
+// A plain domain, e.g. `example.com`, covers the host `example.com` itself,
+// and the hosts one label below it, e.g. `myapp.example.com`. A wildcard
+// domain, e.g. `*.example.com`, covers all hosts below `example.com`, at any
+// depth.
+//
+// Registered domains are owned exclusively by the namespace they are assigned
+// to. Hosts not covered by a domain assigned to a namespace can be used by all
+// namespaces, as before the registry existed. Domains whose hosts overlap
+// cannot be assigned to different namespaces, see CheckAssignment.
+
+const (
+	// RegistrySecretName is the name of the secret holding the domain registry
+	RegistrySecretName = "epinio-domains"
+
+	registryKey = "domains"
+)
+
+// List returns all registered domains, sorted by name.
+func List(ctx context.Context, cluster *kubernetes.Cluster) (models.DomainList, error) {
+	registrySecret, err := registryLoad(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRegistry(registrySecret)
+}
+
+// Lookup returns the named domain, or nil if it is not registered.
+func Lookup(ctx context.Context, cluster *kubernetes.Cluster, name string) (*models.Domain, error) {
+	registry, err := List(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range registry {
+		if d.Name == name {
+			return &d, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Register adds the domain to the registry. An already registered domain
+// is replaced.
+func Register(ctx context.Context, cluster *kubernetes.Cluster, domain models.Domain) error {
+	return registryUpdate(ctx, cluster, func(registry models.DomainList) models.DomainList {
+		result := models.DomainList{domain}
+		for _, d := range registry {
+			if d.Name == domain.Name {
+				continue
+			}
+			if domain.Default && d.Namespace == domain.Namespace {
+				d.Default = false
+			}
+			result = append(result, d)
+		}
+		return result
+	})
+}
+
+// Unregister removes the named domain from the registry.
+func Unregister(ctx context.Context, cluster *kubernetes.Cluster, name string) error {
+	return registryUpdate(ctx, cluster, func(registry models.DomainList) models.DomainList {
+		result := models.DomainList{}
+		for _, d := range registry {
+			if d.Name != name {
+				result = append(result, d)
+			}
+		}
+		return result
+	})
+}
+
+// Assign assigns the named domain to the namespace, possibly as the default
+// domain of the namespace. An empty namespace releases the domain.
+func Assign(ctx context.Context, cluster *kubernetes.Cluster, name, namespace string, isDefault bool) error {
+	return registryUpdate(ctx, cluster, func(registry models.DomainList) models.DomainList {
+		for i, d := range registry {
+			if d.Name == name {
+				registry[i].Namespace = namespace
+				registry[i].Default = isDefault && namespace != ""
+			} else if isDefault && d.Namespace == namespace {
+				registry[i].Default = false
+			}
+		}
+		return registry
+	})
+}
+
+// ReleaseNamespace releases all domains assigned to the namespace. It is used
+// when the namespace is deleted.
+func ReleaseNamespace(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	return registryUpdate(ctx, cluster, func(registry models.DomainList) models.DomainList {
+		for i, d := range registry {
+			if d.Namespace == namespace {
+				registry[i].Namespace = ""
+				registry[i].Default = false
+			}
+		}
+		return registry
+	})
+}
+
+// CheckRoutes returns the routes which the namespace is not allowed to use.
+func CheckRoutes(ctx context.Context, cluster *kubernetes.Cluster, namespace string, routeList []string) ([]string, error) {
+	registry, err := List(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkRoutes(registry, namespace, routeList), nil
+}
+
+// NamespaceDefault returns the domain the default routes of the applications
+// in the namespace are placed under. This is the default domain of the
+// namespace, if there is one, and the main domain otherwise.
+func NamespaceDefault(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (string, error) {
+	registry, err := List(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	for _, d := range registry {
+		if d.Namespace == namespace && d.Default {
+			return strings.TrimPrefix(d.Name, "*."), nil
+		}
+	}
+
+	return MainDomain(ctx)
+}
+
+// ValidateName checks that the name is a valid plain or wildcard domain.
+func ValidateName(name string) error {
+	errorMsgs := validation.IsDNS1123Subdomain(strings.TrimPrefix(name, "*."))
+	if len(errorMsgs) > 0 {
+		return fmt.Errorf("domain name incorrect: %s", strings.Join(errorMsgs, "\n"))
+	}
+	return nil
+}
+
+// Covers returns true if the host can be used with the domain. See the
+// explanation at the top of the file.
+func Covers(domain, host string) bool {
+	if base := strings.TrimPrefix(domain, "*."); base != domain {
+		return strings.HasSuffix(host, "."+base)
+	}
+	if host == domain {
+		return true
+	}
+	parts := strings.SplitN(host, ".", 2)
+	return len(parts) == 2 && parts[0] != "" && parts[1] == domain
+}
+
+// checkRoutes is the core of CheckRoutes, separated out for testing. A route is
+// rejected if its host is covered by a domain assigned to another namespace.
+func checkRoutes(registry models.DomainList, namespace string, routeList []string) []string {
+	rejected := []string{}
+
+	for _, route := range routeList {
+		parsed := routes.FromString(route)
+		if parsed.Type == routes.TypeTCP {
+			// Port based, no domain to own
+			continue
+		}
+
+		for _, d := range registry {
+			if d.Namespace != "" && d.Namespace != namespace && Covers(d.Name, parsed.Domain) {
+				rejected = append(rejected, route)
+				break
+			}
+		}
+	}
+
+	return rejected
+}
+
+// CheckAssignment returns an error if the named domain cannot be assigned to the
+// namespace, because it shares hosts with a domain of the registry assigned to
+// another namespace.
+func CheckAssignment(registry models.DomainList, name, namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+
+	for _, d := range registry {
+		if d.Name == name || d.Namespace == "" || d.Namespace == namespace {
+			continue
+		}
+		if overlaps(d.Name, name) {
+			return fmt.Errorf("domain '%s' shares hosts with domain '%s' of namespace '%s'",
+				name, d.Name, d.Namespace)
+		}
+	}
+
+	return nil
+}
+
+// overlaps returns true if some host is covered by both domains. It suffices to
+// check the shallowest hosts of each domain against the other, with an arbitrary
+// first label.
+func overlaps(a, b string) bool {
+	for _, host := range shallowHosts(a) {
+		if Covers(b, host) {
+			return true
+		}
+	}
+	for _, host := range shallowHosts(b) {
+		if Covers(a, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// shallowHosts returns the host of a plain domain itself, and a host one label
+// below the domain.
+func shallowHosts(domain string) []string {
+	if base := strings.TrimPrefix(domain, "*."); base != domain {
+		return []string{"x." + base}
+	}
+	return []string{domain, "x." + domain}
+}
+
+// registryUpdate is a helper for the public functions. It encapsulates the
+// read/modify/write cycle necessary to update the domain registry.
+func registryUpdate(ctx context.Context, cluster *kubernetes.Cluster,
+	modifyRegistry func(models.DomainList) models.DomainList) error {
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		registrySecret, err := registryLoad(ctx, cluster)
+		if err != nil {
+			return err
+		}
+
+		registry, err := decodeRegistry(registrySecret)
+		if err != nil {
+			return err
+		}
+
+		registry = modifyRegistry(registry)
+
+		encoded, err := json.Marshal(registry)
+		if err != nil {
+			return err
+		}
+
+		registrySecret.Data = map[string][]byte{
+			registryKey: encoded,
+		}
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(helmchart.EpinioNamespace).Update(
+			ctx, registrySecret, metav1.UpdateOptions{})
+
+		return err
+	})
+}
+
+// decodeRegistry extracts the sorted list of domains from the registry secret.
+func decodeRegistry(registrySecret *v1.Secret) (models.DomainList, error) {
+	result := models.DomainList{}
+
+	data, ok := registrySecret.Data[registryKey]
+	if !ok {
+		return result, nil
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, errors.Wrap(err, "bad domain registry")
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// registryLoad locates and returns the kube secret storing the domain
+// registry. If necessary it creates that secret.
+func registryLoad(ctx context.Context, cluster *kubernetes.Cluster) (*v1.Secret, error) {
+	registrySecret, err := cluster.GetSecret(ctx, helmchart.EpinioNamespace, RegistrySecretName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		// Error is `Not Found`. Create the secret.
+
+		registrySecret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      RegistrySecretName,
+				Namespace: helmchart.EpinioNamespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name":       RegistrySecretName,
+					"app.kubernetes.io/managed-by": "epinio",
+				},
+			},
+		}
+		err = cluster.CreateSecret(ctx, helmchart.EpinioNamespace, *registrySecret)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return registrySecret, nil
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Domain registry", func() {
+	Describe("Covers", func() {
+		It("covers the plain domain and the hosts one level below", func() {
+			Expect(Covers("example.com", "example.com")).To(BeTrue())
+			Expect(Covers("example.com", "myapp.example.com")).To(BeTrue())
+			Expect(Covers("example.com", "a.myapp.example.com")).To(BeFalse())
+			Expect(Covers("example.com", "otherexample.com")).To(BeFalse())
+		})
+
+		It("covers all hosts below a wildcard domain", func() {
+			Expect(Covers("*.example.com", "example.com")).To(BeFalse())
+			Expect(Covers("*.example.com", "myapp.example.com")).To(BeTrue())
+			Expect(Covers("*.example.com", "a.myapp.example.com")).To(BeTrue())
+			Expect(Covers("*.example.com", "myapp.otherexample.com")).To(BeFalse())
+		})
+	})
+
+	Describe("ValidateName", func() {
+		It("accepts plain and wildcard domains", func() {
+			Expect(ValidateName("example.com")).To(Succeed())
+			Expect(ValidateName("*.example.com")).To(Succeed())
+		})
+
+		It("rejects bad names", func() {
+			Expect(ValidateName("Example_Com")).ToNot(Succeed())
+			Expect(ValidateName("*.*.example.com")).ToNot(Succeed())
+		})
+	})
+
+	Describe("checkRoutes", func() {
+		registry := models.DomainList{
+			{Name: "qa.example.com", Namespace: "qa"},
+			{Name: "*.corp.example.com", Namespace: "prod"},
+			{Name: "spare.example.com"},
+		}
+
+		It("allows the routes on the domains of the namespace", func() {
+			Expect(checkRoutes(registry, "qa", []string{
+				"myapp.qa.example.com/api",
+				"qa.example.com",
+			})).To(BeEmpty())
+		})
+
+		It("rejects the routes on domains of other namespaces", func() {
+			Expect(checkRoutes(registry, "qa", []string{
+				"myapp.qa.example.com",
+				"shop.eu.corp.example.com",
+			})).To(Equal([]string{
+				"shop.eu.corp.example.com",
+			}))
+		})
+
+		It("allows unregistered hosts and the hosts of unassigned domains", func() {
+			Expect(checkRoutes(registry, "qa", []string{
+				"myapp.main.io",
+				"myapp.elsewhere.io",
+				"myapp.spare.example.com",
+			})).To(BeEmpty())
+		})
+
+		It("rejects hosts also covered by a domain of another namespace", func() {
+			registry := models.DomainList{
+				{Name: "example.com", Namespace: "qa"},
+				{Name: "*.example.com", Namespace: "prod"},
+			}
+			Expect(checkRoutes(registry, "qa", []string{"myapp.example.com"})).To(Equal([]string{"myapp.example.com"}))
+		})
+	})
+
+	Describe("CheckAssignment", func() {
+		registry := models.DomainList{
+			{Name: "example.com", Namespace: "qa"},
+			{Name: "*.corp.example.org", Namespace: "prod"},
+			{Name: "spare.example.net"},
+			{Name: "*.spare.example.net"},
+		}
+
+		It("accepts domains without shared hosts, or shared within the namespace", func() {
+			Expect(CheckAssignment(registry, "spare.example.net", "dev")).To(Succeed())
+			Expect(CheckAssignment(registry, "*.spare.example.net", "dev")).To(Succeed())
+			Expect(CheckAssignment(registry, "example.org", "dev")).To(Succeed())
+			Expect(CheckAssignment(registry, "*.example.com", "qa")).To(Succeed())
+			Expect(CheckAssignment(registry, "*.example.com", "")).To(Succeed())
+		})
+
+		It("rejects a wildcard covering a plain domain of another namespace", func() {
+			Expect(CheckAssignment(registry, "*.example.com", "dev")).To(MatchError(
+				"domain '*.example.com' shares hosts with domain 'example.com' of namespace 'qa'"))
+		})
+
+		It("rejects a plain domain covered by a wildcard of another namespace", func() {
+			Expect(CheckAssignment(registry, "corp.example.org", "dev")).To(MatchError(
+				ContainSubstring("domain '*.corp.example.org' of namespace 'prod'")))
+			Expect(CheckAssignment(registry, "eu.corp.example.org", "dev")).To(MatchError(
+				ContainSubstring("domain '*.corp.example.org' of namespace 'prod'")))
+		})
+
+		It("rejects a plain domain one level below a plain domain of another namespace", func() {
+			Expect(CheckAssignment(registry, "shop.example.com", "prod")).To(MatchError(
+				ContainSubstring("domain 'example.com' of namespace 'qa'")))
+		})
+	})
+})
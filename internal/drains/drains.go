@@ -0,0 +1,230 @@
+// Package drains encapsulates all the functionality around log drains, i.e. the
+// syslog and http endpoints the logs of applications are forwarded to.
+// The drains of an application are stored in a secret owned by the application,
+// the drains of a namespace, covering all its applications, in a secret of the
+// namespace. The forwarding itself is done by the server, see Start.
+package drains
+
+import (
+	"context"
+	"net/url"
+	"sort"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// NamespaceSecretName is the name of the secret holding the log drains of
+	// an epinio-controlled namespace.
+	NamespaceSecretName = "epinio-namespace-drains"
+
+	// Area is the value of the area label of the secrets holding log drains
+	Area = "drain"
+)
+
+// List returns the log drains of the named application, ordered by url, and
+// the state of their forwarding. An empty application name returns the drains
+// of the namespace.
+func List(ctx context.Context, cluster *kubernetes.Cluster, namespace, app string) (models.DrainList, error) {
+	secret, err := cluster.GetSecret(ctx, namespace, secretName(namespace, app))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return models.DrainList{}, nil
+		}
+		return nil, err
+	}
+
+	return fromSecret(*secret), nil
+}
+
+// All returns the log drains of all applications and namespaces.
+func All(ctx context.Context, cluster *kubernetes.Cluster) (models.DrainList, error) {
+	selector := labels.Set(map[string]string{
+		"app.kubernetes.io/managed-by":         "epinio",
+		application.EpinioApplicationAreaLabel: Area,
+	}).AsSelector().String()
+
+	secrets, err := cluster.Kubectl.CoreV1().Secrets("").List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.DrainList{}
+	for _, secret := range secrets.Items {
+		result = append(result, fromSecret(secret)...)
+	}
+
+	return result, nil
+}
+
+// Add adds a log drain for the url to the named application, or, for an empty
+// application name, to the namespace. Adding a known url is a no-op. Validating
+// the url is the responsibility of the caller.
+func Add(ctx context.Context, cluster *kubernetes.Cluster, namespace, app, drainURL string) error {
+	return drainUpdate(ctx, cluster, namespace, app, func(secret *v1.Secret) {
+		secret.Data[models.DrainID(drainURL)] = []byte(drainURL)
+	})
+}
+
+// Remove removes the identified log drain from the named application, or, for
+// an empty application name, from the namespace. Removing an unknown drain is
+// a no-op.
+func Remove(ctx context.Context, cluster *kubernetes.Cluster, namespace, app, id string) error {
+	return drainUpdate(ctx, cluster, namespace, app, func(secret *v1.Secret) {
+		delete(secret.Data, id)
+	})
+}
+
+// Validate checks that the url is a supported log drain, i.e. a syslog
+// endpoint, over tcp, tls, or udp, or an http endpoint.
+func Validate(drainURL string) error {
+	u, err := url.Parse(drainURL)
+	if err != nil {
+		return errors.Wrapf(err, "bad drain url '%s'", drainURL)
+	}
+
+	switch u.Scheme {
+	case "syslog", "syslog+tls", "syslog+udp":
+		if u.Hostname() == "" || u.Port() == "" {
+			return errors.Errorf("bad drain url '%s', expected host and port", drainURL)
+		}
+	case "http", "https":
+		if u.Hostname() == "" {
+			return errors.Errorf("bad drain url '%s', expected host", drainURL)
+		}
+	default:
+		return errors.Errorf("bad drain url '%s', unsupported scheme '%s', expected one of syslog, syslog+tls, syslog+udp, http, https",
+			drainURL, u.Scheme)
+	}
+
+	return nil
+}
+
+// secretName returns the name of the secret holding the drains of the named
+// application, or, for an empty application name, of the namespace.
+func secretName(namespace, app string) string {
+	if app == "" {
+		return NamespaceSecretName
+	}
+	appRef := models.NewAppRef(app, namespace)
+	return appRef.MakeDrainSecretName()
+}
+
+// fromSecret returns the log drains stored in the secret, ordered by url, and
+// the state of their forwarding.
+func fromSecret(secret v1.Secret) models.DrainList {
+	app := ""
+	if secret.Labels["app.kubernetes.io/component"] == "application" {
+		app = secret.Labels["app.kubernetes.io/name"]
+	}
+
+	result := models.DrainList{}
+	for id, drainURL := range secret.Data {
+		drain := models.Drain{
+			ID:        id,
+			URL:       string(drainURL),
+			Namespace: secret.Namespace,
+			App:       app,
+		}
+		drain.Status = Status(drain)
+		result = append(result, drain)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].URL < result[j].URL
+	})
+
+	return result
+}
+
+// drainUpdate is the helper for the public functions encapsulating the
+// read/modify/write cycle necessary to update the kube resource holding the
+// drains of an application or namespace.
+func drainUpdate(ctx context.Context, cluster *kubernetes.Cluster,
+	namespace, app string, modifyDrains func(*v1.Secret)) error {
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := drainLoad(ctx, cluster, namespace, app)
+		if err != nil {
+			return err
+		}
+
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+
+		modifyDrains(secret)
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(namespace).Update(
+			ctx, secret, metav1.UpdateOptions{})
+
+		return err
+	})
+}
+
+// drainLoad locates and returns the kube secret storing the drains of the named
+// application or namespace. If necessary it creates that secret. The secret of
+// an application is owned by it, the secret of a namespace needs no owner, it is
+// removed together with the namespace.
+func drainLoad(ctx context.Context, cluster *kubernetes.Cluster, namespace, app string) (*v1.Secret, error) {
+	name := secretName(namespace, app)
+
+	secret, err := cluster.GetSecret(ctx, namespace, name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		// Error is `Not Found`. Create the secret.
+
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name":               name,
+					"app.kubernetes.io/part-of":            namespace,
+					"app.kubernetes.io/managed-by":         "epinio",
+					"app.kubernetes.io/component":          "namespace",
+					application.EpinioApplicationAreaLabel: Area,
+				},
+			},
+		}
+
+		if app != "" {
+			appCR, err := application.Get(ctx, cluster, models.NewAppRef(app, namespace))
+			if err != nil {
+				// Should not happen. The application was validated to exist already
+				// somewhere by this function's callers.
+				return nil, err
+			}
+
+			secret.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: appCR.GetAPIVersion(),
+				Kind:       appCR.GetKind(),
+				Name:       appCR.GetName(),
+				UID:        appCR.GetUID(),
+			}}
+			secret.Labels["app.kubernetes.io/name"] = app
+			secret.Labels["app.kubernetes.io/component"] = "application"
+		}
+
+		err = cluster.CreateSecret(ctx, namespace, *secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,83 @@
+package drains
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drains", func() {
+	Describe("Validate", func() {
+		It("accepts syslog endpoints with host and port", func() {
+			Expect(Validate("syslog://logs.example.com:514")).To(Succeed())
+			Expect(Validate("syslog+tls://logs.example.com:6514")).To(Succeed())
+			Expect(Validate("syslog+udp://10.0.0.1:514")).To(Succeed())
+		})
+
+		It("accepts http endpoints", func() {
+			Expect(Validate("https://logs.example.com/ingest?token=abc")).To(Succeed())
+		})
+
+		It("rejects syslog endpoints without port", func() {
+			Expect(Validate("syslog+tls://logs.example.com")).To(
+				MatchError("bad drain url 'syslog+tls://logs.example.com', expected host and port"))
+		})
+
+		It("rejects unsupported schemes", func() {
+			Expect(Validate("ftp://logs.example.com")).To(
+				MatchError(ContainSubstring("unsupported scheme 'ftp'")))
+		})
+	})
+
+	Describe("secretName", func() {
+		It("distinguishes namespace and application drains", func() {
+			Expect(secretName("workspace", "")).To(Equal(NamespaceSecretName))
+			Expect(secretName("workspace", "shop")).ToNot(Equal(NamespaceSecretName))
+		})
+	})
+
+	Describe("fromSecret", func() {
+		It("returns the drains of an application ordered by url", func() {
+			secret := v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "workspace",
+					Labels: map[string]string{
+						"app.kubernetes.io/name":      "shop",
+						"app.kubernetes.io/component": "application",
+					},
+				},
+				Data: map[string][]byte{
+					models.DrainID("syslog://b:514"): []byte("syslog://b:514"),
+					models.DrainID("https://a"):      []byte("https://a"),
+				},
+			}
+
+			Expect(fromSecret(secret)).To(Equal(models.DrainList{
+				{ID: models.DrainID("https://a"), URL: "https://a", Namespace: "workspace", App: "shop"},
+				{ID: models.DrainID("syslog://b:514"), URL: "syslog://b:514", Namespace: "workspace", App: "shop"},
+			}))
+		})
+
+		It("returns the drains of a namespace without application", func() {
+			secret := v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "workspace",
+					Labels: map[string]string{
+						"app.kubernetes.io/name":      NamespaceSecretName,
+						"app.kubernetes.io/component": "namespace",
+					},
+				},
+				Data: map[string][]byte{
+					models.DrainID("https://a"): []byte("https://a"),
+				},
+			}
+
+			Expect(fromSecret(secret)).To(Equal(models.DrainList{
+				{ID: models.DrainID("https://a"), URL: "https://a", Namespace: "workspace"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,352 @@
+package drains
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// reconcileInterval is the time between the checks for added and removed drains
+	reconcileInterval = 30 * time.Second
+
+	// history is the age of the oldest log lines forwarded when a drain is
+	// (re)started, and of the first lines of new pods
+	history = 10 * time.Second
+
+	// flushInterval is the time between deliveries to a drain
+	flushInterval = time.Second
+
+	// batchSize is the maximal number of log lines per delivery
+	batchSize = 100
+
+	// queueSize is the maximal number of log lines waiting for delivery. When
+	// exceeded the oldest lines are dropped.
+	queueSize = 10000
+
+	// minBackoff and maxBackoff bound the time between failed deliveries
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// forwarder is the forwarder of the server, if started.
+var forwarder *Forwarder
+
+// Start starts the forwarding of the logs of all applications to their drains,
+// and the drains of their namespaces. Drains added or removed later are picked
+// up within the reconcile interval. Forwarding stops when the context is done.
+func Start(ctx context.Context, cluster *kubernetes.Cluster, logger logr.Logger) {
+	forwarder = NewForwarder(cluster, logger)
+	go forwarder.Run(ctx)
+}
+
+// Status returns the state of the forwarding to the drain. Drains not followed
+// by the server are reported as inactive.
+func Status(drain models.Drain) models.DrainStatus {
+	if forwarder == nil {
+		return models.DrainStatus{}
+	}
+	return forwarder.Status(drain)
+}
+
+// drainKey identifies a drain across applications and namespaces.
+type drainKey struct {
+	namespace string
+	app       string
+	id        string
+}
+
+func keyOf(drain models.Drain) drainKey {
+	return drainKey{namespace: drain.Namespace, app: drain.App, id: drain.ID}
+}
+
+// Forwarder follows the logs of the applications with drains, and delivers
+// them to the drains, one runner per drain.
+type Forwarder struct {
+	cluster *kubernetes.Cluster
+	log     logr.Logger
+	mu      sync.Mutex
+	runners map[drainKey]*runner
+}
+
+// NewForwarder returns a forwarder for the drains of the cluster.
+func NewForwarder(cluster *kubernetes.Cluster, logger logr.Logger) *Forwarder {
+	return &Forwarder{
+		cluster: cluster,
+		log:     logger,
+		runners: map[drainKey]*runner{},
+	}
+}
+
+// Run reconciles the runners with the drains of the cluster, until the context
+// is done.
+func (f *Forwarder) Run(ctx context.Context) {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.reconcile(ctx); err != nil {
+			f.log.Error(err, "failed to reconcile log drains")
+		}
+
+		select {
+		case <-ctx.Done():
+			f.mu.Lock()
+			for key, r := range f.runners {
+				r.cancel()
+				delete(f.runners, key)
+			}
+			f.mu.Unlock()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status returns the state of the forwarding to the drain.
+func (f *Forwarder) Status(drain models.Drain) models.DrainStatus {
+	f.mu.Lock()
+	r, ok := f.runners[keyOf(drain)]
+	f.mu.Unlock()
+
+	if !ok {
+		return models.DrainStatus{}
+	}
+	return r.status()
+}
+
+// reconcile starts runners for new drains, and stops the runners of removed drains.
+func (f *Forwarder) reconcile(ctx context.Context) error {
+	drains, err := All(ctx, f.cluster)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	desired := map[drainKey]models.Drain{}
+	for _, drain := range drains {
+		desired[keyOf(drain)] = drain
+	}
+
+	for key, r := range f.runners {
+		if _, ok := desired[key]; !ok {
+			f.log.Info("stop drain", "namespace", key.namespace, "app", key.app, "id", key.id)
+			r.cancel()
+			delete(f.runners, key)
+		}
+	}
+
+	for key, drain := range desired {
+		if _, ok := f.runners[key]; ok {
+			continue
+		}
+
+		s, err := newSink(drain.URL)
+		if err != nil {
+			f.log.Error(err, "ignoring bad drain", "namespace", key.namespace, "app", key.app, "id", key.id)
+			continue
+		}
+
+		log := f.log.WithValues("namespace", key.namespace, "app", key.app, "id", key.id)
+		log.Info("start drain")
+
+		runCtx, cancel := context.WithCancel(requestctx.WithLogger(ctx, log))
+		r := newRunner(drain, s, cancel)
+		f.runners[key] = r
+
+		go r.run(runCtx, f.cluster, log)
+	}
+
+	return nil
+}
+
+// runner delivers the logs of the application or namespace of a drain to it.
+// The log lines are queued, and delivered in batches. Failed deliveries are
+// retried with exponential backoff.
+type runner struct {
+	drain   models.Drain
+	sink    sink
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	queue   []entry
+	state   models.DrainStatus
+	backoff time.Duration
+	retryAt time.Time
+}
+
+func newRunner(drain models.Drain, s sink, cancel context.CancelFunc) *runner {
+	return &runner{
+		drain:  drain,
+		sink:   s,
+		cancel: cancel,
+		state:  models.DrainStatus{Active: true},
+	}
+}
+
+// run follows the logs and delivers them, until the context is done.
+func (r *runner) run(ctx context.Context, cluster *kubernetes.Cluster, log logr.Logger) {
+	logChan := make(chan tailer.ContainerLogLine)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.follow(ctx, logChan, &wg, cluster, log)
+	}()
+
+	// The tails do not stop writing on their own. Keep reading until they are
+	// all done, then stop.
+	go func() {
+		<-ctx.Done()
+		wg.Wait()
+		close(logChan)
+	}()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case line, ok := <-logChan:
+			if !ok {
+				r.sink.Close()
+				return
+			}
+			if ctx.Err() != nil {
+				continue
+			}
+			r.enqueue(entry{Time: time.Now(), ContainerLogLine: line})
+		case <-ticker.C:
+			if ctx.Err() != nil {
+				continue
+			}
+			if err := r.flush(ctx, time.Now()); err != nil {
+				log.Info("delivery failed", "error", err.Error())
+			}
+		}
+	}
+}
+
+// follow writes the logs of the application or namespace of the drain to the
+// logChan, until the context is done. Failures to set up or keep the tailing
+// are recorded, and retried.
+func (r *runner) follow(ctx context.Context, logChan chan tailer.ContainerLogLine, wg *sync.WaitGroup,
+	cluster *kubernetes.Cluster, log logr.Logger) {
+
+	params := application.DefaultLogParameters()
+	params.Since = history
+
+	r.retry(ctx, log, func(ctx context.Context) error {
+		if r.drain.App == "" {
+			return application.NamespaceLogs(ctx, logChan, wg, cluster, true, r.drain.Namespace, nil, params)
+		}
+		return application.Logs(ctx, logChan, wg, cluster, true, r.drain.App, "", r.drain.Namespace, params)
+	})
+}
+
+// retry runs the tailing until the context is done. Each attempt has its own
+// context, cancelled when the attempt returns, to stop the tails it started
+// before the next attempt starts new ones. The error of a failed attempt is
+// recorded as the last error of the drain.
+func (r *runner) retry(ctx context.Context, log logr.Logger, tail func(context.Context) error) {
+	for {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		err := tail(attemptCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errors.New("log tailing ended")
+		}
+		log.Error(err, "failed to follow logs")
+
+		r.mu.Lock()
+		r.state.LastError = err.Error()
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(maxBackoff):
+		}
+	}
+}
+
+// enqueue adds the entry to the queue, dropping the oldest entry when full.
+func (r *runner) enqueue(e entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.queue) >= queueSize {
+		r.queue = r.queue[1:]
+		r.state.Dropped++
+	}
+	r.queue = append(r.queue, e)
+}
+
+// flush delivers the queued entries in batches, unless waiting for a retry. A
+// failed delivery doubles the time to the next attempt, a success resets it.
+func (r *runner) flush(ctx context.Context, now time.Time) error {
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 || now.Before(r.retryAt) {
+			r.mu.Unlock()
+			return nil
+		}
+		n := len(r.queue)
+		if n > batchSize {
+			n = batchSize
+		}
+		batch := append([]entry{}, r.queue[:n]...)
+		r.mu.Unlock()
+
+		sent, err := r.sink.Send(ctx, batch)
+
+		r.mu.Lock()
+		r.queue = r.queue[sent:]
+		r.state.Delivered += int64(sent)
+		if sent > 0 {
+			r.state.LastDelivery = now.UTC().Format(time.RFC3339)
+		}
+
+		if err != nil {
+			r.backoff *= 2
+			if r.backoff < minBackoff {
+				r.backoff = minBackoff
+			}
+			if r.backoff > maxBackoff {
+				r.backoff = maxBackoff
+			}
+			r.retryAt = now.Add(r.backoff)
+			r.state.Failures++
+			r.state.LastError = err.Error()
+			r.mu.Unlock()
+			return err
+		}
+
+		r.backoff = 0
+		r.state.LastError = ""
+		r.mu.Unlock()
+	}
+}
+
+// status returns the state of the forwarding.
+func (r *runner) status() models.DrainStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := r.state
+	result.Pending = len(r.queue)
+	return result
+}
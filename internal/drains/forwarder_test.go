@@ -0,0 +1,136 @@
+package drains
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeSink records the delivered entries, failing while err is set.
+type fakeSink struct {
+	delivered []entry
+	err       error
+}
+
+func (s *fakeSink) Send(ctx context.Context, entries []entry) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.delivered = append(s.delivered, entries...)
+	return len(entries), nil
+}
+
+func (s *fakeSink) Close() {}
+
+var _ = Describe("Forwarder", func() {
+	var s *fakeSink
+	var r *runner
+	now := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	lines := func(n int) {
+		for i := 0; i < n; i++ {
+			r.enqueue(entry{Time: now, ContainerLogLine: tailer.ContainerLogLine{Message: "hello"}})
+		}
+	}
+
+	BeforeEach(func() {
+		s = &fakeSink{}
+		r = newRunner(models.Drain{URL: "https://logs"}, s, func() {})
+	})
+
+	It("delivers the queued lines in batches", func() {
+		lines(batchSize + 10)
+
+		Expect(r.flush(context.Background(), now)).To(Succeed())
+		Expect(s.delivered).To(HaveLen(batchSize + 10))
+		Expect(r.status()).To(Equal(models.DrainStatus{
+			Active:       true,
+			Delivered:    batchSize + 10,
+			LastDelivery: "2022-03-04T05:06:07Z",
+		}))
+	})
+
+	It("drops the oldest lines when the queue is full", func() {
+		lines(queueSize + 3)
+
+		status := r.status()
+		Expect(status.Dropped).To(Equal(int64(3)))
+		Expect(status.Pending).To(Equal(queueSize))
+	})
+
+	It("retries failed deliveries with exponential backoff", func() {
+		s.err = errors.New("connection refused")
+		lines(1)
+
+		Expect(r.flush(context.Background(), now)).To(MatchError("connection refused"))
+		Expect(r.retryAt).To(Equal(now.Add(minBackoff)))
+
+		// Waiting for the retry
+		Expect(r.flush(context.Background(), now.Add(minBackoff/2))).To(Succeed())
+		Expect(r.status().Failures).To(Equal(int64(1)))
+
+		Expect(r.flush(context.Background(), now.Add(minBackoff))).To(HaveOccurred())
+		Expect(r.retryAt).To(Equal(now.Add(3 * minBackoff)))
+
+		status := r.status()
+		Expect(status.Failures).To(Equal(int64(2)))
+		Expect(status.Pending).To(Equal(1))
+		Expect(status.LastError).To(Equal("connection refused"))
+
+		s.err = nil
+		Expect(r.flush(context.Background(), now.Add(3*minBackoff))).To(Succeed())
+
+		status = r.status()
+		Expect(status.Delivered).To(Equal(int64(1)))
+		Expect(status.Pending).To(Equal(0))
+		Expect(status.LastError).To(BeEmpty())
+		Expect(r.backoff).To(BeZero())
+	})
+
+	It("caps the backoff", func() {
+		s.err = errors.New("connection refused")
+		lines(1)
+
+		at := now
+		for i := 0; i < 10; i++ {
+			Expect(r.flush(context.Background(), at)).To(HaveOccurred())
+			at = r.retryAt
+		}
+		Expect(r.backoff).To(Equal(maxBackoff))
+	})
+
+	It("records a lost tailing, and stops its tails before retrying", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		attempts := make(chan context.Context)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			r.retry(ctx, logr.Discard(), func(attemptCtx context.Context) error {
+				attempts <- attemptCtx
+				return errors.New("lost the watch of the pods")
+			})
+		}()
+
+		var attemptCtx context.Context
+		Eventually(attempts).Should(Receive(&attemptCtx))
+		Eventually(attemptCtx.Done()).Should(BeClosed())
+		Eventually(r.status).Should(HaveField("LastError", "lost the watch of the pods"))
+		Expect(r.status().Active).To(BeTrue())
+
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("reports drains not followed as inactive", func() {
+		Expect(Status(models.Drain{ID: "unknown"})).To(Equal(models.DrainStatus{}))
+	})
+})
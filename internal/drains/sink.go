@@ -0,0 +1,207 @@
+package drains
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/pkg/errors"
+)
+
+// sendTimeout limits the time spent on connecting to a drain, and on
+// delivering a batch of log lines to it.
+const sendTimeout = 10 * time.Second
+
+// entry is a log line waiting for delivery, with the time it was read.
+type entry struct {
+	Time time.Time
+	tailer.ContainerLogLine
+}
+
+// sink is the interface of the endpoints log lines are delivered to.
+type sink interface {
+	// Send delivers the entries in order, and returns the number of
+	// entries delivered before an error.
+	Send(ctx context.Context, entries []entry) (int, error)
+	// Close releases the resources held by the sink.
+	Close()
+}
+
+// newSink returns the sink delivering to the drain url.
+func newSink(drainURL string) (sink, error) {
+	if err := Validate(drainURL); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(drainURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "syslog":
+		return &syslogSink{network: "tcp", address: u.Host}, nil
+	case "syslog+tls":
+		return &syslogSink{network: "tcp", address: u.Host, tls: true}, nil
+	case "syslog+udp":
+		return &syslogSink{network: "udp", address: u.Host}, nil
+	}
+
+	return &httpSink{
+		url:    drainURL,
+		client: &http.Client{Timeout: sendTimeout},
+	}, nil
+}
+
+// syslogSink delivers log lines as RFC 5424 messages. Over tcp and tls the
+// messages are framed by octet counting (RFC 6587), over udp each message is a
+// datagram. The connection is kept across deliveries, and re-established after
+// a failure.
+type syslogSink struct {
+	network string
+	address string
+	tls     bool
+	conn    net.Conn
+}
+
+// Send implements sink
+func (s *syslogSink) Send(ctx context.Context, entries []entry) (int, error) {
+	if s.conn == nil {
+		dialer := &net.Dialer{Timeout: sendTimeout}
+
+		var conn net.Conn
+		var err error
+		if s.tls {
+			conn, err = tls.DialWithDialer(dialer, s.network, s.address, &tls.Config{
+				MinVersion: tls.VersionTLS12,
+			})
+		} else {
+			conn, err = dialer.DialContext(ctx, s.network, s.address)
+		}
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to connect to %s", s.address)
+		}
+		s.conn = conn
+	}
+
+	if err := s.conn.SetWriteDeadline(time.Now().Add(sendTimeout)); err != nil {
+		s.Close()
+		return 0, err
+	}
+
+	for i, e := range entries {
+		message := syslogMessage(e)
+		if s.network != "udp" {
+			message = fmt.Sprintf("%d %s", len(message), message)
+		}
+
+		if _, err := s.conn.Write([]byte(message)); err != nil {
+			s.Close()
+			return i, errors.Wrapf(err, "failed to write to %s", s.address)
+		}
+	}
+
+	return len(entries), nil
+}
+
+// Close implements sink
+func (s *syslogSink) Close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// syslogMessage returns the RFC 5424 message for the entry. The hostname is
+// the namespace, the app name the application, the process id the pod, and the
+// message id the container of the log line. Severity is informational, facility
+// user-level.
+func syslogMessage(e entry) string {
+	return fmt.Sprintf("<14>1 %s %s %s %s %s - %s",
+		e.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogField(e.Namespace, 255),
+		syslogField(e.AppName, 48),
+		syslogField(e.PodName, 128),
+		syslogField(e.ContainerName, 32),
+		strings.TrimRight(e.Message, "\r\n"))
+}
+
+// syslogField returns the value as a syslog header field, i.e. limited to the
+// maximal length, and the nil value `-` when empty.
+func syslogField(value string, max int) string {
+	if value == "" {
+		return "-"
+	}
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
+
+// httpSink delivers batches of log lines as JSON arrays of httpRecords, posted
+// to the url.
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+// httpRecord is the form of a log line delivered to an http drain.
+type httpRecord struct {
+	Time      string `json:"time"`
+	Namespace string `json:"namespace"`
+	App       string `json:"app"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Message   string `json:"message"`
+}
+
+// Send implements sink
+func (s *httpSink) Send(ctx context.Context, entries []entry) (int, error) {
+	records := []httpRecord{}
+	for _, e := range entries {
+		records = append(records, httpRecord{
+			Time:      e.Time.UTC().Format(time.RFC3339Nano),
+			Namespace: e.Namespace,
+			App:       e.AppName,
+			Pod:       e.PodName,
+			Container: e.ContainerName,
+			Message:   strings.TrimRight(e.Message, "\r\n"),
+		})
+	}
+
+	body, err := json.Marshal(records)
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return 0, errors.Errorf("drain responded with status %s", response.Status)
+	}
+
+	return len(entries), nil
+}
+
+// Close implements sink
+func (s *httpSink) Close() {
+	s.client.CloseIdleConnections()
+}
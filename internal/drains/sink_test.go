@@ -0,0 +1,132 @@
+package drains
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sinks", func() {
+	stamp := time.Date(2022, 3, 4, 5, 6, 7, 8000, time.UTC)
+	line := entry{
+		Time: stamp,
+		ContainerLogLine: tailer.ContainerLogLine{
+			Message:       "hello\n",
+			ContainerName: "shop",
+			PodName:       "shop-5d8b9c-x7k2p",
+			Namespace:     "workspace",
+			AppName:       "shop",
+		},
+	}
+
+	Describe("syslogMessage", func() {
+		It("formats the entry as RFC 5424 message", func() {
+			Expect(syslogMessage(line)).To(Equal(
+				"<14>1 2022-03-04T05:06:07.000008Z workspace shop shop-5d8b9c-x7k2p shop - hello"))
+		})
+
+		It("uses the nil value for missing fields", func() {
+			Expect(syslogMessage(entry{Time: stamp, ContainerLogLine: tailer.ContainerLogLine{Message: "x"}})).To(Equal(
+				"<14>1 2022-03-04T05:06:07.000008Z - - - - - x"))
+		})
+	})
+
+	Describe("syslogSink", func() {
+		It("delivers octet-counted messages over tcp", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			defer listener.Close()
+
+			received := make(chan string, 1)
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				data := make([]byte, 512)
+				n, _ := reader.Read(data)
+				received <- string(data[:n])
+			}()
+
+			s, err := newSink("syslog://" + listener.Addr().String())
+			Expect(err).ToNot(HaveOccurred())
+			defer s.Close()
+
+			sent, err := s.Send(context.Background(), []entry{line})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sent).To(Equal(1))
+
+			message := syslogMessage(line)
+			Eventually(received).Should(Receive(Equal(fmt.Sprintf("%d %s", len(message), message))))
+		})
+
+		It("fails when the drain is unreachable", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			address := listener.Addr().String()
+			listener.Close()
+
+			s, err := newSink("syslog://" + address)
+			Expect(err).ToNot(HaveOccurred())
+
+			sent, err := s.Send(context.Background(), []entry{line})
+			Expect(err).To(MatchError(ContainSubstring("failed to connect")))
+			Expect(sent).To(Equal(0))
+		})
+	})
+
+	Describe("httpSink", func() {
+		It("posts the batch as JSON array", func() {
+			var records []httpRecord
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+				Expect(json.NewDecoder(r.Body).Decode(&records)).To(Succeed())
+			}))
+			defer server.Close()
+
+			s, err := newSink(server.URL + "/ingest")
+			Expect(err).ToNot(HaveOccurred())
+			defer s.Close()
+
+			sent, err := s.Send(context.Background(), []entry{line, line})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sent).To(Equal(2))
+			Expect(records).To(HaveLen(2))
+			Expect(records[0]).To(Equal(httpRecord{
+				Time:      "2022-03-04T05:06:07.000008Z",
+				Namespace: "workspace",
+				App:       "shop",
+				Pod:       "shop-5d8b9c-x7k2p",
+				Container: "shop",
+				Message:   "hello",
+			}))
+		})
+
+		It("fails on error responses", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			s, err := newSink(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+
+			sent, err := s.Send(context.Background(), []entry{line})
+			Expect(err).To(MatchError("drain responded with status 503 Service Unavailable"))
+			Expect(sent).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/spf13/viper"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// GatewayName is the name of the Gateway API provider
+const GatewayName = "gateway"
+
+// Gateway exposes routes through a Gateway API HTTPRoute attached to the
+// gateway configured by the server options `gateway-name` and
+// `gateway-namespace`. The Ingress of the route is kept as Epinio's record of
+// the route and as owner of its HTTPRoute and certificate. It should use an
+// ingress class no controller serves, see the server option
+// `ingress-class-name`. TLS termination is configured on the gateway's
+// listeners.
+//
+//   - Timeout:         the request timeout of the HTTPRoute rule.
+//   - HTTPSRedirect:   not supported per route, configure it on the gateway.
+//   - MaxBodySize:     not supported by the Gateway API.
+//   - AllowList:       not supported by the Gateway API.
+//   - BasicAuthSecret: not supported by the Gateway API.
+//
+// Routes using an unsupported option are rejected.
+type Gateway struct{}
+
+// Name implements Provider
+func (Gateway) Name() string {
+	return GatewayName
+}
+
+// ServiceAnnotations implements Provider. The gateway learns about h2c from the
+// application protocol of the gRPC service's port.
+func (Gateway) ServiceAnnotations(routeType routes.Type) map[string]string {
+	return map[string]string{}
+}
+
+// IngressAnnotations implements Provider
+func (Gateway) IngressAnnotations(namespace, ingressName string, route routes.Route) map[string]string {
+	return map[string]string{}
+}
+
+// Unsupported implements Provider
+func (Gateway) Unsupported(options routes.Options) []string {
+	unsupported := []string{}
+	if options.HTTPSRedirect {
+		unsupported = append(unsupported, "https-redirect")
+	}
+	if options.MaxBodySize > 0 {
+		unsupported = append(unsupported, "max-body-size")
+	}
+	if len(options.AllowList) > 0 {
+		unsupported = append(unsupported, "allow")
+	}
+	if options.BasicAuthSecret != "" {
+		unsupported = append(unsupported, "basic-auth")
+	}
+	return unsupported
+}
+
+// Sync implements Provider
+func (g Gateway) Sync(ctx context.Context, cluster *kubernetes.Cluster, ingress *networkingv1.Ingress, route routes.Route) error {
+	if err := CheckSupported(g, route); err != nil {
+		return err
+	}
+
+	httpRoutes, err := cluster.ClientHTTPRoute()
+	if err != nil {
+		return err
+	}
+
+	return apply(ctx, httpRoutes, newHTTPRoute(ingress, route,
+		viper.GetString("gateway-name"), viper.GetString("gateway-namespace")))
+}
+
+// newHTTPRoute returns the HTTPRoute attaching the route to the gateway, and
+// sending its requests to the service of the ingress.
+func newHTTPRoute(ingress *networkingv1.Ingress, route routes.Route, gatewayName, gatewayNamespace string) *unstructured.Unstructured {
+	serviceName, servicePort := backend(ingress)
+
+	parent := map[string]interface{}{"name": gatewayName}
+	if gatewayNamespace != "" {
+		parent["namespace"] = gatewayNamespace
+	}
+
+	rule := map[string]interface{}{
+		"matches": []interface{}{
+			map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":  "PathPrefix",
+					"value": route.Path,
+				},
+			},
+		},
+		"backendRefs": []interface{}{
+			map[string]interface{}{"name": serviceName, "port": servicePort},
+		},
+	}
+	if route.Options.Timeout > 0 {
+		rule["timeouts"] = map[string]interface{}{
+			"request": fmt.Sprintf("%ds", seconds(route.Options.Timeout)),
+		}
+	}
+
+	return newObject(ingress, "gateway.networking.k8s.io/v1", "HTTPRoute", ingress.Name,
+		map[string]interface{}{
+			"parentRefs": []interface{}{parent},
+			"hostnames":  []interface{}{route.Domain},
+			"rules":      []interface{}{rule},
+		})
+}
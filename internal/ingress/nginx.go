@@ -0,0 +1,82 @@
+package ingress
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/routes"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// NginxName is the name of the NGINX provider
+const NginxName = "nginx"
+
+// Nginx exposes routes through the ingress-nginx controller. gRPC routes use
+// the GRPC backend protocol. All options are implemented with annotations on
+// the Ingress:
+//
+//   - HTTPSRedirect:   force-ssl-redirect
+//   - Timeout:         proxy-read-timeout and proxy-send-timeout
+//   - MaxBodySize:     proxy-body-size
+//   - AllowList:       whitelist-source-range
+//   - BasicAuthSecret: auth-type, auth-secret and auth-secret-type
+type Nginx struct{}
+
+// Name implements Provider
+func (Nginx) Name() string {
+	return NginxName
+}
+
+// ServiceAnnotations implements Provider
+func (Nginx) ServiceAnnotations(routeType routes.Type) map[string]string {
+	return map[string]string{}
+}
+
+// IngressAnnotations implements Provider
+func (Nginx) IngressAnnotations(namespace, ingressName string, route routes.Route) map[string]string {
+	annotations := map[string]string{}
+
+	if route.Type == routes.TypeGRPC {
+		annotations["nginx.ingress.kubernetes.io/backend-protocol"] = "GRPC"
+	}
+	if route.Options.HTTPSRedirect {
+		annotations["nginx.ingress.kubernetes.io/force-ssl-redirect"] = "true"
+	}
+	if route.Options.Timeout > 0 {
+		timeout := strconv.FormatInt(seconds(route.Options.Timeout), 10)
+		annotations["nginx.ingress.kubernetes.io/proxy-read-timeout"] = timeout
+		annotations["nginx.ingress.kubernetes.io/proxy-send-timeout"] = timeout
+	}
+	if route.Options.MaxBodySize > 0 {
+		annotations["nginx.ingress.kubernetes.io/proxy-body-size"] = strconv.FormatInt(route.Options.MaxBodySize, 10)
+	}
+	if len(route.Options.AllowList) > 0 {
+		annotations["nginx.ingress.kubernetes.io/whitelist-source-range"] = strings.Join(route.Options.AllowList, ",")
+	}
+	if route.Options.BasicAuthSecret != "" {
+		annotations["nginx.ingress.kubernetes.io/auth-type"] = "basic"
+		annotations["nginx.ingress.kubernetes.io/auth-secret"] = route.Options.BasicAuthSecret
+		annotations["nginx.ingress.kubernetes.io/auth-secret-type"] = "auth-file"
+	}
+
+	return annotations
+}
+
+// Unsupported implements Provider. NGINX supports all options.
+func (Nginx) Unsupported(options routes.Options) []string {
+	return []string{}
+}
+
+// Sync implements Provider. NGINX needs no resources beyond the Ingress. It reads
+// the htpasswd file of a basic-auth route from the key `auth` of its secret, which
+// is checked here.
+func (Nginx) Sync(ctx context.Context, cluster *kubernetes.Cluster, ingress *networkingv1.Ingress, route routes.Route) error {
+	if route.Options.BasicAuthSecret == "" {
+		return nil
+	}
+
+	_, err := basicAuthUsers(ctx, cluster, ingress, route)
+	return err
+}
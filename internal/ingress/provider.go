@@ -0,0 +1,209 @@
+// Package ingress abstracts the ingress controller exposing the routes of applications.
+//
+// Epinio records every route of an application as a kube Ingress, which also
+// serves as owner of the route's certificate. The provider, selected by the
+// server option `ingress-provider`, decides how the controller is told about
+// the route: through annotations on the Ingress itself, and/or through
+// additional, controller specific, resources owned by the Ingress.
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/routes"
+	"github.com/spf13/viper"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// Provider is the interface of the supported ingress controllers.
+type Provider interface {
+	// Name returns the name the provider is selected by.
+	Name() string
+
+	// ServiceAnnotations returns the annotations for a Service of an application,
+	// serving the routes of the given type.
+	ServiceAnnotations(routeType routes.Type) map[string]string
+
+	// IngressAnnotations returns the annotations for the Ingress of a route.
+	IngressAnnotations(namespace, ingressName string, route routes.Route) map[string]string
+
+	// Unsupported returns the names of the options, as used in the string form
+	// of a route, which are set and which the provider does not support.
+	Unsupported(options routes.Options) []string
+
+	// Sync creates, updates, or removes the provider specific resources needed to
+	// expose the route of the Ingress with its options. These resources are owned
+	// by the Ingress, deleting it removes them as well. Routes with options the
+	// provider does not support are rejected, see CheckSupported.
+	Sync(ctx context.Context, cluster *kubernetes.Cluster, ingress *networkingv1.Ingress, route routes.Route) error
+}
+
+var providers = map[string]Provider{
+	TraefikName: Traefik{},
+	NginxName:   Nginx{},
+	GatewayName: Gateway{},
+}
+
+// Lookup returns the named provider. An empty name selects Traefik.
+func Lookup(name string) (Provider, error) {
+	if name == "" {
+		name = TraefikName
+	}
+
+	provider, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown ingress provider '%s', expected one of %v", name, Names())
+	}
+
+	return provider, nil
+}
+
+// Names returns the sorted names of all providers.
+func Names() []string {
+	result := []string{}
+	for name := range providers {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Current returns the provider selected by the server configuration. The
+// server validates the configuration on startup, an invalid name falls back to
+// Traefik.
+func Current() Provider {
+	provider, err := Lookup(viper.GetString("ingress-provider"))
+	if err != nil {
+		return Traefik{}
+	}
+	return provider
+}
+
+// BasicAuthKey is the key of the secret named by the `basic-auth` option of a route,
+// holding the htpasswd file of the users. All providers supporting the option
+// accept such secrets.
+const BasicAuthKey = "auth"
+
+// basicAuthUsers returns the htpasswd file of the basic-auth secret of the route,
+// in the namespace of the ingress.
+func basicAuthUsers(ctx context.Context, cluster *kubernetes.Cluster, ingress *networkingv1.Ingress, route routes.Route) ([]byte, error) {
+	secret, err := cluster.GetSecret(ctx, ingress.Namespace, route.Options.BasicAuthSecret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("basic-auth secret '%s' not found", route.Options.BasicAuthSecret)
+		}
+		return nil, err
+	}
+
+	return htpasswd(secret)
+}
+
+// htpasswd returns the htpasswd file of a basic-auth secret. It is an error for the
+// secret to not have the BasicAuthKey, or an empty file.
+func htpasswd(secret *v1.Secret) ([]byte, error) {
+	users := secret.Data[BasicAuthKey]
+	if len(users) == 0 {
+		return nil, fmt.Errorf("basic-auth secret '%s' has no htpasswd file in key '%s'", secret.Name, BasicAuthKey)
+	}
+	return users, nil
+}
+
+// CheckSupported returns an error naming the first option of the route the
+// provider does not support. Routes with such options are never exposed, as
+// ignoring an option like `allow` or `basic-auth` would serve the route to
+// everyone. TCP routes are not exposed through the provider, all their options
+// are supported.
+func CheckSupported(provider Provider, route routes.Route) error {
+	if route.Type == routes.TypeTCP {
+		return nil
+	}
+
+	unsupported := provider.Unsupported(route.Options)
+	if len(unsupported) > 0 {
+		return fmt.Errorf("route option '%s' of route '%s' is not supported by ingress provider '%s'",
+			unsupported[0], route.String(), provider.Name())
+	}
+
+	return nil
+}
+
+// ownerReference returns the reference making the ingress the owner of a
+// provider specific resource.
+func ownerReference(ingress *networkingv1.Ingress) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+		Name:       ingress.Name,
+		UID:        ingress.UID,
+	}
+}
+
+// newObject returns the skeleton of a provider specific resource for the ingress,
+// carrying the same labels, and owned by it.
+func newObject(ingress *networkingv1.Ingress, apiVersion, kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"spec":       spec,
+		},
+	}
+	obj.SetName(name)
+	obj.SetNamespace(ingress.Namespace)
+	obj.SetLabels(ingress.Labels)
+	obj.SetOwnerReferences([]metav1.OwnerReference{ownerReference(ingress)})
+
+	return obj
+}
+
+// backend returns the name and port of the service the ingress sends requests to.
+func backend(ingress *networkingv1.Ingress) (string, int64) {
+	service := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	if service == nil {
+		return "", 0
+	}
+	return service.Name, int64(service.Port.Number)
+}
+
+// seconds returns the duration in whole seconds, rounded up.
+func seconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// apply creates the resource, or updates it if it exists already.
+func apply(ctx context.Context, client dynamic.NamespaceableResourceInterface, obj *unstructured.Unstructured) error {
+	resources := client.Namespace(obj.GetNamespace())
+
+	_, err := resources.Create(ctx, obj, metav1.CreateOptions{})
+	if err == nil || !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	current, err := resources.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	obj.SetResourceVersion(current.GetResourceVersion())
+	_, err = resources.Update(ctx, obj, metav1.UpdateOptions{})
+
+	return err
+}
+
+// remove deletes the named resource. A missing resource is not an error.
+func remove(ctx context.Context, client dynamic.NamespaceableResourceInterface, namespace, name string) error {
+	err := client.Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
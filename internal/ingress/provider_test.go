@@ -0,0 +1,239 @@
+package ingress
+
+import (
+	"time"
+
+	"github.com/epinio/epinio/internal/routes"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ingress providers", func() {
+	var route routes.Route
+	var ingress *networkingv1.Ingress
+
+	BeforeEach(func() {
+		route = routes.FromString("myapp.example.com/api")
+		ingress = &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myapp-api",
+				Namespace: "workspace",
+				UID:       "1234",
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{{
+					Host: route.Domain,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path: route.Path,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: "rmyapp",
+										Port: networkingv1.ServiceBackendPort{Number: 8080},
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	})
+
+	Describe("basic-auth secrets", func() {
+		It("hold the htpasswd file in key auth", func() {
+			secret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "testers"},
+				Data:       map[string][]byte{"auth": []byte("tester:$apr1$x$y")},
+			}
+			Expect(htpasswd(secret)).To(Equal([]byte("tester:$apr1$x$y")))
+		})
+
+		It("are rejected without key auth", func() {
+			secret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "testers"},
+				Data:       map[string][]byte{"users": []byte("tester:$apr1$x$y")},
+			}
+			_, err := htpasswd(secret)
+			Expect(err).To(MatchError("basic-auth secret 'testers' has no htpasswd file in key 'auth'"))
+		})
+	})
+
+	Describe("CheckSupported", func() {
+		It("accepts the options supported by the provider", func() {
+			route.Options.Timeout = 30 * time.Second
+			Expect(CheckSupported(Nginx{}, route)).To(Succeed())
+			Expect(CheckSupported(Gateway{}, route)).To(Succeed())
+		})
+
+		It("rejects the timeout option under traefik", func() {
+			route.Options.Timeout = 30 * time.Second
+			Expect(CheckSupported(Traefik{}, route)).To(MatchError(
+				"route option 'timeout' of route 'myapp.example.com/api' is not supported by ingress provider 'traefik'"))
+		})
+
+		It("rejects access restrictions under the gateway provider", func() {
+			route.Options.AllowList = []string{"10.0.0.0/8"}
+			Expect(CheckSupported(Gateway{}, route)).To(MatchError(ContainSubstring("route option 'allow'")))
+
+			route.Options.AllowList = nil
+			route.Options.BasicAuthSecret = "testers"
+			Expect(CheckSupported(Gateway{}, route)).To(MatchError(ContainSubstring("route option 'basic-auth'")))
+		})
+
+		It("accepts all options of tcp routes", func() {
+			route = routes.FromString("tcp://1883?allow=10.0.0.0/8")
+			Expect(CheckSupported(Gateway{}, route)).To(Succeed())
+		})
+	})
+
+	Describe("Lookup", func() {
+		It("defaults to traefik", func() {
+			provider, err := Lookup("")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(provider.Name()).To(Equal(TraefikName))
+		})
+
+		It("rejects unknown providers", func() {
+			_, err := Lookup("haproxy")
+			Expect(err).To(MatchError(ContainSubstring("unknown ingress provider 'haproxy'")))
+		})
+	})
+
+	Describe("Traefik", func() {
+		It("talks h2c to the service of grpc routes", func() {
+			Expect(Traefik{}.ServiceAnnotations(routes.TypeGRPC)).To(
+				HaveKeyWithValue("traefik.ingress.kubernetes.io/service.serversscheme", "h2c"))
+			Expect(Traefik{}.ServiceAnnotations(routes.TypeHTTP)).ToNot(
+				HaveKey("traefik.ingress.kubernetes.io/service.serversscheme"))
+		})
+
+		It("serves routes on the secure entrypoint", func() {
+			Expect(Traefik{}.IngressAnnotations("workspace", "myapp-api", route)).To(Equal(map[string]string{
+				"traefik.ingress.kubernetes.io/router.entrypoints": "websecure",
+				"traefik.ingress.kubernetes.io/router.tls":         "true",
+			}))
+		})
+
+		It("references the middlewares of the options", func() {
+			route.Options.MaxBodySize = 1024
+			route.Options.AllowList = []string{"10.0.0.0/8"}
+			Expect(Traefik{}.IngressAnnotations("workspace", "myapp-api", route)).To(HaveKeyWithValue(
+				"traefik.ingress.kubernetes.io/router.middlewares",
+				"workspace-myapp-api-allow@kubernetescrd,workspace-myapp-api-body@kubernetescrd"))
+		})
+
+		It("describes the middlewares of the options", func() {
+			route.Options.MaxBodySize = 1024
+			route.Options.BasicAuthSecret = "testers"
+
+			middlewares := traefikMiddlewares("myapp-api", route)
+			Expect(middlewares).To(HaveLen(3))
+			Expect(middlewares[0].needed).To(BeFalse())
+			Expect(middlewares[1]).To(Equal(traefikMiddleware{
+				name:   "myapp-api-auth",
+				needed: true,
+				spec: map[string]interface{}{
+					"basicAuth": map[string]interface{}{"secret": "myapp-api-auth"},
+				},
+			}))
+			Expect(middlewares[2]).To(Equal(traefikMiddleware{
+				name:   "myapp-api-body",
+				needed: true,
+				spec: map[string]interface{}{
+					"buffering": map[string]interface{}{"maxRequestBodyBytes": int64(1024)},
+				},
+			}))
+		})
+
+		It("copies the htpasswd file of the basic-auth secret into the key read by traefik", func() {
+			source := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "testers", Namespace: "workspace"},
+				Data:       map[string][]byte{BasicAuthKey: []byte("tester:$apr1$x$y")},
+			}
+			users, err := htpasswd(source)
+			Expect(err).ToNot(HaveOccurred())
+
+			secret := newTraefikAuthSecret(ingress, users)
+			Expect(secret.Name).To(Equal("myapp-api-auth"))
+			Expect(secret.OwnerReferences).To(ConsistOf(ownerReference(ingress)))
+			Expect(secret.Data).To(Equal(map[string][]byte{"users": []byte("tester:$apr1$x$y")}))
+		})
+
+		It("redirects plain http through an ingress route", func() {
+			ingressRoute := newTraefikRedirectRoute(ingress, route)
+			Expect(ingressRoute.GetName()).To(Equal("myapp-api-redirect"))
+
+			rules, _, _ := unstructured.NestedSlice(ingressRoute.Object, "spec", "routes")
+			Expect(rules).To(HaveLen(1))
+			Expect(rules[0]).To(HaveKeyWithValue("match", "Host(`myapp.example.com`) && PathPrefix(`/api`)"))
+		})
+	})
+
+	Describe("Nginx", func() {
+		It("adds no annotations without options", func() {
+			Expect(Nginx{}.IngressAnnotations("workspace", "myapp-api", route)).To(BeEmpty())
+		})
+
+		It("talks grpc to the backend of grpc routes", func() {
+			route = routes.FromString("grpc://myapp.example.com")
+			Expect(Nginx{}.IngressAnnotations("workspace", "myapp", route)).To(Equal(map[string]string{
+				"nginx.ingress.kubernetes.io/backend-protocol": "GRPC",
+			}))
+		})
+
+		It("translates the options into annotations", func() {
+			route.Options = routes.Options{
+				HTTPSRedirect:   true,
+				Timeout:         1500 * time.Millisecond,
+				MaxBodySize:     1048576,
+				AllowList:       []string{"10.0.0.0/8", "192.168.1.1/32"},
+				BasicAuthSecret: "testers",
+			}
+			Expect(Nginx{}.IngressAnnotations("workspace", "myapp-api", route)).To(Equal(map[string]string{
+				"nginx.ingress.kubernetes.io/force-ssl-redirect":     "true",
+				"nginx.ingress.kubernetes.io/proxy-read-timeout":     "2",
+				"nginx.ingress.kubernetes.io/proxy-send-timeout":     "2",
+				"nginx.ingress.kubernetes.io/proxy-body-size":        "1048576",
+				"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/8,192.168.1.1/32",
+				"nginx.ingress.kubernetes.io/auth-type":              "basic",
+				"nginx.ingress.kubernetes.io/auth-secret":            "testers",
+				"nginx.ingress.kubernetes.io/auth-secret-type":       "auth-file",
+			}))
+		})
+	})
+
+	Describe("Gateway", func() {
+		It("attaches the route to the gateway", func() {
+			httpRoute := newHTTPRoute(ingress, route, "public", "gateways")
+			Expect(httpRoute.GetName()).To(Equal("myapp-api"))
+			Expect(httpRoute.GetNamespace()).To(Equal("workspace"))
+
+			parents, _, _ := unstructured.NestedSlice(httpRoute.Object, "spec", "parentRefs")
+			Expect(parents).To(Equal([]interface{}{
+				map[string]interface{}{"name": "public", "namespace": "gateways"},
+			}))
+
+			hostnames, _, _ := unstructured.NestedStringSlice(httpRoute.Object, "spec", "hostnames")
+			Expect(hostnames).To(Equal([]string{"myapp.example.com"}))
+
+			rules, _, _ := unstructured.NestedSlice(httpRoute.Object, "spec", "rules")
+			Expect(rules).To(HaveLen(1))
+			Expect(rules[0]).ToNot(HaveKey("timeouts"))
+		})
+
+		It("sets the request timeout", func() {
+			route.Options.Timeout = time.Minute
+			httpRoute := newHTTPRoute(ingress, route, "public", "")
+
+			rules, _, _ := unstructured.NestedSlice(httpRoute.Object, "spec", "rules")
+			Expect(rules[0]).To(HaveKeyWithValue("timeouts", map[string]interface{}{"request": "60s"}))
+		})
+	})
+})
@@ -0,0 +1,270 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/routes"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TraefikName is the name of the Traefik provider
+const TraefikName = "traefik"
+
+// Traefik exposes routes through the Traefik ingress controller. The route is
+// served on the `websecure` entrypoint only. gRPC routes use the h2c scheme of
+// their service. Options are implemented with Traefik middlewares:
+//
+//   - HTTPSRedirect:   an IngressRoute on the `web` entrypoint, redirecting to https.
+//   - MaxBodySize:     a buffering middleware attached to the Ingress.
+//   - AllowList:       an ipWhiteList middleware attached to the Ingress.
+//   - BasicAuthSecret: a basicAuth middleware attached to the Ingress. Traefik reads
+//     the users from the key `users`, the htpasswd file of the route's secret is
+//     copied into a secret of that shape, owned by the Ingress.
+//   - Timeout:         not supported per route, routes using it are rejected.
+type Traefik struct{}
+
+// traefikUsersKey is the key of the secret of a basicAuth middleware holding the
+// htpasswd file.
+const traefikUsersKey = "users"
+
+// Name implements Provider
+func (Traefik) Name() string {
+	return TraefikName
+}
+
+// ServiceAnnotations implements Provider
+func (Traefik) ServiceAnnotations(routeType routes.Type) map[string]string {
+	annotations := map[string]string{
+		"traefik.ingress.kubernetes.io/router.entrypoints": "websecure",
+		"traefik.ingress.kubernetes.io/router.tls":         "true",
+	}
+
+	if routeType == routes.TypeGRPC {
+		annotations["traefik.ingress.kubernetes.io/service.serversscheme"] = "h2c"
+	}
+
+	return annotations
+}
+
+// IngressAnnotations implements Provider
+func (Traefik) IngressAnnotations(namespace, ingressName string, route routes.Route) map[string]string {
+	annotations := map[string]string{
+		"traefik.ingress.kubernetes.io/router.entrypoints": "websecure",
+		"traefik.ingress.kubernetes.io/router.tls":         "true",
+	}
+
+	middlewares := []string{}
+	for _, middleware := range traefikMiddlewares(ingressName, route) {
+		if middleware.needed {
+			middlewares = append(middlewares, fmt.Sprintf("%s-%s@kubernetescrd", namespace, middleware.name))
+		}
+	}
+	if len(middlewares) > 0 {
+		annotations["traefik.ingress.kubernetes.io/router.middlewares"] = strings.Join(middlewares, ",")
+	}
+
+	return annotations
+}
+
+// Unsupported implements Provider
+func (Traefik) Unsupported(options routes.Options) []string {
+	unsupported := []string{}
+	if options.Timeout > 0 {
+		unsupported = append(unsupported, "timeout")
+	}
+	return unsupported
+}
+
+// Sync implements Provider
+func (t Traefik) Sync(ctx context.Context, cluster *kubernetes.Cluster, ingress *networkingv1.Ingress, route routes.Route) error {
+	if err := CheckSupported(t, route); err != nil {
+		return err
+	}
+
+	middlewares, err := cluster.ClientTraefikMiddleware()
+	if err != nil {
+		return err
+	}
+	ingressRoutes, err := cluster.ClientTraefikIngressRoute()
+	if err != nil {
+		return err
+	}
+
+	err = syncTraefikAuthSecret(ctx, cluster, ingress, route)
+	if err != nil {
+		return err
+	}
+
+	for _, middleware := range traefikMiddlewares(ingress.Name, route) {
+		if middleware.needed {
+			err = apply(ctx, middlewares, newObject(ingress, "traefik.containo.us/v1alpha1", "Middleware",
+				middleware.name, middleware.spec))
+		} else {
+			err = remove(ctx, middlewares, ingress.Namespace, middleware.name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	redirectName := traefikRedirectName(ingress.Name)
+	if route.Options.HTTPSRedirect {
+		err = apply(ctx, middlewares, newTraefikRedirectMiddleware(ingress))
+		if err != nil {
+			return err
+		}
+		return apply(ctx, ingressRoutes, newTraefikRedirectRoute(ingress, route))
+	}
+
+	err = remove(ctx, ingressRoutes, ingress.Namespace, redirectName)
+	if err != nil {
+		return err
+	}
+	return remove(ctx, middlewares, ingress.Namespace, redirectName)
+}
+
+// traefikMiddleware describes one of the middlewares attached to the ingress of a route.
+type traefikMiddleware struct {
+	name   string
+	needed bool
+	spec   map[string]interface{}
+}
+
+// traefikMiddlewares returns the middlewares for the options of the route, in
+// the order they are applied to requests. Middlewares not needed for the
+// options have no spec.
+func traefikMiddlewares(ingressName string, route routes.Route) []traefikMiddleware {
+	options := route.Options
+
+	allowList := []interface{}{}
+	for _, cidr := range options.AllowList {
+		allowList = append(allowList, cidr)
+	}
+
+	result := []traefikMiddleware{
+		{name: ingressName + "-allow", needed: len(options.AllowList) > 0},
+		{name: traefikAuthName(ingressName), needed: options.BasicAuthSecret != ""},
+		{name: traefikBodyName(ingressName), needed: options.MaxBodySize > 0},
+	}
+	if result[0].needed {
+		result[0].spec = map[string]interface{}{
+			"ipWhiteList": map[string]interface{}{"sourceRange": allowList},
+		}
+	}
+	if result[1].needed {
+		result[1].spec = map[string]interface{}{
+			"basicAuth": map[string]interface{}{"secret": traefikAuthName(ingressName)},
+		}
+	}
+	if result[2].needed {
+		result[2].spec = map[string]interface{}{
+			"buffering": map[string]interface{}{"maxRequestBodyBytes": options.MaxBodySize},
+		}
+	}
+
+	return result
+}
+
+func traefikAuthName(ingressName string) string {
+	return ingressName + "-auth"
+}
+
+func traefikBodyName(ingressName string) string {
+	return ingressName + "-body"
+}
+
+func traefikRedirectName(ingressName string) string {
+	return ingressName + "-redirect"
+}
+
+// syncTraefikAuthSecret creates, updates, or removes the secret of the basicAuth
+// middleware of the route, holding the htpasswd file of the route's basic-auth secret.
+func syncTraefikAuthSecret(ctx context.Context, cluster *kubernetes.Cluster, ingress *networkingv1.Ingress, route routes.Route) error {
+	secrets := cluster.Kubectl.CoreV1().Secrets(ingress.Namespace)
+
+	if route.Options.BasicAuthSecret == "" {
+		err := secrets.Delete(ctx, traefikAuthName(ingress.Name), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	users, err := basicAuthUsers(ctx, cluster, ingress, route)
+	if err != nil {
+		return err
+	}
+
+	secret := newTraefikAuthSecret(ingress, users)
+	_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if err == nil || !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	current, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	secret.ResourceVersion = current.ResourceVersion
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// newTraefikAuthSecret returns the secret of the basicAuth middleware of the ingress,
+// for the htpasswd file.
+func newTraefikAuthSecret(ingress *networkingv1.Ingress, users []byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            traefikAuthName(ingress.Name),
+			Namespace:       ingress.Namespace,
+			Labels:          ingress.Labels,
+			OwnerReferences: []metav1.OwnerReference{ownerReference(ingress)},
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			traefikUsersKey: users,
+		},
+	}
+}
+
+// newTraefikRedirectMiddleware returns the middleware redirecting requests to https.
+func newTraefikRedirectMiddleware(ingress *networkingv1.Ingress) *unstructured.Unstructured {
+	return newObject(ingress, "traefik.containo.us/v1alpha1", "Middleware", traefikRedirectName(ingress.Name),
+		map[string]interface{}{
+			"redirectScheme": map[string]interface{}{
+				"scheme":    "https",
+				"permanent": true,
+			},
+		})
+}
+
+// newTraefikRedirectRoute returns the ingress route serving the route on the
+// plain http entrypoint, through the redirect middleware.
+func newTraefikRedirectRoute(ingress *networkingv1.Ingress, route routes.Route) *unstructured.Unstructured {
+	serviceName, servicePort := backend(ingress)
+
+	return newObject(ingress, "traefik.containo.us/v1alpha1", "IngressRoute", traefikRedirectName(ingress.Name),
+		map[string]interface{}{
+			"entryPoints": []interface{}{"web"},
+			"routes": []interface{}{
+				map[string]interface{}{
+					"kind":  "Rule",
+					"match": fmt.Sprintf("Host(`%s`) && PathPrefix(`%s`)", route.Domain, route.Path),
+					"middlewares": []interface{}{
+						map[string]interface{}{"name": traefikRedirectName(ingress.Name)},
+					},
+					"services": []interface{}{
+						map[string]interface{}{"name": serviceName, "port": servicePort},
+					},
+				},
+			},
+		})
+}
@@ -0,0 +1,157 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Changes of a difference
+const (
+	DiffAdded   = "+" // Declared by the manifest, missing from the deployed application
+	DiffRemoved = "-" // Present in the deployed application, not declared by the manifest
+	DiffChanged = "~" // Declared and present, with different values
+)
+
+// MaskedValue replaces the values of environment variables in differences.
+const MaskedValue = "********"
+
+// Difference is a single drift between a manifest and the deployed application. For
+// the environment the Field is the name of the variable, and the values are masked.
+type Difference struct {
+	Change   string `json:"change"`
+	Section  string `json:"section"`
+	Field    string `json:"field,omitempty"`
+	Deployed string `json:"deployed,omitempty"`
+	Manifest string `json:"manifest,omitempty"`
+}
+
+// Diff returns the differences between the manifest and the deployed application, in
+// the order instances, environment, services, routes, builder, and origin. Parts left
+// out of the manifest are not compared, as pushing the manifest leaves them as they are.
+// An empty result means that the application has not drifted from the manifest.
+func Diff(manifest models.ApplicationManifest, app models.App) []Difference {
+	desired := manifest.Configuration
+	current := app.Configuration
+	diffs := []Difference{}
+
+	if desired.Instances != nil {
+		deployed := ""
+		if current.Instances != nil {
+			deployed = strconv.Itoa(int(*current.Instances))
+		}
+		if declared := strconv.Itoa(int(*desired.Instances)); declared != deployed {
+			diffs = append(diffs, Difference{Change: DiffChanged, Section: "instances",
+				Deployed: deployed, Manifest: declared})
+		}
+	}
+
+	if len(desired.Environment) > 0 {
+		diffs = append(diffs, environmentDiff(desired.Environment, current.Environment)...)
+	}
+
+	if desired.Services != nil {
+		diffs = append(diffs, setDiff("services", desired.Services, current.Services)...)
+	}
+
+	if len(desired.Routes) > 0 {
+		diffs = append(diffs, setDiff("routes", desired.Routes, current.Routes)...)
+	}
+
+	if manifest.Origin.Kind != models.OriginContainer && manifest.Staging.Builder != app.BuilderImage {
+		diffs = append(diffs, Difference{Change: DiffChanged, Section: "builder",
+			Deployed: app.BuilderImage, Manifest: manifest.Staging.Builder})
+	}
+
+	// Local sources are compared by kind only. Their path depends on the checkout, which
+	// differs between the machines pushing the application.
+	declared, deployed := originString(manifest.Origin), originString(app.Origin)
+	if manifest.Origin.Kind == models.OriginPath && app.Origin.Kind == models.OriginPath {
+		declared, deployed = "path", "path"
+	}
+	if declared != deployed {
+		diffs = append(diffs, Difference{Change: DiffChanged, Section: "origin",
+			Deployed: deployed, Manifest: declared})
+	}
+
+	return diffs
+}
+
+// environmentDiff returns the differences of the environment, by variable name. The
+// values are masked, they may be secrets.
+func environmentDiff(desired, current models.EnvVariableMap) []Difference {
+	diffs := []Difference{}
+
+	for _, name := range union(desired.Names(), current.Names()) {
+		declared, inManifest := desired[name]
+		deployed, isDeployed := current[name]
+
+		switch {
+		case !isDeployed:
+			diffs = append(diffs, Difference{Change: DiffAdded, Section: "environment", Field: name,
+				Manifest: MaskedValue})
+		case !inManifest:
+			diffs = append(diffs, Difference{Change: DiffRemoved, Section: "environment", Field: name,
+				Deployed: MaskedValue})
+		case declared != deployed:
+			diffs = append(diffs, Difference{Change: DiffChanged, Section: "environment", Field: name,
+				Deployed: MaskedValue, Manifest: MaskedValue})
+		}
+	}
+
+	return diffs
+}
+
+// setDiff returns the differences of a section whose order does not matter, like the
+// bound services, or the routes.
+func setDiff(section string, desired, current []string) []Difference {
+	declared := map[string]bool{}
+	for _, value := range desired {
+		declared[value] = true
+	}
+	deployed := map[string]bool{}
+	for _, value := range current {
+		deployed[value] = true
+	}
+
+	diffs := []Difference{}
+	for _, value := range union(desired, current) {
+		switch {
+		case !deployed[value]:
+			diffs = append(diffs, Difference{Change: DiffAdded, Section: section, Manifest: value})
+		case !declared[value]:
+			diffs = append(diffs, Difference{Change: DiffRemoved, Section: section, Deployed: value})
+		}
+	}
+
+	return diffs
+}
+
+// union returns the sorted union of the strings of the slices, without duplicates.
+func union(a, b []string) []string {
+	result := uniqueStrings(append(append([]string{}, a...), b...))
+	sort.Strings(result)
+	return result
+}
+
+// originString returns a readable description of the origin, for comparison and
+// display.
+func originString(origin models.ApplicationOrigin) string {
+	switch origin.Kind {
+	case models.OriginPath:
+		return "path " + origin.Path
+	case models.OriginContainer:
+		return "container " + origin.Container
+	case models.OriginGit:
+		if origin.Git == nil {
+			return "git"
+		}
+		if origin.Git.Revision == "" {
+			return fmt.Sprintf("git %s", origin.Git.URL)
+		}
+		return fmt.Sprintf("git %s @ %s", origin.Git.URL, origin.Git.Revision)
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package manifest_test
+
+import (
+	"github.com/epinio/epinio/internal/manifest"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	one := int32(1)
+	two := int32(2)
+
+	var m models.ApplicationManifest
+	var app models.App
+
+	BeforeEach(func() {
+		m = models.ApplicationManifest{}
+		m.Name = "web"
+		m.Configuration = models.ApplicationUpdateRequest{
+			Instances:   &one,
+			Services:    []string{"db"},
+			Environment: models.EnvVariableMap{"PASSWORD": "secret"},
+			Routes:      []string{"web.example.com"},
+		}
+		m.Staging.Builder = manifest.DefaultBuilder
+		m.Origin = models.ApplicationOrigin{Kind: models.OriginGit,
+			Git: &models.GitRef{URL: "https://github.com/epinio/web", Revision: "main"}}
+
+		app = models.App{
+			Meta: models.NewAppRef("web", "workspace"),
+			Configuration: models.ApplicationUpdateRequest{
+				Instances:   &one,
+				Services:    []string{"db"},
+				Environment: models.EnvVariableMap{"PASSWORD": "secret"},
+				Routes:      []string{"web.example.com"},
+			},
+			Origin: models.ApplicationOrigin{Kind: models.OriginGit,
+				Git: &models.GitRef{URL: "https://github.com/epinio/web", Revision: "main"}},
+			BuilderImage: manifest.DefaultBuilder,
+		}
+	})
+
+	It("is empty without drift", func() {
+		Expect(manifest.Diff(m, app)).To(BeEmpty())
+	})
+
+	It("ignores parts left out of the manifest", func() {
+		m.Configuration = models.ApplicationUpdateRequest{}
+		Expect(manifest.Diff(m, app)).To(BeEmpty())
+	})
+
+	It("reports the changed instances, services and routes", func() {
+		m.Configuration.Instances = &two
+		m.Configuration.Services = []string{"cache"}
+		m.Configuration.Routes = []string{"web.example.com", "www.example.com"}
+
+		Expect(manifest.Diff(m, app)).To(Equal([]manifest.Difference{
+			{Change: manifest.DiffChanged, Section: "instances", Deployed: "1", Manifest: "2"},
+			{Change: manifest.DiffAdded, Section: "services", Manifest: "cache"},
+			{Change: manifest.DiffRemoved, Section: "services", Deployed: "db"},
+			{Change: manifest.DiffAdded, Section: "routes", Manifest: "www.example.com"},
+		}))
+	})
+
+	It("masks the values of the environment", func() {
+		m.Configuration.Environment = models.EnvVariableMap{"PASSWORD": "other", "DEBUG": "1"}
+		app.Configuration.Environment["TOKEN"] = "abc"
+
+		diffs := manifest.Diff(m, app)
+		Expect(diffs).To(Equal([]manifest.Difference{
+			{Change: manifest.DiffAdded, Section: "environment", Field: "DEBUG",
+				Manifest: manifest.MaskedValue},
+			{Change: manifest.DiffChanged, Section: "environment", Field: "PASSWORD",
+				Deployed: manifest.MaskedValue, Manifest: manifest.MaskedValue},
+			{Change: manifest.DiffRemoved, Section: "environment", Field: "TOKEN",
+				Deployed: manifest.MaskedValue},
+		}))
+	})
+
+	It("reports the changed builder and origin", func() {
+		m.Staging.Builder = "paketobuildpacks/builder:tiny"
+		m.Origin.Git.Revision = "v2"
+
+		Expect(manifest.Diff(m, app)).To(Equal([]manifest.Difference{
+			{Change: manifest.DiffChanged, Section: "builder",
+				Deployed: manifest.DefaultBuilder, Manifest: "paketobuildpacks/builder:tiny"},
+			{Change: manifest.DiffChanged, Section: "origin",
+				Deployed: "git https://github.com/epinio/web @ main",
+				Manifest: "git https://github.com/epinio/web @ v2"},
+		}))
+	})
+
+	It("does not compare the paths of local sources", func() {
+		m.Origin = models.ApplicationOrigin{Kind: models.OriginPath, Path: "/builds/ci/checkout/web"}
+		app.Origin = models.ApplicationOrigin{Kind: models.OriginPath, Path: "/home/dev/src/web"}
+
+		Expect(manifest.Diff(m, app)).To(BeEmpty())
+	})
+
+	It("reports a change of the kind of origin", func() {
+		m.Origin = models.ApplicationOrigin{Kind: models.OriginPath, Path: "/home/dev/src/web"}
+
+		Expect(manifest.Diff(m, app)).To(Equal([]manifest.Difference{
+			{Change: manifest.DiffChanged, Section: "origin",
+				Deployed: "git https://github.com/epinio/web @ main",
+				Manifest: "path /home/dev/src/web"},
+		}))
+	})
+
+	It("does not compare the builder of container images", func() {
+		m.Origin = models.ApplicationOrigin{Kind: models.OriginContainer, Container: "nginx"}
+		app.Origin = models.ApplicationOrigin{Kind: models.OriginContainer, Container: "nginx"}
+		app.BuilderImage = ""
+
+		Expect(manifest.Diff(m, app)).To(BeEmpty())
+	})
+})
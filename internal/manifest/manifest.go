@@ -18,8 +18,8 @@ const (
 	DefaultBuilder = "paketobuildpacks/builder:full"
 )
 
-// UpdateRoutes updates the incoming manifest with information pulled from the --route option.
-// Option information replaces any existing information.
+// UpdateRoutes updates the incoming manifest with information pulled from the --route and
+// --internal options. Option information replaces any existing information.
 func UpdateRoutes(manifest models.ApplicationManifest, cmd *cobra.Command) (models.ApplicationManifest, error) {
 	routes, err := cmd.Flags().GetStringSlice("route")
 	if err != nil {
@@ -30,6 +30,14 @@ func UpdateRoutes(manifest models.ApplicationManifest, cmd *cobra.Command) (mode
 		manifest.Configuration.Routes = routes
 	}
 
+	if flag := cmd.Flags().Lookup("internal"); flag != nil && flag.Changed {
+		internal, err := cmd.Flags().GetBool("internal")
+		if err != nil {
+			return manifest, errors.Wrap(err, "could not read option --internal")
+		}
+		manifest.Configuration.Internal = &internal
+	}
+
 	return manifest, nil
 }
 
@@ -269,10 +277,20 @@ func Get(manifestPath string) (models.ApplicationManifest, error) {
 		return empty, errors.Wrapf(err, "bad yaml")
 	}
 
-	// Verify that origin information is one-of only.
-
 	manifest.Self = manifestPath
 
+	err = completeOrigin(&manifest, filepath.Dir(manifestPath))
+	if err != nil {
+		return empty, err
+	}
+
+	return manifest, nil
+}
+
+// completeOrigin verifies that the origin information of the manifest is one-of only,
+// and sets the origin type tag. Without origin information the sources are taken from
+// the directory. A relative path to the sources is resolved relative to the directory.
+func completeOrigin(manifest *models.ApplicationManifest, dir string) error {
 	origins := 0
 	if manifest.Origin.Path != "" {
 		manifest.Origin.Kind = models.OriginPath
@@ -290,23 +308,24 @@ func Get(manifestPath string) (models.ApplicationManifest, error) {
 	}
 
 	if origins > 1 {
-		return empty, errors.New("Cannot use `path`, `git`, and `container` keys together")
+		return errors.New("Cannot use `path`, `git`, and `container` keys together")
 	}
 
 	// Add default location (manifest directory) back, if needed
 	if origins == 0 {
-		manifest.Origin = defaultOrigin
+		manifest.Origin = models.ApplicationOrigin{
+			Kind: models.OriginPath,
+			Path: dir,
+		}
 	}
 
 	// Resolve relative path to app sources, relative to manifest file directory
 	if manifest.Origin.Kind == models.OriginPath &&
 		!filepath.IsAbs(manifest.Origin.Path) {
-		manifest.Origin.Path = filepath.Join(
-			filepath.Dir(manifestPath),
-			manifest.Origin.Path)
+		manifest.Origin.Path = filepath.Join(dir, manifest.Origin.Path)
 	}
 
-	return manifest, nil
+	return nil
 }
 
 // instances checks if the user provided an instance count. If they didn't, then we'll
@@ -0,0 +1,285 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Kinds of the resources of a stack
+const (
+	KindService     = "service"
+	KindApplication = "application"
+)
+
+// Actions of a plan step
+const (
+	ActionCreate   = "create"   // Create the resource. Applications are staged and deployed.
+	ActionUpdate   = "update"   // Change the configuration of the resource
+	ActionRestage  = "restage"  // Change the configuration, then stage and deploy the sources
+	ActionRedeploy = "redeploy" // Change the configuration, then deploy the container image
+	ActionDelete   = "delete"   // Delete the resource, it is not declared by the stack
+)
+
+// Step is a single action of a plan, on a service or application.
+type Step struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Plan is the sequence of steps bringing a namespace to the state declared by a stack.
+type Plan []Step
+
+// NewPlan returns the steps bringing the services and applications of a namespace to
+// the state declared by the stack. Resources of the stack which are up to date have no
+// step. Resources not declared by the stack are deleted only when pruning.
+//
+// The steps are ordered for execution: services are created and updated before the
+// applications, which may bind them. Deletions come last, applications before the
+// services they may have bound.
+func NewPlan(stack models.StackManifest, services models.ServiceResponseList, apps models.AppList, prune bool) Plan {
+	plan := Plan{}
+
+	currentServices := map[string]models.ServiceResponse{}
+	for _, service := range services {
+		currentServices[service.Meta.Name] = service
+	}
+	currentApps := map[string]models.App{}
+	for _, app := range apps {
+		currentApps[app.Meta.Name] = app
+	}
+
+	for _, service := range stack.Services {
+		current, ok := currentServices[service.Name]
+		if !ok {
+			plan = append(plan, Step{Kind: KindService, Name: service.Name, Action: ActionCreate})
+			continue
+		}
+		if changes := serviceChanges(service, current.Configuration); len(changes) > 0 {
+			plan = append(plan, Step{Kind: KindService, Name: service.Name, Action: ActionUpdate,
+				Reason: changed(changes)})
+		}
+	}
+
+	for _, app := range stack.Applications {
+		current, ok := currentApps[app.Name]
+		if !ok {
+			plan = append(plan, Step{Kind: KindApplication, Name: app.Name, Action: ActionCreate})
+			continue
+		}
+		if step, ok := appStep(app, current); ok {
+			plan = append(plan, step)
+		}
+	}
+
+	if !prune {
+		return plan
+	}
+
+	declared := map[string]bool{}
+	for _, app := range stack.Applications {
+		declared[app.Name] = true
+	}
+	undeclared := []string{}
+	for _, app := range apps {
+		if !declared[app.Meta.Name] {
+			undeclared = append(undeclared, app.Meta.Name)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		plan = append(plan, Step{Kind: KindApplication, Name: name, Action: ActionDelete,
+			Reason: "not declared"})
+	}
+
+	declared = map[string]bool{}
+	for _, service := range stack.Services {
+		declared[service.Name] = true
+	}
+	undeclared = []string{}
+	for _, service := range services {
+		if !declared[service.Meta.Name] {
+			undeclared = append(undeclared, service.Meta.Name)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		plan = append(plan, Step{Kind: KindService, Name: name, Action: ActionDelete,
+			Reason: "not declared"})
+	}
+
+	return plan
+}
+
+// appStep returns the step for an existing application, if it is not up to date.
+// Sources of a path origin are always restaged, as they cannot be compared to the
+// deployed ones. Sources of a git origin are restaged when the repository or revision
+// changed, or when no revision is given, as the branch may have moved.
+func appStep(app models.ApplicationManifest, current models.App) (Step, bool) {
+	step := Step{Kind: KindApplication, Name: app.Name}
+	changes := appChanges(app.Configuration, current.Configuration)
+
+	active := current.Workload != nil && current.Workload.ImageURL != ""
+
+	switch app.Origin.Kind {
+	case models.OriginContainer:
+		switch {
+		case !active:
+			step.Action = ActionRedeploy
+			changes = append(changes, "not deployed")
+		case current.Workload.ImageURL != app.Origin.Container:
+			step.Action = ActionRedeploy
+			changes = append(changes, "image")
+		}
+	case models.OriginGit:
+		switch {
+		case !active:
+			step.Action = ActionRestage
+			changes = append(changes, "not deployed")
+		case current.Origin.Git == nil ||
+			current.Origin.Git.URL != app.Origin.Git.URL ||
+			current.Origin.Git.Revision != app.Origin.Git.Revision:
+			step.Action = ActionRestage
+			changes = append(changes, "git origin")
+		case app.Origin.Git.Revision == "":
+			step.Action = ActionRestage
+			changes = append(changes, "unpinned git revision")
+		}
+	default:
+		step.Action = ActionRestage
+		changes = append(changes, "local sources")
+	}
+
+	if step.Action == "" {
+		if len(changes) == 0 {
+			return step, false
+		}
+		step.Action = ActionUpdate
+	}
+
+	step.Reason = changed(changes)
+	return step, true
+}
+
+// appChanges returns the names of the parts of the configuration which differ from the
+// current one. Parts left out of the desired configuration are not compared, as the
+// update of the application leaves them as they are.
+func appChanges(desired, current models.ApplicationUpdateRequest) []string {
+	changes := []string{}
+
+	if desired.Instances != nil &&
+		(current.Instances == nil || *current.Instances != *desired.Instances) {
+		changes = append(changes, "instances")
+	}
+	if desired.Services != nil && !sameStrings(desired.Services, current.Services) {
+		changes = append(changes, "services")
+	}
+	if len(desired.Environment) > 0 && !sameMap(desired.Environment, current.Environment) {
+		changes = append(changes, "environment")
+	}
+	if len(desired.Routes) > 0 && !sameStrings(desired.Routes, current.Routes) {
+		changes = append(changes, "routes")
+	}
+	if desired.Internal != nil &&
+		*desired.Internal != (current.Internal != nil && *current.Internal) {
+		changes = append(changes, "internal")
+	}
+	if desired.Volumes != nil && !sameMap(desired.Volumes, current.Volumes) {
+		changes = append(changes, "volumes")
+	}
+
+	return changes
+}
+
+// serviceChanges returns the names of the parts of the service which differ from the
+// current service.
+func serviceChanges(desired models.ServiceCreateRequest, current models.ServiceShowResponse) []string {
+	changes := []string{}
+
+	if !sameMap(desired.Data, current.Details) {
+		changes = append(changes, "data")
+	}
+	if !sameMap(desired.Labels, current.Labels) {
+		changes = append(changes, "labels")
+	}
+	if desired.Description != current.Description {
+		changes = append(changes, "description")
+	}
+
+	return changes
+}
+
+// ServiceUpdate returns the request changing the current service into the desired one.
+func ServiceUpdate(desired models.ServiceCreateRequest, current models.ServiceShowResponse) models.ServiceUpdateRequest {
+	request := models.ServiceUpdateRequest{
+		Set:       map[string]string{},
+		SetLabels: map[string]string{},
+	}
+
+	for key, value := range desired.Data {
+		if currentValue, ok := current.Details[key]; !ok || currentValue != value {
+			request.Set[key] = value
+		}
+	}
+	for key := range current.Details {
+		if _, ok := desired.Data[key]; !ok {
+			request.Remove = append(request.Remove, key)
+		}
+	}
+	sort.Strings(request.Remove)
+
+	for key, value := range desired.Labels {
+		if currentValue, ok := current.Labels[key]; !ok || currentValue != value {
+			request.SetLabels[key] = value
+		}
+	}
+	for key := range current.Labels {
+		if _, ok := desired.Labels[key]; !ok {
+			request.RemoveLabels = append(request.RemoveLabels, key)
+		}
+	}
+	sort.Strings(request.RemoveLabels)
+
+	if desired.Description != current.Description {
+		description := desired.Description
+		request.Description = &description
+	}
+
+	return request
+}
+
+// changed returns the description of the changes for a step.
+func changed(changes []string) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("changed: %s", strings.Join(changes, ", "))
+}
+
+// sameStrings returns true if the slices contain the same strings, in any order.
+func sameStrings(a, b []string) bool {
+	a = uniqueStrings(a)
+	b = uniqueStrings(b)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
+
+// sameMap returns true if the maps have the same entries. Nil and empty maps are the
+// same.
+func sameMap(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
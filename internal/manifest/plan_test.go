@@ -0,0 +1,155 @@
+package manifest_test
+
+import (
+	"github.com/epinio/epinio/internal/manifest"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plan", func() {
+	two := int32(2)
+	three := int32(3)
+
+	container := func(name, image string, instances *int32) models.ApplicationManifest {
+		app := models.ApplicationManifest{}
+		app.Name = name
+		app.Configuration.Instances = instances
+		app.Origin = models.ApplicationOrigin{Kind: models.OriginContainer, Container: image}
+		return app
+	}
+
+	deployed := func(name, image string, instances *int32) models.App {
+		return models.App{
+			Meta:          models.NewAppRef(name, "workspace"),
+			Configuration: models.ApplicationUpdateRequest{Instances: instances},
+			Workload:      &models.AppDeployment{ImageURL: image},
+		}
+	}
+
+	service := func(name string, data map[string]string) models.ServiceResponse {
+		return models.ServiceResponse{
+			Meta:          models.ServiceRef{Name: name, Namespace: "workspace"},
+			Configuration: models.ServiceShowResponse{Details: data},
+		}
+	}
+
+	Describe("NewPlan", func() {
+		It("has no steps for an up to date namespace", func() {
+			stack := models.StackManifest{
+				Services:     []models.ServiceCreateRequest{{Name: "db", Data: map[string]string{"user": "admin"}}},
+				Applications: []models.ApplicationManifest{container("web", "nginx", &two)},
+			}
+			plan := manifest.NewPlan(stack,
+				models.ServiceResponseList{service("db", map[string]string{"user": "admin"})},
+				models.AppList{deployed("web", "nginx", &two)},
+				true)
+			Expect(plan).To(BeEmpty())
+		})
+
+		It("orders services before applications, and deletions last", func() {
+			stack := models.StackManifest{
+				Services: []models.ServiceCreateRequest{
+					{Name: "db", Data: map[string]string{"user": "root"}},
+					{Name: "cache"},
+				},
+				Applications: []models.ApplicationManifest{
+					container("web", "nginx", &three),
+					container("proxy", "haproxy", nil),
+					container("api", "api:2", nil),
+				},
+			}
+			plan := manifest.NewPlan(stack,
+				models.ServiceResponseList{
+					service("db", map[string]string{"user": "admin"}),
+					service("queue", nil),
+				},
+				models.AppList{
+					deployed("web", "nginx", &two),
+					deployed("old", "old", nil),
+					deployed("api", "api:1", nil),
+				},
+				true)
+
+			Expect(plan).To(Equal(manifest.Plan{
+				{Kind: manifest.KindService, Name: "db", Action: manifest.ActionUpdate, Reason: "changed: data"},
+				{Kind: manifest.KindService, Name: "cache", Action: manifest.ActionCreate},
+				{Kind: manifest.KindApplication, Name: "web", Action: manifest.ActionUpdate, Reason: "changed: instances"},
+				{Kind: manifest.KindApplication, Name: "proxy", Action: manifest.ActionCreate},
+				{Kind: manifest.KindApplication, Name: "api", Action: manifest.ActionRedeploy, Reason: "changed: image"},
+				{Kind: manifest.KindApplication, Name: "old", Action: manifest.ActionDelete, Reason: "not declared"},
+				{Kind: manifest.KindService, Name: "queue", Action: manifest.ActionDelete, Reason: "not declared"},
+			}))
+		})
+
+		It("keeps undeclared resources without pruning", func() {
+			stack := models.StackManifest{
+				Applications: []models.ApplicationManifest{container("web", "nginx", nil)},
+			}
+			plan := manifest.NewPlan(stack,
+				models.ServiceResponseList{service("db", nil)},
+				models.AppList{deployed("web", "nginx", nil), deployed("old", "old", nil)},
+				false)
+			Expect(plan).To(BeEmpty())
+		})
+
+		It("restages local sources, and moved git origins", func() {
+			local := models.ApplicationManifest{}
+			local.Name = "local"
+			local.Origin = models.ApplicationOrigin{Kind: models.OriginPath, Path: "/src"}
+
+			pinned := models.ApplicationManifest{}
+			pinned.Name = "pinned"
+			pinned.Origin = models.ApplicationOrigin{Kind: models.OriginGit,
+				Git: &models.GitRef{URL: "https://git/pinned", Revision: "v1"}}
+
+			moved := models.ApplicationManifest{}
+			moved.Name = "moved"
+			moved.Origin = models.ApplicationOrigin{Kind: models.OriginGit,
+				Git: &models.GitRef{URL: "https://git/moved", Revision: "v2"}}
+
+			current := func(name, revision string) models.App {
+				app := deployed(name, "registry/"+name, nil)
+				app.Origin = models.ApplicationOrigin{Kind: models.OriginGit,
+					Git: &models.GitRef{URL: "https://git/" + name, Revision: revision}}
+				return app
+			}
+
+			plan := manifest.NewPlan(
+				models.StackManifest{Applications: []models.ApplicationManifest{local, pinned, moved}},
+				models.ServiceResponseList{},
+				models.AppList{deployed("local", "registry/local", nil), current("pinned", "v1"), current("moved", "v1")},
+				false)
+
+			Expect(plan).To(Equal(manifest.Plan{
+				{Kind: manifest.KindApplication, Name: "local", Action: manifest.ActionRestage, Reason: "changed: local sources"},
+				{Kind: manifest.KindApplication, Name: "moved", Action: manifest.ActionRestage, Reason: "changed: git origin"},
+			}))
+		})
+	})
+
+	Describe("ServiceUpdate", func() {
+		It("changes the current service into the desired one", func() {
+			request := manifest.ServiceUpdate(
+				models.ServiceCreateRequest{
+					Name:        "db",
+					Data:        map[string]string{"user": "root", "host": "db"},
+					Labels:      map[string]string{"tier": "backend"},
+					Description: "database",
+				},
+				models.ServiceShowResponse{
+					Details: map[string]string{"user": "admin", "host": "db", "port": "5432"},
+					Labels:  map[string]string{"team": "a"},
+				})
+
+			description := "database"
+			Expect(request).To(Equal(models.ServiceUpdateRequest{
+				Remove:       []string{"port"},
+				Set:          map[string]string{"user": "root"},
+				RemoveLabels: []string{"team"},
+				SetLabels:    map[string]string{"tier": "backend"},
+				Description:  &description,
+			}))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// GetStack reads the stack file at the specified path into memory. Contrary to Get a
+// missing file is an error. Each application is completed like the manifest of Get,
+// i.e. with the default builder, and with the directory of the stack file as the
+// origin of its sources, if it has no origin.
+func GetStack(stackPath string) (models.StackManifest, error) {
+
+	// Empty stack, for errors
+	empty := models.StackManifest{}
+
+	stackPath, err := filepath.Abs(stackPath)
+	if err != nil {
+		return empty, errors.Wrapf(err, "filesystem error")
+	}
+
+	yamlFile, err := ioutil.ReadFile(stackPath)
+	if err != nil {
+		return empty, errors.Wrapf(err, "filesystem error")
+	}
+
+	stack := models.StackManifest{}
+	err = yaml.Unmarshal(yamlFile, &stack)
+	if err != nil {
+		return empty, errors.Wrapf(err, "bad yaml")
+	}
+
+	stack.Self = stackPath
+
+	if len(stack.Services) == 0 && len(stack.Applications) == 0 {
+		return empty, errors.New("The stack declares neither services nor applications")
+	}
+
+	services := map[string]bool{}
+	for _, service := range stack.Services {
+		if service.Name == "" {
+			return empty, errors.New("Service without name")
+		}
+		if services[service.Name] {
+			return empty, errors.Errorf("Service `%s` is declared more than once", service.Name)
+		}
+		services[service.Name] = true
+	}
+
+	apps := map[string]bool{}
+	for i := range stack.Applications {
+		app := &stack.Applications[i]
+
+		if app.Name == "" {
+			return empty, errors.New("Application without name")
+		}
+		if apps[app.Name] {
+			return empty, errors.Errorf("Application `%s` is declared more than once", app.Name)
+		}
+		apps[app.Name] = true
+
+		app.Self = stackPath
+		if app.Staging.Builder == "" {
+			app.Staging.Builder = DefaultBuilder
+		}
+
+		err = completeOrigin(app, filepath.Dir(stackPath))
+		if err != nil {
+			return empty, errors.Wrapf(err, "application `%s`", app.Name)
+		}
+	}
+
+	return stack, nil
+}
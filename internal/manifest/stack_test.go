@@ -0,0 +1,114 @@
+package manifest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/epinio/epinio/internal/manifest"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stack", func() {
+	var workdir string
+
+	BeforeEach(func() {
+		var err error
+		workdir, err = os.Getwd()
+		Expect(err).ToNot(HaveOccurred(), workdir)
+	})
+
+	Describe("GetStack", func() {
+		write := func(content string) {
+			err := ioutil.WriteFile("stack.yml", []byte(content), 0600)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		AfterEach(func() {
+			_ = os.Remove("stack.yml")
+		})
+
+		When("the stack file is missing", func() {
+			It("fails with an error", func() {
+				_, err := manifest.GetStack("stack.yml")
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+
+		When("the stack file declares nothing", func() {
+			It("fails with an error", func() {
+				write("services: []\n")
+				_, err := manifest.GetStack("stack.yml")
+				Expect(err).To(MatchError("The stack declares neither services nor applications"))
+			})
+		})
+
+		When("the stack file declares a name twice", func() {
+			It("fails with an error", func() {
+				write("applications:\n- name: web\n- name: web\n")
+				_, err := manifest.GetStack("stack.yml")
+				Expect(err).To(MatchError("Application `web` is declared more than once"))
+			})
+		})
+
+		When("an application has several origins", func() {
+			It("fails with an error", func() {
+				write("applications:\n- name: web\n  origin:\n    path: web\n    container: nginx\n")
+				_, err := manifest.GetStack("stack.yml")
+				Expect(err).To(MatchError("application `web`: Cannot use `path`, `git`, and `container` keys together"))
+			})
+		})
+
+		When("the stack file is good", func() {
+			It("completes the applications", func() {
+				write(`services:
+- name: db
+  data:
+    user: admin
+applications:
+- name: web
+  origin:
+    path: web
+  configuration:
+    services:
+    - db
+- name: proxy
+  origin:
+    container: nginx
+  staging:
+    builder: snafu
+- name: worker
+`)
+
+				stack, err := manifest.GetStack("stack.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stack.Self).To(Equal(path.Join(workdir, "stack.yml")))
+				Expect(stack.Services).To(Equal([]models.ServiceCreateRequest{
+					{Name: "db", Data: map[string]string{"user": "admin"}},
+				}))
+				Expect(stack.Applications).To(HaveLen(3))
+
+				web := stack.Applications[0]
+				Expect(web.Self).To(Equal(path.Join(workdir, "stack.yml")))
+				Expect(web.Configuration.Services).To(Equal([]string{"db"}))
+				Expect(web.Origin).To(Equal(models.ApplicationOrigin{
+					Kind: models.OriginPath,
+					Path: path.Join(workdir, "web"),
+				}))
+				Expect(web.Staging.Builder).To(Equal(manifest.DefaultBuilder))
+
+				proxy := stack.Applications[1]
+				Expect(proxy.Origin.Kind).To(Equal(models.OriginContainer))
+				Expect(proxy.Staging.Builder).To(Equal("snafu"))
+
+				worker := stack.Applications[2]
+				Expect(worker.Origin).To(Equal(models.ApplicationOrigin{
+					Kind: models.OriginPath,
+					Path: workdir,
+				}))
+			})
+		})
+	})
+})
@@ -17,6 +17,24 @@ func ServiceName(base string) string {
 	return GenerateResourceName("s-" + base)
 }
 
+// GRPCServiceName returns the name of the kube service derived from the
+// base string, for the gRPC routes of an application. See ServiceName.
+func GRPCServiceName(base string) string {
+	return GenerateResourceName("g-" + base)
+}
+
+// TCPServiceName returns the name of the kube service derived from the
+// base string, for a TCP route of an application. See ServiceName.
+func TCPServiceName(base string) string {
+	return GenerateResourceName("t-" + base)
+}
+
+// VolumeName returns the name of the kube persistent volume claim derived
+// from the base string, for a volume of a namespace. See ServiceName.
+func VolumeName(base string) string {
+	return GenerateResourceName("v-" + base)
+}
+
 // IngressName returns the name of a kube ingress derived from the
 // base string. It ensures that things like leading digits are
 // sufficiently hidden to prevent kube from erroring out on the name.
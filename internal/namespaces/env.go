@@ -0,0 +1,128 @@
+package namespaces
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// EnvSecretName is the name of the secret holding the default environment
+// variables of an epinio-controlled namespace. These variables are provided to
+// all applications in the namespace which do not set them themselves.
+const EnvSecretName = "epinio-namespace-env"
+
+// EnvironmentNames returns the names of all default environment variables of the
+// namespace. It does not return values.
+func EnvironmentNames(ctx context.Context, cluster *kubernetes.Cluster, namespace string) ([]string, error) {
+	evSecret, err := envLoad(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for name := range evSecret.Data {
+		result = append(result, name)
+	}
+
+	return result, nil
+}
+
+// Environment returns the default environment variables of the namespace, and
+// their values
+func Environment(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (models.EnvVariableMap, error) {
+	evSecret, err := envLoad(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.EnvVariableMap{}
+	for name, value := range evSecret.Data {
+		result[name] = string(value)
+	}
+
+	return result, nil
+}
+
+// EnvironmentSet adds or modifies the specified default environment
+// variables of the namespace. Restarting the affected workloads is the
+// responsibility of the caller.
+func EnvironmentSet(ctx context.Context, cluster *kubernetes.Cluster, namespace string, assignments models.EnvVariableMap) error {
+	return envUpdate(ctx, cluster, namespace, func(evSecret *v1.Secret) {
+		for name, value := range assignments {
+			evSecret.Data[name] = []byte(value)
+		}
+	})
+}
+
+// EnvironmentUnset removes the specified default environment variable
+// from the namespace. Restarting the affected workloads is the
+// responsibility of the caller.
+func EnvironmentUnset(ctx context.Context, cluster *kubernetes.Cluster, namespace, varName string) error {
+	return envUpdate(ctx, cluster, namespace, func(evSecret *v1.Secret) {
+		delete(evSecret.Data, varName)
+	})
+}
+
+// envUpdate is the helper for the public functions encapsulating the
+// read/modify/write cycle necessary to update the namespace's kube resource
+// holding the default environment.
+func envUpdate(ctx context.Context, cluster *kubernetes.Cluster,
+	namespace string, modifyEnvironment func(*v1.Secret)) error {
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		evSecret, err := envLoad(ctx, cluster, namespace)
+		if err != nil {
+			return err
+		}
+
+		if evSecret.Data == nil {
+			evSecret.Data = make(map[string][]byte)
+		}
+
+		modifyEnvironment(evSecret)
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(namespace).Update(
+			ctx, evSecret, metav1.UpdateOptions{})
+
+		return err
+	})
+}
+
+// envLoad locates and returns the kube secret storing the namespace's default
+// environment. If necessary it creates that secret. No owner is needed, the
+// secret is removed together with the namespace.
+func envLoad(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (*v1.Secret, error) {
+	evSecret, err := cluster.GetSecret(ctx, namespace, EnvSecretName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		// Error is `Not Found`. Create the secret.
+
+		evSecret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      EnvSecretName,
+				Namespace: namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name":       EnvSecretName,
+					"app.kubernetes.io/part-of":    namespace,
+					"app.kubernetes.io/managed-by": "epinio",
+					"app.kubernetes.io/component":  "namespace",
+				},
+			},
+		}
+		err = cluster.CreateSecret(ctx, namespace, *evSecret)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return evSecret, nil
+}
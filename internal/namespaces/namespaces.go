@@ -8,7 +8,10 @@ import (
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/duration"
+	"github.com/epinio/epinio/internal/network"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -54,7 +57,8 @@ func Exists(ctx context.Context, kubeClient *kubernetes.Cluster, lookupNamespace
 }
 
 // Create generates a new epinio-controlled namespace, i.e. a kube
-// namespace plus a service account.
+// namespace plus a service account. When the server is configured for it,
+// the network of the namespace is isolated from the other namespaces.
 func Create(ctx context.Context, kubeClient *kubernetes.Cluster, namespace string) error {
 	if _, err := kubeClient.Kubectl.CoreV1().Namespaces().Create(
 		ctx,
@@ -86,6 +90,13 @@ func Create(ctx context.Context, kubeClient *kubernetes.Cluster, namespace strin
 		return errors.Wrap(err, "timed out while waiting for registry-creds secret to be copied to the new namespace")
 	}
 
+	if viper.GetBool("namespace-isolation") {
+		err := network.Set(ctx, kubeClient, namespace, models.NamespaceNetwork{Isolated: true})
+		if err != nil {
+			return errors.Wrap(err, "failed to isolate the namespace network")
+		}
+	}
+
 	return nil
 }
 
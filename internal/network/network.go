@@ -0,0 +1,357 @@
+// Package network encapsulates the network isolation of epinio-controlled namespaces.
+// An isolated namespace has a kube NetworkPolicy selecting all its pods. It
+// admits traffic from within the namespace, from the ingress controller (and
+// the gateway of the Gateway API provider), and from the namespaces it
+// explicitly allows. The pods behind load balancer and node port services, i.e.
+// the TCP routes of applications, have an additional policy admitting external
+// clients on the ports of these services. Egress is limited to the namespace
+// itself, to namespaces not controlled by epinio (DNS, registry, and the
+// in-cluster backends of bound services), and to the namespaces allowing traffic
+// from it. When the server knows the pod and service networks of the cluster,
+// egress to all other addresses (external services) is allowed as well.
+// The policy is owned by the kube namespace, and is removed together with it.
+package network
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	ingressprovider "github.com/epinio/epinio/internal/ingress"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// PolicyName is the name of the NetworkPolicy isolating a namespace
+	PolicyName = "epinio-isolation"
+
+	// allowAnnotation is the annotation of the policy holding the
+	// comma-separated list of namespaces allowed to reach the namespace.
+	allowAnnotation = "epinio.suse.org/network-allow"
+
+	// namespaceNameLabel is set by kube on all namespaces
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+)
+
+// Get returns the network isolation of the namespace.
+func Get(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (models.NamespaceNetwork, error) {
+	result := models.NamespaceNetwork{}
+
+	policy, err := cluster.Kubectl.NetworkingV1().NetworkPolicies(namespace).Get(ctx, PolicyName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return result, nil
+		}
+		return result, err
+	}
+
+	result.Isolated = true
+	result.AllowFrom = allowedFrom(policy)
+
+	return result, nil
+}
+
+// Set replaces the network isolation of the namespace. The policies of the
+// namespaces whose egress depends on the change are updated as well.
+func Set(ctx context.Context, cluster *kubernetes.Cluster, namespace string, network models.NamespaceNetwork) error {
+	if err := Validate(network); err != nil {
+		return err
+	}
+
+	previous, err := Get(ctx, cluster, namespace)
+	if err != nil {
+		return err
+	}
+
+	if !network.Isolated {
+		err := cluster.Kubectl.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, PolicyName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		err = syncServicePolicies(ctx, cluster, namespace, false)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := apply(ctx, cluster, namespace, network.AllowFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Egress towards this namespace changed for the namespaces which are, or
+	// were, allowed to reach it.
+	affected := map[string]bool{}
+	for _, other := range append(previous.AllowFrom, network.AllowFrom...) {
+		affected[other] = true
+	}
+	for other := range affected {
+		if other == namespace {
+			continue
+		}
+		otherNetwork, err := Get(ctx, cluster, other)
+		if err != nil {
+			return err
+		}
+		if !otherNetwork.Isolated {
+			continue
+		}
+		err = apply(ctx, cluster, other, otherNetwork.AllowFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the network isolation of the namespace, if any.
+func Delete(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	return Set(ctx, cluster, namespace, models.NamespaceNetwork{})
+}
+
+// Validate checks that the allowed namespaces are proper names, and that they
+// are only specified for an isolated namespace.
+func Validate(network models.NamespaceNetwork) error {
+	if !network.Isolated && len(network.AllowFrom) > 0 {
+		return errors.New("allowed namespaces require an isolated namespace")
+	}
+
+	for _, name := range network.AllowFrom {
+		if msgs := validation.IsDNS1123Label(name); len(msgs) > 0 {
+			return errors.Errorf("bad namespace name '%s': %s", name, strings.Join(msgs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// apply creates or updates the policy of the isolated namespace.
+func apply(ctx context.Context, cluster *kubernetes.Cluster, namespace string, allowFrom []string) error {
+	kubeNamespace, err := cluster.Kubectl.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	owner := metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Namespace",
+		Name:       kubeNamespace.Name,
+		UID:        kubeNamespace.UID,
+	}
+
+	allowTo, err := allowingNamespaces(ctx, cluster, namespace)
+	if err != nil {
+		return err
+	}
+
+	clusterNetworks, err := ParseCIDRs(viper.GetStringSlice("cluster-cidrs"))
+	if err != nil {
+		return err
+	}
+
+	controllers := controllerNamespaces(ingressprovider.Current().Name(),
+		viper.GetString("ingress-namespace"), viper.GetString("gateway-namespace"))
+	policy := newPolicy(namespace, owner, allowFrom, allowTo, controllers, clusterNetworks)
+
+	client := cluster.Kubectl.NetworkingV1().NetworkPolicies(namespace)
+	current, err := client.Get(ctx, PolicyName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if _, err := client.Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create network policy")
+		}
+		return syncServicePolicies(ctx, cluster, namespace, true)
+	}
+
+	policy.ResourceVersion = current.ResourceVersion
+	if _, err := client.Update(ctx, policy, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update network policy")
+	}
+	return syncServicePolicies(ctx, cluster, namespace, true)
+}
+
+// controllerNamespaces returns the namespaces of the controllers sending the
+// requests of the routes to the applications. The gateway of the Gateway API
+// provider forwards the requests itself. Without a namespace it is in the
+// namespace of the application, which is admitted anyway.
+func controllerNamespaces(provider, ingressNamespace, gatewayNamespace string) []string {
+	result := []string{}
+	if ingressNamespace != "" {
+		result = append(result, ingressNamespace)
+	}
+	if provider == ingressprovider.GatewayName && gatewayNamespace != "" && gatewayNamespace != ingressNamespace {
+		result = append(result, gatewayNamespace)
+	}
+	return result
+}
+
+// allowingNamespaces returns the isolated namespaces allowing traffic from the
+// namespace.
+func allowingNamespaces(ctx context.Context, cluster *kubernetes.Cluster, namespace string) ([]string, error) {
+	policies, err := cluster.Kubectl.NetworkingV1().NetworkPolicies("").List(ctx, metav1.ListOptions{
+		FieldSelector: "metadata.name=" + PolicyName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, policy := range policies.Items {
+		for _, name := range allowedFrom(&policy) {
+			if name == namespace {
+				result = append(result, policy.Namespace)
+			}
+		}
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// allowedFrom returns the namespaces the policy allows traffic from.
+func allowedFrom(policy *networkingv1.NetworkPolicy) []string {
+	value := policy.Annotations[allowAnnotation]
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// ParseCIDRs returns the networks of the CIDRs. An entry may hold several
+// comma-separated CIDRs, as given by the environment.
+func ParseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	result := []*net.IPNet{}
+	for _, entry := range cidrs {
+		for _, cidr := range strings.Split(entry, ",") {
+			cidr = strings.TrimSpace(cidr)
+			if cidr == "" {
+				continue
+			}
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "bad cluster network '%s'", cidr)
+			}
+			result = append(result, network)
+		}
+	}
+	return result, nil
+}
+
+// externalPeers returns the peers for the addresses outside of the cluster, i.e. all
+// addresses except those of the cluster networks. Only the address families of the
+// cluster networks are covered. Without cluster networks there are no such peers, as
+// ipBlock peers match the addresses of pods and services as well on many CNIs.
+func externalPeers(clusterNetworks []*net.IPNet) []networkingv1.NetworkPolicyPeer {
+	v4 := []string{}
+	v6 := []string{}
+	for _, network := range clusterNetworks {
+		if network.IP.To4() != nil {
+			v4 = append(v4, network.String())
+		} else {
+			v6 = append(v6, network.String())
+		}
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{}
+	if len(v4) > 0 {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: v4},
+		})
+	}
+	if len(v6) > 0 {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: "::/0", Except: v6},
+		})
+	}
+	return peers
+}
+
+// newPolicy returns the policy isolating the namespace.
+func newPolicy(namespace string, owner metav1.OwnerReference, allowFrom, allowTo, controllers []string, clusterNetworks []*net.IPNet) *networkingv1.NetworkPolicy {
+	allowFrom = append([]string{}, allowFrom...)
+	sort.Strings(allowFrom)
+
+	sameNamespace := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{},
+	}
+	namespaces := func(names ...string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      namespaceNameLabel,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   names,
+				}},
+			},
+		}
+	}
+
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{From: []networkingv1.NetworkPolicyPeer{sameNamespace}},
+	}
+	if len(controllers) > 0 {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{namespaces(controllers...)},
+		})
+	}
+	if len(allowFrom) > 0 {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{namespaces(allowFrom...)},
+		})
+	}
+
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{To: []networkingv1.NetworkPolicyPeer{sameNamespace}},
+		{To: []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      kubernetes.EpinioNamespaceLabelKey,
+					Operator: metav1.LabelSelectorOpDoesNotExist,
+				}},
+			},
+		}}},
+	}
+	if external := externalPeers(clusterNetworks); len(external) > 0 {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{To: external})
+	}
+	if len(allowTo) > 0 {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{namespaces(allowTo...)},
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            PolicyName,
+			Namespace:       namespace,
+			OwnerReferences: []metav1.OwnerReference{owner},
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "epinio",
+				"app.kubernetes.io/component":  "network",
+			},
+			Annotations: map[string]string{
+				allowAnnotation: strings.Join(allowFrom, ","),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+			Ingress: ingress,
+			Egress:  egress,
+		},
+	}
+}
@@ -0,0 +1,212 @@
+package network
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network", func() {
+	owner := metav1.OwnerReference{APIVersion: "v1", Kind: "Namespace", Name: "workspace", UID: "1234"}
+
+	namespacesIn := func(names ...string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "kubernetes.io/metadata.name",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   names,
+				}},
+			},
+		}
+	}
+
+	Describe("newPolicy", func() {
+		It("admits the namespace itself and the ingress controller only", func() {
+			policy := newPolicy("workspace", owner, nil, nil, []string{"traefik"}, nil)
+
+			Expect(policy.Name).To(Equal(PolicyName))
+			Expect(policy.Namespace).To(Equal("workspace"))
+			Expect(policy.OwnerReferences).To(Equal([]metav1.OwnerReference{owner}))
+			Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+			Expect(policy.Spec.Ingress).To(Equal([]networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+				{From: []networkingv1.NetworkPolicyPeer{namespacesIn("traefik")}},
+			}))
+			Expect(allowedFrom(policy)).To(BeEmpty())
+		})
+
+		It("admits the allowed namespaces", func() {
+			policy := newPolicy("workspace", owner, []string{"shop", "billing"}, nil, []string{"traefik"}, nil)
+
+			Expect(policy.Spec.Ingress).To(ContainElement(networkingv1.NetworkPolicyIngressRule{
+				From: []networkingv1.NetworkPolicyPeer{namespacesIn("billing", "shop")},
+			}))
+			Expect(allowedFrom(policy)).To(Equal([]string{"billing", "shop"}))
+		})
+
+		It("lets traffic out to services and the namespaces allowing it", func() {
+			policy := newPolicy("workspace", owner, nil, []string{"billing"}, []string{"traefik"}, nil)
+
+			Expect(policy.Spec.Egress).To(ContainElement(networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{
+							Key:      kubernetes.EpinioNamespaceLabelKey,
+							Operator: metav1.LabelSelectorOpDoesNotExist,
+						}},
+					},
+				}},
+			}))
+			Expect(policy.Spec.Egress).To(ContainElement(networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{namespacesIn("billing")},
+			}))
+		})
+	})
+
+	Describe("external egress", func() {
+		externalRules := func(policy *networkingv1.NetworkPolicy) []networkingv1.NetworkPolicyEgressRule {
+			rules := []networkingv1.NetworkPolicyEgressRule{}
+			for _, rule := range policy.Spec.Egress {
+				for _, peer := range rule.To {
+					if peer.IPBlock != nil {
+						rules = append(rules, rule)
+						break
+					}
+				}
+			}
+			return rules
+		}
+
+		It("is denied without the cluster networks", func() {
+			policy := newPolicy("workspace", owner, nil, nil, []string{"traefik"}, nil)
+
+			Expect(externalRules(policy)).To(BeEmpty())
+		})
+
+		It("excludes the cluster networks", func() {
+			networks, err := ParseCIDRs([]string{"10.42.0.0/16,10.43.0.0/16", "fd00:42::/56"})
+			Expect(err).ToNot(HaveOccurred())
+
+			policy := newPolicy("workspace", owner, nil, nil, []string{"traefik"}, networks)
+
+			Expect(externalRules(policy)).To(Equal([]networkingv1.NetworkPolicyEgressRule{{
+				To: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: []string{"10.42.0.0/16", "10.43.0.0/16"}}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "::/0", Except: []string{"fd00:42::/56"}}},
+				},
+			}}))
+		})
+
+		It("covers only the address families of the cluster networks", func() {
+			networks, err := ParseCIDRs([]string{"10.42.0.0/16"})
+			Expect(err).ToNot(HaveOccurred())
+
+			policy := newPolicy("workspace", owner, nil, nil, []string{"traefik"}, networks)
+
+			Expect(externalRules(policy)).To(Equal([]networkingv1.NetworkPolicyEgressRule{{
+				To: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: []string{"10.42.0.0/16"}}},
+				},
+			}}))
+		})
+
+		It("rejects bad cluster networks", func() {
+			_, err := ParseCIDRs([]string{"10.42.0.0"})
+			Expect(err).To(MatchError(ContainSubstring("bad cluster network '10.42.0.0'")))
+		})
+	})
+
+	Describe("controllerNamespaces", func() {
+		It("admits the ingress controller", func() {
+			Expect(controllerNamespaces("traefik", "traefik", "gateways")).To(Equal([]string{"traefik"}))
+		})
+
+		It("admits the gateway of the gateway provider", func() {
+			Expect(controllerNamespaces("gateway", "traefik", "gateways")).To(Equal([]string{"traefik", "gateways"}))
+
+			policy := newPolicy("workspace", owner, nil, nil, controllerNamespaces("gateway", "traefik", "gateways"), nil)
+			Expect(policy.Spec.Ingress).To(ContainElement(networkingv1.NetworkPolicyIngressRule{
+				From: []networkingv1.NetworkPolicyPeer{namespacesIn("traefik", "gateways")},
+			}))
+		})
+
+		It("leaves out a gateway in the namespace of the application", func() {
+			Expect(controllerNamespaces("gateway", "traefik", "")).To(Equal([]string{"traefik"}))
+		})
+	})
+
+	Describe("newServicePolicy", func() {
+		service := func(serviceType v1.ServiceType) v1.Service {
+			return v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "tmyapp-1883", Namespace: "workspace", UID: "5678"},
+				Spec: v1.ServiceSpec{
+					Type: serviceType,
+					Ports: []v1.ServicePort{{
+						Port:       1883,
+						Protocol:   v1.ProtocolTCP,
+						TargetPort: intstr.FromInt(8883),
+					}},
+					Selector: map[string]string{
+						"app.kubernetes.io/component": "application",
+						"app.kubernetes.io/name":      "myapp",
+					},
+					LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+				},
+			}
+		}
+
+		It("admits all clients to the target ports of the pods of a tcp route", func() {
+			tcp := v1.ProtocolTCP
+			target := intstr.FromInt(8883)
+
+			policy := newServicePolicy(service(v1.ServiceTypeLoadBalancer))
+			Expect(policy.Name).To(Equal("tmyapp-1883"))
+			Expect(policy.OwnerReferences).To(Equal([]metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "Service", Name: "tmyapp-1883", UID: "5678"},
+			}))
+			Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{
+				"app.kubernetes.io/component": "application",
+				"app.kubernetes.io/name":      "myapp",
+			}))
+			Expect(policy.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}))
+			Expect(policy.Spec.Ingress).To(Equal([]networkingv1.NetworkPolicyIngressRule{{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &target}},
+				From: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "::/0"}},
+				},
+			}}))
+		})
+
+		It("covers node port services", func() {
+			Expect(newServicePolicy(service(v1.ServiceTypeNodePort))).ToNot(BeNil())
+		})
+
+		It("ignores cluster internal services", func() {
+			Expect(newServicePolicy(service(v1.ServiceTypeClusterIP))).To(BeNil())
+		})
+	})
+
+	Describe("Validate", func() {
+		It("accepts isolation with allowed namespaces", func() {
+			Expect(Validate(models.NamespaceNetwork{Isolated: true, AllowFrom: []string{"shop"}})).To(Succeed())
+		})
+
+		It("rejects allowed namespaces without isolation", func() {
+			Expect(Validate(models.NamespaceNetwork{AllowFrom: []string{"shop"}})).To(
+				MatchError("allowed namespaces require an isolated namespace"))
+		})
+
+		It("rejects bad namespace names", func() {
+			Expect(Validate(models.NamespaceNetwork{Isolated: true, AllowFrom: []string{"Shop_1"}})).To(
+				MatchError(ContainSubstring("bad namespace name 'Shop_1'")))
+		})
+	})
+})
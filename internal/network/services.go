@@ -0,0 +1,139 @@
+package network
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/pkg/errors"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// servicePolicyComponent is the component label of the policies admitting the
+// external clients of the load balancer and node port services of an isolated
+// namespace.
+const servicePolicyComponent = "network-service"
+
+// SyncServicePolicies ensures that the pods behind the load balancer and node
+// port services of the namespace admit external clients, if the namespace is
+// isolated. It is called when these services change.
+func SyncServicePolicies(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	network, err := Get(ctx, cluster, namespace)
+	if err != nil {
+		return err
+	}
+
+	return syncServicePolicies(ctx, cluster, namespace, network.Isolated)
+}
+
+// syncServicePolicies creates, updates, or removes the policies of the external
+// services of the namespace. Without isolation all of them are removed. Each
+// policy is owned by its service, and is removed together with it.
+func syncServicePolicies(ctx context.Context, cluster *kubernetes.Cluster, namespace string, isolated bool) error {
+	client := cluster.Kubectl.NetworkingV1().NetworkPolicies(namespace)
+
+	existing, err := client.List(ctx, metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/managed-by=epinio,app.kubernetes.io/component=" + servicePolicyComponent,
+	})
+	if err != nil {
+		return err
+	}
+
+	desired := map[string]*networkingv1.NetworkPolicy{}
+	if isolated {
+		services, err := cluster.Kubectl.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "app.kubernetes.io/managed-by=epinio",
+		})
+		if err != nil {
+			return err
+		}
+		for _, service := range services.Items {
+			if policy := newServicePolicy(service); policy != nil {
+				desired[policy.Name] = policy
+			}
+		}
+	}
+
+	for _, policy := range existing.Items {
+		if _, ok := desired[policy.Name]; ok {
+			continue
+		}
+		err := client.Delete(ctx, policy.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	for _, policy := range desired {
+		_, err := client.Create(ctx, policy, metav1.CreateOptions{})
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsAlreadyExists(err) {
+			return errors.Wrap(err, "failed to create network policy")
+		}
+
+		current, err := client.Get(ctx, policy.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		policy.ResourceVersion = current.ResourceVersion
+		if _, err := client.Update(ctx, policy, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to update network policy")
+		}
+	}
+
+	return nil
+}
+
+// newServicePolicy returns the policy admitting all clients to the target ports
+// of the pods behind the service, for load balancer and node port services. It
+// returns nil for other services. The source ranges of a load balancer are
+// enforced by the service itself, and the address of the client is often
+// replaced by a node's on the way to the pod, so no ranges are applied here.
+func newServicePolicy(service v1.Service) *networkingv1.NetworkPolicy {
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer && service.Spec.Type != v1.ServiceTypeNodePort {
+		return nil
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil
+	}
+
+	ports := []networkingv1.NetworkPolicyPort{}
+	for _, port := range service.Spec.Ports {
+		protocol := port.Protocol
+		target := port.TargetPort
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &target})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      service.Name,
+			Namespace: service.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Name:       service.Name,
+				UID:        service.UID,
+			}},
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "epinio",
+				"app.kubernetes.io/component":  servicePolicyComponent,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: service.Spec.Selector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				Ports: ports,
+				From: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "::/0"}},
+				},
+			}},
+		},
+	}
+}
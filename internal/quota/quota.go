@@ -0,0 +1,426 @@
+// Package quota encapsulates all the functionality around the resource limits of
+// epinio-controlled namespaces.
+// CPU and memory limits are stored in and enforced by a kube ResourceQuota, with
+// a LimitRange providing the per-instance limits the ResourceQuota requires every
+// container to have. The limits on the number of applications, services, and
+// instances are epinio-level counters. They are kept as annotations of the
+// ResourceQuota and checked by the API server before it modifies the system.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/services"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ResourceQuotaName is the name of the ResourceQuota holding the namespace's quota
+	ResourceQuotaName = "epinio-quota"
+	// LimitRangeName is the name of the LimitRange holding the per-instance limits
+	LimitRangeName = "epinio-limits"
+
+	// DefaultInstanceCPU is the cpu limit of an app instance when the quota does not specify one
+	DefaultInstanceCPU = "500m"
+	// DefaultInstanceMemory is the memory limit of an app instance when the quota does not specify one
+	DefaultInstanceMemory = "512Mi"
+
+	appsAnnotation           = "epinio.suse.org/quota-apps"
+	servicesAnnotation       = "epinio.suse.org/quota-services"
+	instancesAnnotation      = "epinio.suse.org/quota-instances"
+	instanceCPUAnnotation    = "epinio.suse.org/quota-instance-cpu"
+	instanceMemoryAnnotation = "epinio.suse.org/quota-instance-memory"
+)
+
+// Get returns the quota of the namespace. A namespace without quota has the
+// empty quota, i.e. no limits.
+func Get(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (models.NamespaceQuota, error) {
+	result := models.NamespaceQuota{}
+
+	rq, err := cluster.Kubectl.CoreV1().ResourceQuotas(namespace).Get(ctx, ResourceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return result, nil
+		}
+		return result, err
+	}
+
+	if q, ok := rq.Spec.Hard[corev1.ResourceLimitsCPU]; ok {
+		result.CPU = q.String()
+	}
+	if q, ok := rq.Spec.Hard[corev1.ResourceLimitsMemory]; ok {
+		result.Memory = q.String()
+	}
+
+	result.Apps = countAnnotation(rq.Annotations, appsAnnotation)
+	result.Services = countAnnotation(rq.Annotations, servicesAnnotation)
+	result.Instances = countAnnotation(rq.Annotations, instancesAnnotation)
+	result.InstanceCPU = quantityAnnotation(rq.Annotations, instanceCPUAnnotation)
+	result.InstanceMemory = quantityAnnotation(rq.Annotations, instanceMemoryAnnotation)
+
+	return result, nil
+}
+
+// Set replaces the quota of the namespace. Setting the empty quota is
+// equivalent to Delete.
+func Set(ctx context.Context, cluster *kubernetes.Cluster, namespace string, quota models.NamespaceQuota) error {
+	if err := Validate(quota); err != nil {
+		return err
+	}
+
+	if quota == (models.NamespaceQuota{}) {
+		return Delete(ctx, cluster, namespace)
+	}
+
+	hard := corev1.ResourceList{}
+	if quota.CPU != "" {
+		hard[corev1.ResourceLimitsCPU] = resource.MustParse(quota.CPU)
+	}
+	if quota.Memory != "" {
+		hard[corev1.ResourceLimitsMemory] = resource.MustParse(quota.Memory)
+	}
+
+	annotations := map[string]string{}
+	setCountAnnotation(annotations, appsAnnotation, quota.Apps)
+	setCountAnnotation(annotations, servicesAnnotation, quota.Services)
+	setCountAnnotation(annotations, instancesAnnotation, quota.Instances)
+	if quota.InstanceCPU != "" {
+		annotations[instanceCPUAnnotation] = quota.InstanceCPU
+	}
+	if quota.InstanceMemory != "" {
+		annotations[instanceMemoryAnnotation] = quota.InstanceMemory
+	}
+
+	rq := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ResourceQuotaName,
+			Namespace:   namespace,
+			Labels:      quotaLabels(),
+			Annotations: annotations,
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: hard,
+		},
+	}
+
+	client := cluster.Kubectl.CoreV1().ResourceQuotas(namespace)
+	current, err := client.Get(ctx, ResourceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if _, err := client.Create(ctx, rq, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create resource quota")
+		}
+	} else {
+		rq.ResourceVersion = current.ResourceVersion
+		if _, err := client.Update(ctx, rq, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to update resource quota")
+		}
+	}
+
+	// A ResourceQuota on cpu or memory rejects all containers without
+	// limits for these. The LimitRange provides them.
+
+	if quota.CPU == "" && quota.Memory == "" {
+		return deleteLimitRange(ctx, cluster, namespace)
+	}
+
+	return setLimitRange(ctx, cluster, namespace, quota)
+}
+
+// Delete removes the quota of the namespace, if any.
+func Delete(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	err := cluster.Kubectl.CoreV1().ResourceQuotas(namespace).Delete(ctx, ResourceQuotaName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return deleteLimitRange(ctx, cluster, namespace)
+}
+
+// Validate checks that the quantities of the quota are well-formed, and that
+// the counts are not negative.
+func Validate(quota models.NamespaceQuota) error {
+	quantities := map[string]string{
+		"cpu":            quota.CPU,
+		"memory":         quota.Memory,
+		"instancecpu":    quota.InstanceCPU,
+		"instancememory": quota.InstanceMemory,
+	}
+	for name, value := range quantities {
+		if value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("bad %s quantity '%s': %s", name, value, err.Error())
+		}
+	}
+
+	if quota.Apps < 0 || quota.Services < 0 || quota.Instances < 0 {
+		return errors.New("counts must not be negative")
+	}
+
+	return nil
+}
+
+// Usage returns the current use of the limited resources in the namespace.
+// The instance count is the sum of the instances desired by all applications.
+func Usage(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (models.NamespaceQuota, error) {
+	result := models.NamespaceQuota{}
+
+	instances, err := desiredInstances(ctx, cluster, namespace)
+	if err != nil {
+		return result, err
+	}
+	for _, count := range instances {
+		result.Instances += count
+	}
+	result.Apps = int32(len(instances))
+
+	serviceList, err := services.List(ctx, cluster, namespace)
+	if err != nil {
+		return result, err
+	}
+	result.Services = int32(len(serviceList))
+
+	rq, err := cluster.Kubectl.CoreV1().ResourceQuotas(namespace).Get(ctx, ResourceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return result, nil
+		}
+		return result, err
+	}
+
+	if q, ok := rq.Status.Used[corev1.ResourceLimitsCPU]; ok {
+		result.CPU = q.String()
+	}
+	if q, ok := rq.Status.Used[corev1.ResourceLimitsMemory]; ok {
+		result.Memory = q.String()
+	}
+
+	return result, nil
+}
+
+// CheckApp determines if the application may exist with the specified
+// number of instances without exceeding the quota of its namespace. A new
+// application additionally counts against the application limit. The
+// result is the list of violated limits, empty if there are none.
+func CheckApp(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef,
+	instances int32, isNew bool) ([]string, error) {
+
+	quota, err := Get(ctx, cluster, appRef.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if quota == (models.NamespaceQuota{}) {
+		return nil, nil
+	}
+
+	desired, err := desiredInstances(ctx, cluster, appRef.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := int32(len(desired))
+	if isNew {
+		apps++
+	}
+
+	// The total replaces whatever the application currently desires with the
+	// new count.
+	total := instances
+	for name, count := range desired {
+		if name != appRef.Name {
+			total += count
+		}
+	}
+
+	return checkApp(quota, apps, total), nil
+}
+
+// CheckService determines if another service can be created in the namespace
+// without exceeding its quota. The result is the list of violated limits,
+// empty if there are none.
+func CheckService(ctx context.Context, cluster *kubernetes.Cluster, namespace string) ([]string, error) {
+	quota, err := Get(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if quota.Services == 0 {
+		return nil, nil
+	}
+
+	serviceList, err := services.List(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if int32(len(serviceList))+1 > quota.Services {
+		return []string{fmt.Sprintf("services: limit %d", quota.Services)}, nil
+	}
+
+	return nil, nil
+}
+
+// checkApp is the pure core of CheckApp. It compares the projected number of
+// applications and instances against the quota, with the cpu and memory of
+// the instances derived from the per-instance limits.
+func checkApp(quota models.NamespaceQuota, apps, instances int32) []string {
+	violations := []string{}
+
+	if quota.Apps > 0 && apps > quota.Apps {
+		violations = append(violations, fmt.Sprintf("apps: limit %d, requested %d", quota.Apps, apps))
+	}
+
+	if quota.Instances > 0 && instances > quota.Instances {
+		violations = append(violations, fmt.Sprintf("instances: limit %d, requested %d", quota.Instances, instances))
+	}
+
+	if quota.CPU != "" {
+		limit := resource.MustParse(quota.CPU)
+		requested := instanceQuantity(quota.InstanceCPU, DefaultInstanceCPU)
+		requested.SetMilli(requested.MilliValue() * int64(instances))
+		if requested.Cmp(limit) > 0 {
+			violations = append(violations, fmt.Sprintf("cpu: limit %s, requested %s", limit.String(), requested.String()))
+		}
+	}
+
+	if quota.Memory != "" {
+		limit := resource.MustParse(quota.Memory)
+		requested := instanceQuantity(quota.InstanceMemory, DefaultInstanceMemory)
+		requested.Set(requested.Value() * int64(instances))
+		if requested.Cmp(limit) > 0 {
+			violations = append(violations, fmt.Sprintf("memory: limit %s, requested %s", limit.String(), requested.String()))
+		}
+	}
+
+	return violations
+}
+
+// desiredInstances returns a map from the names of the applications in the
+// namespace to their desired number of instances.
+func desiredInstances(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (map[string]int32, error) {
+	appRefs, err := application.ListAppRefs(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]int32{}
+	for _, appRef := range appRefs {
+		count, err := application.Scaling(ctx, cluster, appRef)
+		if err != nil {
+			return nil, err
+		}
+		result[appRef.Name] = count
+	}
+
+	return result, nil
+}
+
+func setLimitRange(ctx context.Context, cluster *kubernetes.Cluster, namespace string, quota models.NamespaceQuota) error {
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    instanceQuantity(quota.InstanceCPU, DefaultInstanceCPU),
+		corev1.ResourceMemory: instanceQuantity(quota.InstanceMemory, DefaultInstanceMemory),
+	}
+
+	lr := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      LimitRangeName,
+			Namespace: namespace,
+			Labels:    quotaLabels(),
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{
+				{
+					Type:           corev1.LimitTypeContainer,
+					Default:        limits,
+					DefaultRequest: limits,
+				},
+			},
+		},
+	}
+
+	client := cluster.Kubectl.CoreV1().LimitRanges(namespace)
+	current, err := client.Get(ctx, LimitRangeName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if _, err := client.Create(ctx, lr, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create limit range")
+		}
+		return nil
+	}
+
+	lr.ResourceVersion = current.ResourceVersion
+	if _, err := client.Update(ctx, lr, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update limit range")
+	}
+	return nil
+}
+
+func deleteLimitRange(ctx context.Context, cluster *kubernetes.Cluster, namespace string) error {
+	err := cluster.Kubectl.CoreV1().LimitRanges(namespace).Delete(ctx, LimitRangeName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func quotaLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "epinio",
+		"app.kubernetes.io/managed-by": "epinio",
+		"app.kubernetes.io/component":  "quota",
+	}
+}
+
+// instanceQuantity returns the parsed quantity, or the parsed default when no
+// quantity is specified. Callers are expected to have validated the quantity.
+func instanceQuantity(value, defaultValue string) resource.Quantity {
+	if value == "" {
+		value = defaultValue
+	}
+	return resource.MustParse(value)
+}
+
+func countAnnotation(annotations map[string]string, key string) int32 {
+	value, ok := annotations[key]
+	if !ok {
+		return 0
+	}
+	count, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || count < 0 {
+		return 0
+	}
+	return int32(count)
+}
+
+// quantityAnnotation returns the quantity stored in the annotation. Externally
+// damaged values are treated as absent.
+func quantityAnnotation(annotations map[string]string, key string) string {
+	value := annotations[key]
+	if _, err := resource.ParseQuantity(value); err != nil {
+		return ""
+	}
+	return value
+}
+
+func setCountAnnotation(annotations map[string]string, key string, count int32) {
+	if count > 0 {
+		annotations[key] = strconv.Itoa(int(count))
+	}
+}
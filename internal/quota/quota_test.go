@@ -0,0 +1,50 @@
+package quota
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quota", func() {
+	Describe("checkApp", func() {
+		It("accepts everything without limits", func() {
+			Expect(checkApp(models.NamespaceQuota{}, 100, 1000)).To(BeEmpty())
+		})
+
+		It("rejects too many apps", func() {
+			Expect(checkApp(models.NamespaceQuota{Apps: 2}, 3, 3)).To(ConsistOf("apps: limit 2, requested 3"))
+		})
+
+		It("rejects too many instances", func() {
+			Expect(checkApp(models.NamespaceQuota{Instances: 4}, 1, 5)).To(ConsistOf("instances: limit 4, requested 5"))
+		})
+
+		It("derives cpu and memory from the default instance limits", func() {
+			q := models.NamespaceQuota{CPU: "1", Memory: "1Gi"}
+			Expect(checkApp(q, 1, 2)).To(BeEmpty())
+			Expect(checkApp(q, 1, 3)).To(ConsistOf(
+				"cpu: limit 1, requested 1500m",
+				"memory: limit 1Gi, requested 1536Mi"))
+		})
+
+		It("uses the specified instance limits", func() {
+			q := models.NamespaceQuota{CPU: "1", InstanceCPU: "100m"}
+			Expect(checkApp(q, 1, 10)).To(BeEmpty())
+			Expect(checkApp(q, 1, 11)).To(ConsistOf("cpu: limit 1, requested 1100m"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("accepts proper quantities", func() {
+			Expect(Validate(models.NamespaceQuota{CPU: "2", Memory: "4Gi", Apps: 3})).To(Succeed())
+		})
+		It("rejects bad quantities", func() {
+			Expect(Validate(models.NamespaceQuota{Memory: "lots"})).ToNot(Succeed())
+		})
+		It("rejects negative counts", func() {
+			Expect(Validate(models.NamespaceQuota{Services: -1})).ToNot(Succeed())
+		})
+	})
+})
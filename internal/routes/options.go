@@ -0,0 +1,187 @@
+package routes
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// OptionsAnnotation records the options (See Options.String) an ingress was
+// created with. A difference to the desired options causes SyncIngresses to
+// update the ingress.
+const OptionsAnnotation = "epinio.suse.org/route-options"
+
+// Options are the generic settings of a route. The ingress provider translates
+// them into its own configuration, see package internal/ingress. The zero
+// value of each option keeps the provider's default behaviour.
+//
+// In the string form of a route the options follow the path, in query syntax:
+//
+//	https-redirect        redirect plain http requests to https
+//	timeout=DURATION      maximum duration of a request, e.g. `30s`
+//	max-body-size=SIZE    maximum size of a request body, e.g. `10M`, `512Ki`
+//	allow=CIDR            accept requests from these addresses only (repeatable)
+//	basic-auth=SECRET     require the users of the htpasswd file in key `auth`
+//	                      of the secret, in the namespace of the application,
+//	                      for all ingress providers
+//
+// TCP routes accept only these options:
+//
+//	expose=TYPE           `loadbalancer` (default) or `nodeport`
+//	target=PORT           the port the application listens on, defaults to the
+//	                      port of the route
+//	allow=CIDR            as above, for load balancers only
+//
+// E.g. `staging.mydomain.org/api?https-redirect&allow=10.0.0.0/8&basic-auth=testers`
+type Options struct {
+	HTTPSRedirect   bool
+	Timeout         time.Duration
+	MaxBodySize     int64 // in bytes
+	AllowList       []string
+	BasicAuthSecret string
+	Expose          string
+	TargetPort      int32
+}
+
+// The ways of exposing a TCP route.
+const (
+	ExposeLoadBalancer = "loadbalancer"
+	ExposeNodePort     = "nodeport"
+)
+
+// String returns the canonical string form of the options. It is empty for
+// the default options.
+func (o Options) String() string {
+	options := []string{}
+
+	if o.HTTPSRedirect {
+		options = append(options, "https-redirect")
+	}
+	if o.Timeout > 0 {
+		options = append(options, "timeout="+o.Timeout.String())
+	}
+	if o.MaxBodySize > 0 {
+		options = append(options, "max-body-size="+strconv.FormatInt(o.MaxBodySize, 10))
+	}
+	for _, cidr := range o.AllowList {
+		options = append(options, "allow="+cidr)
+	}
+	if o.BasicAuthSecret != "" {
+		options = append(options, "basic-auth="+o.BasicAuthSecret)
+	}
+	if o.Expose != "" {
+		options = append(options, "expose="+o.Expose)
+	}
+	if o.TargetPort > 0 {
+		options = append(options, "target="+strconv.Itoa(int(o.TargetPort)))
+	}
+
+	return strings.Join(options, "&")
+}
+
+// ParseOptions converts the string form of route options into an Options
+// object. Single addresses in the allow list are turned into CIDRs.
+func ParseOptions(optionStr string) (Options, error) {
+	options := Options{}
+	if optionStr == "" {
+		return options, nil
+	}
+
+	values, err := url.ParseQuery(optionStr)
+	if err != nil {
+		return options, fmt.Errorf("bad options: %s", err)
+	}
+
+	for key, list := range values {
+		value := list[len(list)-1]
+
+		switch key {
+		case "https-redirect":
+			if value == "" {
+				options.HTTPSRedirect = true
+				break
+			}
+			options.HTTPSRedirect, err = strconv.ParseBool(value)
+			if err != nil {
+				return options, fmt.Errorf("bad https-redirect '%s', expected a boolean", value)
+			}
+		case "timeout":
+			options.Timeout, err = time.ParseDuration(value)
+			if err != nil || options.Timeout <= 0 {
+				return options, fmt.Errorf("bad timeout '%s', expected a positive duration", value)
+			}
+		case "max-body-size":
+			size, err := resource.ParseQuantity(value)
+			if err != nil || size.Sign() <= 0 {
+				return options, fmt.Errorf("bad max-body-size '%s', expected a positive size", value)
+			}
+			options.MaxBodySize = size.Value()
+		case "allow":
+			for _, cidr := range list {
+				if ip := net.ParseIP(cidr); ip != nil {
+					if ip.To4() != nil {
+						cidr += "/32"
+					} else {
+						cidr += "/128"
+					}
+				}
+				if _, _, err := net.ParseCIDR(cidr); err != nil {
+					return options, fmt.Errorf("bad allow '%s', expected an address or CIDR", cidr)
+				}
+				options.AllowList = append(options.AllowList, cidr)
+			}
+		case "basic-auth":
+			if value == "" {
+				return options, fmt.Errorf("bad basic-auth, expected a secret name")
+			}
+			options.BasicAuthSecret = value
+		case "expose":
+			if value != ExposeLoadBalancer && value != ExposeNodePort {
+				return options, fmt.Errorf("bad expose '%s', expected %s or %s", value, ExposeLoadBalancer, ExposeNodePort)
+			}
+			options.Expose = value
+		case "target":
+			options.TargetPort, err = parsePort(value)
+			if err != nil {
+				return options, fmt.Errorf("bad target: %s", err)
+			}
+		default:
+			return options, fmt.Errorf("unknown option '%s'", key)
+		}
+	}
+
+	return options, nil
+}
+
+// validFor returns an error if the options contain settings not applicable to
+// the type of route.
+func (o Options) validFor(routeType Type) error {
+	if routeType == TypeTCP {
+		if o.HTTPSRedirect || o.Timeout > 0 || o.MaxBodySize > 0 || o.BasicAuthSecret != "" {
+			return fmt.Errorf("tcp routes support only the options expose, target, and allow")
+		}
+		if len(o.AllowList) > 0 && o.Expose == ExposeNodePort {
+			return fmt.Errorf("option allow is not supported for node ports")
+		}
+		return nil
+	}
+
+	if o.Expose != "" || o.TargetPort > 0 {
+		return fmt.Errorf("options expose and target are supported for tcp routes only")
+	}
+	return nil
+}
+
+// parsePort converts the string into a port number.
+func parsePort(portStr string) (int32, error) {
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("bad port '%s', expected a number between 1 and 65535", portStr)
+	}
+	return int32(port), nil
+}
@@ -2,15 +2,43 @@ package routes
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Type distinguishes the kinds of routes.
+type Type string
+
+// The kinds of routes. HTTP and gRPC routes are exposed through an ingress,
+// with a host and path. TCP routes are exposed through a LoadBalancer or
+// NodePort service, with a port.
+const (
+	TypeHTTP Type = ""
+	TypeGRPC Type = "grpc"
+	TypeTCP  Type = "tcp"
+)
+
+// TypeAnnotation records the type of route an ingress was created for. It is
+// empty for HTTP routes.
+const TypeAnnotation = "epinio.suse.org/route-type"
+
+// Route is a host and path, or port, an application is reachable at, with its
+// options. The string form of a route is
+//
+//	DOMAIN[/PATH][?OPTIONS]           HTTP
+//	grpc://DOMAIN[/PATH][?OPTIONS]    gRPC, sent to the application as h2c
+//	tcp://PORT[?OPTIONS]              TCP
+//
+// See options.go for the syntax of the options.
 type Route struct {
-	Domain string
-	Path   string
+	Type    Type
+	Domain  string
+	Path    string
+	Port    int32
+	Options Options
 }
 
 // String returns the string representation of a Route object.
@@ -20,10 +48,59 @@ type Route struct {
 // also removes trailing "/". E.g.
 // Route{ Domain: "mydomain.org", Path: "/" }
 // becomes: "mydomain.org" (no trailing "/")
+// Routes of other types carry their type as scheme. E.g.
+// Route{ Type: TypeTCP, Port: 1883 }
+// becomes: "tcp://1883"
 func (r Route) String() string {
+	switch r.Type {
+	case TypeTCP:
+		return fmt.Sprintf("%s://%d", r.Type, r.Port)
+	case TypeGRPC:
+		return fmt.Sprintf("%s://%s", r.Type, strings.TrimSuffix(r.Domain+r.Path, "/"))
+	}
 	return strings.TrimSuffix(r.Domain+r.Path, "/")
 }
 
+// StringWithOptions returns the string representation of a Route object,
+// including its options, if any. The result is accepted by FromString.
+// E.g.
+// Route{ Domain: "mydomain.org", Path: "/api", Options: Options{ Timeout: time.Minute } }
+// becomes: "mydomain.org/api?timeout=1m0s"
+func (r Route) StringWithOptions() string {
+	options := r.Options.String()
+	if options == "" {
+		return r.String()
+	}
+	return r.String() + "?" + options
+}
+
+// Rehost returns a copy of the route whose host keeps its first label, with
+// the remainder replaced by the given domain. TCP routes have no host, and
+// are returned unchanged.
+// E.g.
+// Route{ Domain: "myapp.mydomain.org", Path: "/api" }.Rehost("qa.otherdomain.org")
+// becomes: Route{ Domain: "myapp.qa.otherdomain.org", Path: "/api" }
+func (r Route) Rehost(domain string) Route {
+	if r.Type == TypeTCP {
+		return r
+	}
+	host := strings.SplitN(r.Domain, ".", 2)[0]
+	r.Domain = host + "." + domain
+	return r
+}
+
+// ParentDomain returns the host of the route without its first label.
+// E.g.
+// Route{ Domain: "myapp.mydomain.org", Path: "/api" }
+// becomes: "mydomain.org"
+func (r Route) ParentDomain() string {
+	parts := strings.SplitN(r.Domain, ".", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
 // ToIngress  returns an Ingress resource for this route
 func (r Route) ToIngress(ingressName string) networkingv1.Ingress {
 	pathTypeImplementationSpecific := networkingv1.PathTypeImplementationSpecific
@@ -31,6 +108,10 @@ func (r Route) ToIngress(ingressName string) networkingv1.Ingress {
 	return networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: ingressName,
+			Annotations: map[string]string{
+				OptionsAnnotation: r.Options.String(),
+				TypeAnnotation:    string(r.Type),
+			},
 		},
 		Spec: networkingv1.IngressSpec{
 			Rules: []networkingv1.IngressRule{
@@ -45,27 +126,71 @@ func (r Route) ToIngress(ingressName string) networkingv1.Ingress {
 								}}}}}}}}
 }
 
-// FromString converts a route string to a Route object.
+// FromString converts a route string to a Route object. Invalid options are
+// ignored, use Parse to detect them.
 // E.g.
 // mydomain.org/api
 // becomes: Route{ Domain: "mydomain.org", Path: "/api" }
 func FromString(routeStr string) Route {
+	route, _ := Parse(routeStr)
+	return route
+}
+
+// Parse converts a route string to a Route object, and returns an error if
+// its port or options are invalid.
+// E.g.
+// mydomain.org/api?https-redirect
+// becomes: Route{ Domain: "mydomain.org", Path: "/api", Options: Options{ HTTPSRedirect: true } }
+func Parse(routeStr string) (Route, error) {
 	var domain, path string
 
-	splitRoute := strings.SplitN(routeStr, "/", 2)
-	domain = splitRoute[0]
-	if len(splitRoute) > 1 {
-		path = "/" + splitRoute[1]
-	} else {
-		path = "/"
+	splitOptions := strings.SplitN(routeStr, "?", 2)
+	routeStr = splitOptions[0]
+
+	route := Route{}
+	if rest := strings.TrimPrefix(routeStr, "grpc://"); rest != routeStr {
+		route.Type = TypeGRPC
+		routeStr = rest
+	} else if rest := strings.TrimPrefix(routeStr, "tcp://"); rest != routeStr {
+		route.Type = TypeTCP
+		port, err := parsePort(rest)
+		if err != nil {
+			return route, fmt.Errorf("route %s: %s", routeStr, err)
+		}
+		route.Port = port
 	}
-	return Route{Domain: domain, Path: path}
+
+	if route.Type != TypeTCP {
+		splitRoute := strings.SplitN(routeStr, "/", 2)
+		domain = splitRoute[0]
+		if len(splitRoute) > 1 {
+			path = "/" + splitRoute[1]
+		} else {
+			path = "/"
+		}
+		route.Domain = domain
+		route.Path = path
+	}
+
+	if len(splitOptions) > 1 {
+		options, err := ParseOptions(splitOptions[1])
+		if err == nil {
+			err = options.validFor(route.Type)
+		}
+		if err != nil {
+			return route, fmt.Errorf("route %s: %s", route, err)
+		}
+		route.Options = options
+	}
+
+	return route, nil
 }
 
 // FromIngress returns a Route resource matching the given Ingress
 // NOTE: Epinio doesn't create Ingresses with multiple rules. For that reason,
 // this function will try to construct a Route from the first rule of the passed
-// Ingress, ingoring all other rules if they exist.
+// Ingress, ingoring all other rules if they exist. The options are taken from
+// the annotation recorded by ToIngress.
 func FromIngress(ingress networkingv1.Ingress) (*Route, error) {
 	if len(ingress.Spec.Rules) == 0 {
 		return nil, errors.New("no Rules found on Ingress")
@@ -74,5 +199,14 @@ func FromIngress(ingress networkingv1.Ingress) (*Route, error) {
 	domain := rule.Host
 	path := rule.HTTP.Paths[0].Path
 
-	return &Route{Domain: domain, Path: path}, nil
+	// Options of ingresses created by older versions are not known, and
+	// annotations may have been edited. Fall back to the defaults.
+	options, _ := ParseOptions(ingress.Annotations[OptionsAnnotation])
+
+	return &Route{
+		Type:    Type(ingress.Annotations[TypeAnnotation]),
+		Domain:  domain,
+		Path:    path,
+		Options: options,
+	}, nil
 }
@@ -1,6 +1,8 @@
 package routes_test
 
 import (
+	"time"
+
 	. "github.com/epinio/epinio/internal/routes"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,6 +37,31 @@ var _ = Describe("Route", func() {
 		})
 	})
 
+	Describe("Rehost", func() {
+		It("replaces everything after the first label of the host", func() {
+			route := Route{Domain: "myapp.mydomain.org", Path: "/api"}
+			Expect(route.Rehost("qa.otherdomain.org")).To(Equal(Route{
+				Domain: "myapp.qa.otherdomain.org",
+				Path:   "/api",
+			}))
+		})
+
+		It("appends the domain to a single label host", func() {
+			route := Route{Domain: "myapp", Path: "/"}
+			Expect(route.Rehost("mydomain.org").String()).To(Equal("myapp.mydomain.org"))
+		})
+	})
+
+	Describe("ParentDomain", func() {
+		It("returns the host without its first label", func() {
+			Expect(FromString("myapp.mydomain.org/api").ParentDomain()).To(Equal("mydomain.org"))
+		})
+
+		It("returns nothing for a single label host", func() {
+			Expect(FromString("myapp").ParentDomain()).To(Equal(""))
+		})
+	})
+
 	Describe("FromIngress", func() {
 		var routeIngress networkingv1.Ingress
 		BeforeEach(func() {
@@ -146,3 +173,128 @@ var _ = Describe("Route", func() {
 		})
 	})
 })
+
+var _ = Describe("Route options", func() {
+	Describe("Parse", func() {
+		It("parses the options after the path", func() {
+			route, err := Parse("mydomain.org/api?https-redirect&timeout=30s&max-body-size=10Mi&allow=10.0.0.0/8&allow=192.168.1.1&basic-auth=testers")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(route).To(Equal(Route{
+				Domain: "mydomain.org",
+				Path:   "/api",
+				Options: Options{
+					HTTPSRedirect:   true,
+					Timeout:         30 * time.Second,
+					MaxBodySize:     10 * 1024 * 1024,
+					AllowList:       []string{"10.0.0.0/8", "192.168.1.1/32"},
+					BasicAuthSecret: "testers",
+				},
+			}))
+		})
+
+		It("parses options of a route without path", func() {
+			route, err := Parse("mydomain.org?https-redirect=false&timeout=1m")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(route).To(Equal(Route{
+				Domain:  "mydomain.org",
+				Path:    "/",
+				Options: Options{Timeout: time.Minute},
+			}))
+		})
+
+		It("rejects unknown options", func() {
+			_, err := Parse("mydomain.org/api?retries=3")
+			Expect(err).To(MatchError("route mydomain.org/api: unknown option 'retries'"))
+		})
+
+		It("rejects bad values", func() {
+			_, err := Parse("mydomain.org?timeout=soon")
+			Expect(err).To(MatchError(ContainSubstring("bad timeout 'soon'")))
+
+			_, err = Parse("mydomain.org?max-body-size=-1")
+			Expect(err).To(MatchError(ContainSubstring("bad max-body-size '-1'")))
+
+			_, err = Parse("mydomain.org?allow=10.0.0.0/40")
+			Expect(err).To(MatchError(ContainSubstring("bad allow '10.0.0.0/40'")))
+		})
+	})
+
+	Describe("StringWithOptions", func() {
+		It("returns the canonical form of the route", func() {
+			route := FromString("mydomain.org/api?basic-auth=testers&timeout=90s&https-redirect")
+			Expect(route.String()).To(Equal("mydomain.org/api"))
+			Expect(route.StringWithOptions()).To(Equal("mydomain.org/api?https-redirect&timeout=1m30s&basic-auth=testers"))
+			Expect(FromString(route.StringWithOptions())).To(Equal(route))
+		})
+
+		It("omits default options", func() {
+			Expect(FromString("mydomain.org/api").StringWithOptions()).To(Equal("mydomain.org/api"))
+		})
+	})
+
+	Describe("FromIngress", func() {
+		It("restores the type recorded by ToIngress", func() {
+			route := FromString("grpc://mydomain.org/api")
+			result, err := FromIngress(route.ToIngress("myingress"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*result).To(Equal(route))
+		})
+
+		It("restores the options recorded by ToIngress", func() {
+			route := FromString("mydomain.org/api?allow=10.0.0.0/8")
+			result, err := FromIngress(route.ToIngress("myingress"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*result).To(Equal(route))
+		})
+	})
+})
+
+var _ = Describe("Route types", func() {
+	It("parses grpc routes", func() {
+		route, err := Parse("grpc://mydomain.org/api?timeout=10s")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(route).To(Equal(Route{
+			Type:    TypeGRPC,
+			Domain:  "mydomain.org",
+			Path:    "/api",
+			Options: Options{Timeout: 10 * time.Second},
+		}))
+		Expect(route.String()).To(Equal("grpc://mydomain.org/api"))
+	})
+
+	It("parses tcp routes", func() {
+		route, err := Parse("tcp://1883?expose=nodeport&target=1884")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(route).To(Equal(Route{
+			Type:    TypeTCP,
+			Port:    1883,
+			Options: Options{Expose: ExposeNodePort, TargetPort: 1884},
+		}))
+		Expect(route.String()).To(Equal("tcp://1883"))
+		Expect(route.StringWithOptions()).To(Equal("tcp://1883?expose=nodeport&target=1884"))
+	})
+
+	It("rejects bad ports", func() {
+		_, err := Parse("tcp://mqtt")
+		Expect(err).To(MatchError(ContainSubstring("bad port 'mqtt'")))
+
+		_, err = Parse("tcp://70000")
+		Expect(err).To(MatchError(ContainSubstring("bad port '70000'")))
+	})
+
+	It("rejects options not fitting the type", func() {
+		_, err := Parse("tcp://1883?https-redirect")
+		Expect(err).To(HaveOccurred())
+
+		_, err = Parse("tcp://1883?expose=nodeport&allow=10.0.0.0/8")
+		Expect(err).To(HaveOccurred())
+
+		_, err = Parse("mydomain.org?expose=nodeport")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("leaves tcp routes alone when rehosting", func() {
+		route := FromString("tcp://1883")
+		Expect(route.Rehost("other.org")).To(Equal(route))
+	})
+})
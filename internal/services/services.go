@@ -3,9 +3,12 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	epinioerrors "github.com/epinio/epinio/internal/errors"
@@ -15,17 +18,47 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/util/retry"
 )
 
+const (
+	// LabelsAnnotation is the annotation on a service's secret holding the
+	// user-specified labels of the service, as JSON-encoded map.
+	LabelsAnnotation = "epinio.suse.org/service-labels"
+
+	// DescriptionAnnotation is the annotation on a service's secret holding
+	// the user-specified description of the service.
+	DescriptionAnnotation = "epinio.suse.org/service-description"
+)
+
 type ServiceList []*Service
 
+// Filter returns the services of the list whose labels match the selector.
+func (sl ServiceList) Filter(selector labels.Selector) ServiceList {
+	if selector == nil || selector.Empty() {
+		return sl
+	}
+
+	result := ServiceList{}
+	for _, service := range sl {
+		if selector.Matches(labels.Set(service.Labels)) {
+			result = append(result, service)
+		}
+	}
+
+	return result
+}
+
 // Service contains the information needed for Epinio to address a specific service.
 type Service struct {
 	SecretName    string
 	NamespaceName string
 	Service       string
 	Username      string
+	Labels        map[string]string
+	Description   string
 	kubeClient    *kubernetes.Cluster
 }
 
@@ -51,6 +84,8 @@ func Lookup(ctx context.Context, kubeClient *kubernetes.Cluster, namespace, serv
 		Service:       service,
 		kubeClient:    kubeClient,
 		Username:      username,
+		Labels:        userLabels(s.ObjectMeta.Annotations),
+		Description:   s.ObjectMeta.Annotations[DescriptionAnnotation],
 	}, nil
 }
 
@@ -99,6 +134,8 @@ func List(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (S
 			Service:       service,
 			kubeClient:    cluster,
 			Username:      username,
+			Labels:        userLabels(s.ObjectMeta.Annotations),
+			Description:   s.ObjectMeta.Annotations[DescriptionAnnotation],
 		})
 	}
 
@@ -106,9 +143,9 @@ func List(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (S
 }
 
 // CreateService creates a new  service instance from namespace,
-// name, and a map of parameters.
+// name, a map of parameters, and the optional user labels and description.
 func CreateService(ctx context.Context, cluster *kubernetes.Cluster, name, namespace, username string,
-	data map[string]string) (*Service, error) {
+	data, userlabels map[string]string, description string) (*Service, error) {
 
 	secretName := serviceResourceName(namespace, name)
 
@@ -124,6 +161,11 @@ func CreateService(ctx context.Context, cluster *kubernetes.Cluster, name, names
 		sdata[k] = []byte(v)
 	}
 
+	annotations, err := serviceAnnotations(userlabels, description)
+	if err != nil {
+		return nil, err
+	}
+
 	err = cluster.CreateLabeledSecret(ctx, namespace, secretName, sdata,
 		map[string]string{
 			// "epinio.suse.org/service-type": "custom",
@@ -135,6 +177,7 @@ func CreateService(ctx context.Context, cluster *kubernetes.Cluster, name, names
 			// FIXME: Importing cmd causes cycle
 			// FIXME: Move version info to separate package!
 		},
+		annotations,
 	)
 	if err != nil {
 		return nil, err
@@ -143,6 +186,8 @@ func CreateService(ctx context.Context, cluster *kubernetes.Cluster, name, names
 		SecretName:    secretName,
 		NamespaceName: namespace,
 		Service:       name,
+		Labels:        userlabels,
+		Description:   description,
 		kubeClient:    cluster,
 	}, nil
 }
@@ -165,6 +210,34 @@ func UpdateService(ctx context.Context, cluster *kubernetes.Cluster, service *Se
 			serviceSecret.Data[key] = []byte(value)
 		}
 
+		if len(changes.SetLabels) > 0 || len(changes.RemoveLabels) > 0 || changes.Description != nil {
+			userlabels := userLabels(serviceSecret.Annotations)
+			for _, remove := range changes.RemoveLabels {
+				delete(userlabels, remove)
+			}
+			for key, value := range changes.SetLabels {
+				userlabels[key] = value
+			}
+
+			description := serviceSecret.Annotations[DescriptionAnnotation]
+			if changes.Description != nil {
+				description = *changes.Description
+			}
+
+			annotations, err := serviceAnnotations(userlabels, description)
+			if err != nil {
+				return err
+			}
+			if serviceSecret.Annotations == nil {
+				serviceSecret.Annotations = map[string]string{}
+			}
+			delete(serviceSecret.Annotations, LabelsAnnotation)
+			delete(serviceSecret.Annotations, DescriptionAnnotation)
+			for key, value := range annotations {
+				serviceSecret.Annotations[key] = value
+			}
+		}
+
 		_, err = cluster.Kubectl.CoreV1().Secrets(service.NamespaceName).Update(
 			ctx, serviceSecret, metav1.UpdateOptions{})
 		return err
@@ -256,6 +329,62 @@ func (s *Service) Details(ctx context.Context) (map[string]string, error) {
 	return details, nil
 }
 
+// ValidateLabels checks that the user labels are acceptable as kubernetes
+// labels, which is required for their use in label selectors.
+func ValidateLabels(userlabels map[string]string) error {
+	keys := []string{}
+	for key := range userlabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := userlabels[key]
+		if errorMsgs := validation.IsQualifiedName(key); len(errorMsgs) > 0 {
+			return fmt.Errorf("bad label key %s: %s", key, strings.Join(errorMsgs, "\n"))
+		}
+		if errorMsgs := validation.IsValidLabelValue(value); len(errorMsgs) > 0 {
+			return fmt.Errorf("bad label %s=%s: %s", key, value, strings.Join(errorMsgs, "\n"))
+		}
+	}
+	return nil
+}
+
+// userLabels decodes the user labels stored in the annotations of a
+// service's secret. Undecodable labels are treated as absent.
+func userLabels(annotations map[string]string) map[string]string {
+	result := map[string]string{}
+
+	encoded, ok := annotations[LabelsAnnotation]
+	if !ok {
+		return result
+	}
+	if err := json.Unmarshal([]byte(encoded), &result); err != nil {
+		return map[string]string{}
+	}
+
+	return result
+}
+
+// serviceAnnotations returns the annotations holding the user labels and
+// description of a service. Empty values are not recorded.
+func serviceAnnotations(userlabels map[string]string, description string) (map[string]string, error) {
+	annotations := map[string]string{}
+
+	if len(userlabels) > 0 {
+		encoded, err := json.Marshal(userlabels)
+		if err != nil {
+			return nil, err
+		}
+		annotations[LabelsAnnotation] = string(encoded)
+	}
+	if description != "" {
+		annotations[DescriptionAnnotation] = description
+	}
+
+	return annotations, nil
+}
+
 // serviceResourceName returns a name for a kube service resource
 // representing the namespace and service
 func serviceResourceName(namespace, service string) string {
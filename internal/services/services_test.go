@@ -1,11 +1,64 @@
 package services_test
 
 import (
+	. "github.com/epinio/epinio/internal/services"
+	"k8s.io/apimachinery/pkg/labels"
+
 	. "github.com/onsi/ginkgo/v2"
-	//. "github.com/onsi/gomega"
-	//. "github.com/epinio/epinio/internal/services"
+	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Services", func() {
+	Describe("ServiceList.Filter", func() {
+		var list ServiceList
+		BeforeEach(func() {
+			list = ServiceList{
+				&Service{Service: "db", Labels: map[string]string{"env": "prod", "tier": "data"}},
+				&Service{Service: "cache", Labels: map[string]string{"env": "dev"}},
+				&Service{Service: "plain", Labels: map[string]string{}},
+			}
+		})
+
+		names := func(l ServiceList) []string {
+			result := []string{}
+			for _, s := range l {
+				result = append(result, s.Name())
+			}
+			return result
+		}
+
+		It("returns everything for an empty selector", func() {
+			Expect(names(list.Filter(labels.Everything()))).To(Equal([]string{"db", "cache", "plain"}))
+		})
+
+		It("returns the services matching the selector", func() {
+			selector, err := labels.Parse("env=prod")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(list.Filter(selector))).To(Equal([]string{"db"}))
+		})
+
+		It("supports set-based selectors", func() {
+			selector, err := labels.Parse("env in (prod,dev),!tier")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(list.Filter(selector))).To(Equal([]string{"cache"}))
+		})
+	})
 
+	Describe("ValidateLabels", func() {
+		It("accepts proper labels", func() {
+			Expect(ValidateLabels(map[string]string{"env": "prod"})).To(Succeed())
+		})
+		It("rejects bad label values", func() {
+			Expect(ValidateLabels(map[string]string{"env": "has space"})).ToNot(Succeed())
+		})
+		It("rejects label values holding selector syntax", func() {
+			Expect(ValidateLabels(map[string]string{"env": "prod,tier=x"})).ToNot(Succeed())
+			Expect(ValidateLabels(map[string]string{"env": "prod!=dev"})).ToNot(Succeed())
+			Expect(ValidateLabels(map[string]string{"env": "in (prod,dev)"})).ToNot(Succeed())
+		})
+		It("rejects bad label keys", func() {
+			Expect(ValidateLabels(map[string]string{"env,tier": "prod"})).ToNot(Succeed())
+			Expect(ValidateLabels(map[string]string{"": "prod"})).ToNot(Succeed())
+		})
+	})
 })
@@ -0,0 +1,181 @@
+// Package volumes encapsulates all the functionality around the persistent volumes of
+// epinio-controlled namespaces.
+// A volume is a kube PersistentVolumeClaim in the namespace. It is not owned by any
+// application, i.e. its data survives the applications it is bound to. The bindings
+// are stored with the applications, see application.BoundVolumes.
+package volumes
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// DefaultSize is the size of a volume when the request does not specify one
+	DefaultSize = "1Gi"
+
+	// Component is the component label of the claims of volumes
+	Component = "volume"
+)
+
+// Create creates the named volume in the namespace.
+func Create(ctx context.Context, cluster *kubernetes.Cluster, namespace string, request models.VolumeCreateRequest, username string) error {
+	if err := Validate(request); err != nil {
+		return err
+	}
+
+	pvc := newClaim(namespace, request, username)
+
+	_, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	return err
+}
+
+// Get returns the named volume of the namespace. A missing volume is reported
+// as a kube NotFound error.
+func Get(ctx context.Context, cluster *kubernetes.Cluster, namespace, name string) (models.Volume, error) {
+	pvc, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, names.VolumeName(name), metav1.GetOptions{})
+	if err != nil {
+		return models.Volume{}, err
+	}
+
+	return toModel(*pvc), nil
+}
+
+// List returns the volumes of the namespace, ordered by name. The empty
+// namespace returns the volumes of all namespaces.
+func List(ctx context.Context, cluster *kubernetes.Cluster, namespace string) (models.VolumeList, error) {
+	selector := labels.Set(map[string]string{
+		"app.kubernetes.io/component":  Component,
+		"app.kubernetes.io/managed-by": "epinio",
+	}).AsSelector().String()
+
+	pvcList, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.VolumeList{}
+	for _, pvc := range pvcList.Items {
+		result = append(result, toModel(pvc))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// Delete removes the named volume from the namespace, and with it its data.
+// Checking that the volume is not bound is the responsibility of the caller.
+func Delete(ctx context.Context, cluster *kubernetes.Cluster, namespace, name string) error {
+	return cluster.Kubectl.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, names.VolumeName(name), metav1.DeleteOptions{})
+}
+
+// Validate checks that the name of the requested volume is a proper name, and
+// that its size is a positive quantity.
+func Validate(request models.VolumeCreateRequest) error {
+	if msgs := validation.IsDNS1123Label(request.Name); len(msgs) > 0 {
+		return errors.Errorf("bad volume name '%s': %s", request.Name, strings.Join(msgs, ", "))
+	}
+
+	if request.Size != "" {
+		size, err := resource.ParseQuantity(request.Size)
+		if err != nil || size.Sign() <= 0 {
+			return errors.Errorf("bad volume size '%s', expected a positive quantity, e.g. 10Gi", request.Size)
+		}
+	}
+
+	return nil
+}
+
+// ValidateMountPath checks that the path is suitable for mounting a volume at,
+// i.e. absolute, and neither the root directory nor the directory of the bound
+// services.
+func ValidateMountPath(mountPath string) error {
+	if !path.IsAbs(mountPath) {
+		return errors.Errorf("bad mount path '%s', expected an absolute path", mountPath)
+	}
+
+	cleaned := path.Clean(mountPath)
+	if cleaned == "/" || cleaned == "/services" || strings.HasPrefix(cleaned, "/services/") {
+		return errors.Errorf("bad mount path '%s', reserved directory", mountPath)
+	}
+
+	return nil
+}
+
+// newClaim returns the claim of the requested volume.
+func newClaim(namespace string, request models.VolumeCreateRequest, username string) *corev1.PersistentVolumeClaim {
+	size := request.Size
+	if size == "" {
+		size = DefaultSize
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.VolumeName(request.Name),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       request.Name,
+				"app.kubernetes.io/part-of":    namespace,
+				"app.kubernetes.io/component":  Component,
+				"app.kubernetes.io/managed-by": "epinio",
+				"app.kubernetes.io/created-by": username,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteOnce,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(size),
+				},
+			},
+		},
+	}
+
+	if request.StorageClass != "" {
+		storageClass := request.StorageClass
+		pvc.Spec.StorageClassName = &storageClass
+	}
+
+	return pvc
+}
+
+// toModel returns the volume described by the claim.
+func toModel(pvc corev1.PersistentVolumeClaim) models.Volume {
+	volume := models.Volume{
+		Name:      pvc.Labels["app.kubernetes.io/name"],
+		Namespace: pvc.Namespace,
+		Status:    string(pvc.Status.Phase),
+	}
+
+	if size, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		volume.Size = size.String()
+	}
+	if pvc.Spec.StorageClassName != nil {
+		volume.StorageClass = *pvc.Spec.StorageClassName
+	}
+
+	return volume
+}
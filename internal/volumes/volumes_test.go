@@ -0,0 +1,83 @@
+package volumes
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Volumes", func() {
+	Describe("Validate", func() {
+		It("accepts a proper name without size", func() {
+			Expect(Validate(models.VolumeCreateRequest{Name: "uploads"})).To(Succeed())
+		})
+
+		It("accepts a positive size", func() {
+			Expect(Validate(models.VolumeCreateRequest{Name: "uploads", Size: "10Gi"})).To(Succeed())
+		})
+
+		It("rejects a bad name", func() {
+			Expect(Validate(models.VolumeCreateRequest{Name: "Up_loads"})).To(MatchError(ContainSubstring("bad volume name")))
+		})
+
+		It("rejects a bad size", func() {
+			Expect(Validate(models.VolumeCreateRequest{Name: "uploads", Size: "lots"})).To(MatchError(ContainSubstring("bad volume size")))
+			Expect(Validate(models.VolumeCreateRequest{Name: "uploads", Size: "0"})).To(MatchError(ContainSubstring("bad volume size")))
+			Expect(Validate(models.VolumeCreateRequest{Name: "uploads", Size: "-1Gi"})).To(MatchError(ContainSubstring("bad volume size")))
+		})
+	})
+
+	Describe("ValidateMountPath", func() {
+		It("accepts absolute paths", func() {
+			Expect(ValidateMountPath("/app/uploads")).To(Succeed())
+			Expect(ValidateMountPath("/data")).To(Succeed())
+		})
+
+		It("rejects relative paths", func() {
+			Expect(ValidateMountPath("uploads")).To(MatchError(ContainSubstring("expected an absolute path")))
+		})
+
+		It("rejects the root and the services directories", func() {
+			Expect(ValidateMountPath("/")).To(MatchError(ContainSubstring("reserved directory")))
+			Expect(ValidateMountPath("/app/..")).To(MatchError(ContainSubstring("reserved directory")))
+			Expect(ValidateMountPath("/services")).To(MatchError(ContainSubstring("reserved directory")))
+			Expect(ValidateMountPath("/services/mydb")).To(MatchError(ContainSubstring("reserved directory")))
+		})
+	})
+
+	Describe("newClaim", func() {
+		It("uses the defaults", func() {
+			pvc := newClaim("workspace", models.VolumeCreateRequest{Name: "uploads"}, "admin")
+
+			Expect(pvc.Name).To(Equal("v-uploads"))
+			Expect(pvc.Namespace).To(Equal("workspace"))
+			Expect(pvc.Labels).To(HaveKeyWithValue("app.kubernetes.io/component", Component))
+			Expect(pvc.Labels).To(HaveKeyWithValue("app.kubernetes.io/created-by", "admin"))
+			Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+			Expect(pvc.Spec.StorageClassName).To(BeNil())
+
+			size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			Expect(size.Cmp(resource.MustParse(DefaultSize))).To(Equal(0))
+		})
+
+		It("round-trips the requested volume", func() {
+			pvc := newClaim("workspace", models.VolumeCreateRequest{
+				Name:         "uploads",
+				Size:         "5Gi",
+				StorageClass: "fast",
+			}, "admin")
+			pvc.Status.Phase = corev1.ClaimBound
+
+			Expect(toModel(*pvc)).To(Equal(models.Volume{
+				Name:         "uploads",
+				Namespace:    "workspace",
+				Size:         "5Gi",
+				StorageClass: "fast",
+				Status:       "Bound",
+			}))
+		})
+	})
+})
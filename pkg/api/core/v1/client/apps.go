@@ -136,6 +136,24 @@ func (c *Client) AppShow(namespace string, appName string) (models.App, error) {
 	return resp, nil
 }
 
+// AppEvents returns the events of an app, and the diagnostics of its instances
+func (c *Client) AppEvents(namespace string, appName string) (models.AppEventsResponse, error) {
+	var resp models.AppEventsResponse
+
+	data, err := c.get(api.Routes.Path("AppEvents", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
 // AppUpdate updates an app
 func (c *Client) AppUpdate(req models.ApplicationUpdateRequest, namespace string, appName string) (models.Response, error) {
 	var resp models.Response
@@ -159,6 +177,29 @@ func (c *Client) AppUpdate(req models.ApplicationUpdateRequest, namespace string
 	return resp, nil
 }
 
+// AppRouteCert configures the certificate of an app route
+func (c *Client) AppRouteCert(req models.RouteCertRequest, namespace string, appName string) (models.Response, error) {
+	var resp models.Response
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, nil
+	}
+
+	data, err := c.post(api.Routes.Path("AppRouteCert", namespace, appName), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
 // AppDelete deletes an app
 func (c *Client) AppDelete(namespace string, name string) (models.ApplicationDeleteResponse, error) {
 	resp := models.ApplicationDeleteResponse{}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"encoding/json"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Domains returns the registered domains
+func (c *Client) Domains() (models.DomainList, error) {
+	var resp models.DomainList
+
+	data, err := c.get(api.Routes.Path("Domains"))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// DomainCreate registers a domain
+func (c *Client) DomainCreate(req models.Domain) (models.Response, error) {
+	var resp models.Response
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("DomainCreate"), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// DomainAssign assigns a domain to a namespace, or releases it
+func (c *Client) DomainAssign(name string, req models.DomainAssignRequest) (models.Response, error) {
+	var resp models.Response
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.patch(api.Routes.Path("DomainAssign", name), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// DomainDelete removes a domain from the registry
+func (c *Client) DomainDelete(name string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("DomainDelete", name))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
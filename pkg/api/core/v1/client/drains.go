@@ -0,0 +1,126 @@
+package client
+
+import (
+	"encoding/json"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// AppDrains returns the log drains of an app
+func (c *Client) AppDrains(namespace, appName string) (models.DrainList, error) {
+	var resp models.DrainList
+
+	data, err := c.get(api.Routes.Path("AppDrains", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppDrainCreate adds a log drain to an app
+func (c *Client) AppDrainCreate(req models.DrainCreateRequest, namespace, appName string) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("AppDrainCreate", namespace, appName), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppDrainDelete removes a log drain from an app
+func (c *Client) AppDrainDelete(namespace, appName, id string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("AppDrainDelete", namespace, appName, id))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceDrains returns the log drains of a namespace
+func (c *Client) NamespaceDrains(namespace string) (models.DrainList, error) {
+	var resp models.DrainList
+
+	data, err := c.get(api.Routes.Path("NamespaceDrains", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceDrainCreate adds a log drain to a namespace
+func (c *Client) NamespaceDrainCreate(req models.DrainCreateRequest, namespace string) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("NamespaceDrainCreate", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceDrainDelete removes a log drain from a namespace
+func (c *Client) NamespaceDrainDelete(namespace, id string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("NamespaceDrainDelete", namespace, id))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
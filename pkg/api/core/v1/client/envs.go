@@ -103,3 +103,62 @@ func (c *Client) EnvMatch(namespace string, appName string, prefix string) (mode
 
 	return resp, nil
 }
+
+// NamespaceEnvList returns the default env vars of a namespace
+func (c *Client) NamespaceEnvList(namespace string) (models.EnvVariableMap, error) {
+	var resp models.EnvVariableMap
+
+	data, err := c.get(api.Routes.Path("NamespaceEnvList", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceEnvSet sets default env vars for a namespace
+func (c *Client) NamespaceEnvSet(req models.EnvVariableMap, namespace string) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, nil
+	}
+
+	data, err := c.post(api.Routes.Path("NamespaceEnvSet", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceEnvUnset removes a default env var from a namespace
+func (c *Client) NamespaceEnvUnset(namespace string, envName string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("NamespaceEnvUnset", namespace, envName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
@@ -101,3 +101,121 @@ func (c *Client) Namespaces() (models.NamespaceList, error) {
 
 	return resp, nil
 }
+
+// NamespaceQuota returns the quota of a namespace, and its current usage
+func (c *Client) NamespaceQuota(namespace string) (models.NamespaceQuotaResponse, error) {
+	resp := models.NamespaceQuotaResponse{}
+
+	data, err := c.get(api.Routes.Path("NamespaceQuota", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceQuotaSet replaces the quota of a namespace
+func (c *Client) NamespaceQuotaSet(namespace string, req models.NamespaceQuota) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.put(api.Routes.Path("NamespaceQuotaSet", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceQuotaDelete removes the quota of a namespace
+func (c *Client) NamespaceQuotaDelete(namespace string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("NamespaceQuotaDelete", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceNetwork returns the network isolation of a namespace
+func (c *Client) NamespaceNetwork(namespace string) (models.NamespaceNetwork, error) {
+	resp := models.NamespaceNetwork{}
+
+	data, err := c.get(api.Routes.Path("NamespaceNetwork", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceNetworkSet replaces the network isolation of a namespace
+func (c *Client) NamespaceNetworkSet(namespace string, req models.NamespaceNetwork) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.put(api.Routes.Path("NamespaceNetworkSet", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// NamespaceNetworkDelete removes the network isolation of a namespace
+func (c *Client) NamespaceNetworkDelete(namespace string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("NamespaceNetworkDelete", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
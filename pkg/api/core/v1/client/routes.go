@@ -0,0 +1,49 @@
+package client
+
+import (
+	"encoding/json"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Routes returns the active routes of all applications
+func (c *Client) Routes() (models.RouteList, error) {
+	var resp models.RouteList
+
+	data, err := c.get(api.Routes.Path("Routes"))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// RouteMove moves a route between two applications of the namespace
+func (c *Client) RouteMove(req models.RouteMoveRequest, namespace string) (models.Response, error) {
+	var resp models.Response
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, nil
+	}
+
+	data, err := c.post(api.Routes.Path("AppRouteMove", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
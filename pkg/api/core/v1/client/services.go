@@ -2,6 +2,7 @@ package client
 
 import (
 	"encoding/json"
+	"net/url"
 
 	"github.com/pkg/errors"
 
@@ -11,9 +12,15 @@ import (
 
 // Services returns a list of services for the specified namespace
 func (c *Client) Services(namespace string) (models.ServiceResponseList, error) {
+	return c.ServicesWithSelector(namespace, "")
+}
+
+// ServicesWithSelector returns a list of the services in the specified
+// namespace whose labels match the selector
+func (c *Client) ServicesWithSelector(namespace, selector string) (models.ServiceResponseList, error) {
 	resp := models.ServiceResponseList{}
 
-	data, err := c.get(api.Routes.Path("Services", namespace))
+	data, err := c.get(withSelector(api.Routes.Path("Services", namespace), selector))
 	if err != nil {
 		return resp, err
 	}
@@ -29,9 +36,15 @@ func (c *Client) Services(namespace string) (models.ServiceResponseList, error)
 
 // AllServices returns a list of all services, across all namespaces
 func (c *Client) AllServices() (models.ServiceResponseList, error) {
+	return c.AllServicesWithSelector("")
+}
+
+// AllServicesWithSelector returns a list of all services, across all
+// namespaces, whose labels match the selector
+func (c *Client) AllServicesWithSelector(selector string) (models.ServiceResponseList, error) {
 	resp := models.ServiceResponseList{}
 
-	data, err := c.get(api.Routes.Path("AllServices"))
+	data, err := c.get(withSelector(api.Routes.Path("AllServices"), selector))
 	if err != nil {
 		return resp, err
 	}
@@ -45,6 +58,15 @@ func (c *Client) AllServices() (models.ServiceResponseList, error) {
 	return resp, nil
 }
 
+// withSelector extends the endpoint with the label selector as query
+// parameter, if a selector is specified.
+func withSelector(endpoint, selector string) string {
+	if selector == "" {
+		return endpoint
+	}
+	return endpoint + "?" + url.Values{"selector": []string{selector}}.Encode()
+}
+
 // ServiceBindingCreate creates a binding from an app to a serviceclass
 func (c *Client) ServiceBindingCreate(req models.BindRequest, namespace string, appName string) (models.BindResponse, error) {
 	resp := models.BindResponse{}
@@ -205,3 +227,28 @@ func (c *Client) ServiceApps(namespace string) (models.ServiceAppsResponse, erro
 
 	return resp, nil
 }
+
+// ServiceReplace replaces the data of a service by invoking the associated API endpoint
+func (c *Client) ServiceReplace(req models.ServiceReplaceRequest, namespace, name string) (models.Response, error) {
+	resp := models.Response{}
+
+	c.log.V(5).WithValues("namespace", namespace, "service", name).Info("requesting ServiceReplace")
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, nil
+	}
+
+	data, err := c.put(api.Routes.Path("ServiceReplace", namespace, name), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
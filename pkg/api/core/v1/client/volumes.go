@@ -0,0 +1,108 @@
+package client
+
+import (
+	"encoding/json"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Volumes returns the volumes of a namespace
+func (c *Client) Volumes(namespace string) (models.VolumeList, error) {
+	var resp models.VolumeList
+
+	data, err := c.get(api.Routes.Path("Volumes", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// VolumeCreate creates a volume in a namespace
+func (c *Client) VolumeCreate(req models.VolumeCreateRequest, namespace string) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("VolumeCreate", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// VolumeDelete deletes a volume of a namespace
+func (c *Client) VolumeDelete(namespace, name string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("VolumeDelete", namespace, name))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// VolumeBindingCreate binds a volume to an application, at a mount path
+func (c *Client) VolumeBindingCreate(req models.VolumeBindRequest, namespace, appName string) (models.Response, error) {
+	resp := models.Response{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("VolumeBindingCreate", namespace, appName), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// VolumeBindingDelete removes the binding between a volume and an application
+func (c *Client) VolumeBindingDelete(namespace, appName, volumeName string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("VolumeBindingDelete", namespace, appName, volumeName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
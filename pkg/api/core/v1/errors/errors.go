@@ -179,3 +179,91 @@ func ServiceIsNotBound(service string) APIError {
 		"",
 		http.StatusBadRequest)
 }
+
+// QuotaExceeded constructs an API error for when an operation would exceed the quota of the namespace
+func QuotaExceeded(namespace string, violations ...string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Quota of namespace '%s' exceeded", namespace),
+		strings.Join(violations, ", "),
+		http.StatusForbidden)
+}
+
+// RoutesNotOwned constructs an API error for when routes are placed on domains not owned by the namespace
+func RoutesNotOwned(namespace string, routes ...string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Namespace '%s' does not own the domains of the routes", namespace),
+		strings.Join(routes, ", "),
+		http.StatusForbidden)
+}
+
+// DomainIsNotKnown constructs an API error for when the desired domain is not registered
+func DomainIsNotKnown(domain string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Domain '%s' is not registered", domain),
+		"",
+		http.StatusNotFound)
+}
+
+// DomainAlreadyKnown constructs an API error for when we have a conflict with an already registered domain
+func DomainAlreadyKnown(domain string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Domain '%s' is already registered", domain),
+		"",
+		http.StatusConflict)
+}
+
+// RouteInUse constructs an API error for when a route is already claimed by another app
+func RouteInUse(route, namespace, app string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Route '%s' is already used by application '%s' in namespace '%s'", route, app, namespace),
+		"",
+		http.StatusConflict)
+}
+
+// VolumeIsNotKnown constructs an API error for when the desired volume does not exist
+func VolumeIsNotKnown(volume string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Volume '%s' does not exist", volume),
+		"",
+		http.StatusNotFound)
+}
+
+// VolumeAlreadyKnown constructs an API error for when we have a conflict with an existing volume
+func VolumeAlreadyKnown(volume string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Volume '%s' already exists", volume),
+		"",
+		http.StatusConflict)
+}
+
+// VolumeIsBound constructs an API error for when an operation is blocked by volumes bound to applications
+func VolumeIsBound(volume string, apps ...string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Volume '%s' is bound to applications", volume),
+		strings.Join(apps, ", "),
+		http.StatusBadRequest)
+}
+
+// VolumeIsNotBound constructs an API error for when the volume to unbind is actually not bound to the app
+func VolumeIsNotBound(volume string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Volume '%s' is not bound", volume),
+		"",
+		http.StatusBadRequest)
+}
+
+// DrainIsNotKnown constructs an API error for when the desired log drain does not exist
+func DrainIsNotKnown(drain string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Log drain '%s' does not exist", drain),
+		"",
+		http.StatusNotFound)
+}
+
+// DrainAlreadyKnown constructs an API error for when we have a conflict with an existing log drain
+func DrainAlreadyKnown(url string) APIError {
+	return NewAPIError(
+		fmt.Sprintf("Log drain '%s' already exists", url),
+		"",
+		http.StatusConflict)
+}
@@ -34,7 +34,8 @@ type App struct {
 	Workload      *AppDeployment           `json:"deployment,omitempty"`
 	Status        ApplicationStatus        `json:"status"`
 	StatusMessage string                   `json:"statusmessage"`
-	StageID       string                   `json:"stage_id,omitempty"` // staging id, last run
+	StageID       string                   `json:"stage_id,omitempty"`     // staging id, last run
+	BuilderImage  string                   `json:"builderimage,omitempty"` // builder of the last staging
 }
 
 type PodInfo struct {
@@ -57,8 +58,12 @@ type AppDeployment struct {
 	Replicas        map[string]*PodInfo `json:"replicas"`
 	Username        string              `json:"username,omitempty"` // app creator
 	StageID         string              `json:"stage_id,omitempty"` // staging id, running app
+	ImageURL        string              `json:"image,omitempty"`    // image of the running app
 	Status          string              `json:"status,omitempty"`   // app replica status
 	Routes          []string            `json:"routes,omitempty"`   // app routes
+	Certificates    []RouteCertificate  `json:"certificates,omitempty"`
+	Endpoints       []RouteEndpoint     `json:"endpoints,omitempty"`   // connection endpoints of grpc and tcp routes
+	InternalURL     string              `json:"internalurl,omitempty"` // in-cluster url of the app
 }
 
 // NewApp returns a new app for name and namespace
@@ -131,6 +136,24 @@ func (ar *AppRef) MakeServiceSecretName() string {
 	return names.GenerateResourceName(ar.Name + "-svc")
 }
 
+// MakeVolumeSecretName returns the name of the kube secret holding the
+// bound volumes of the referenced application, and their mount paths
+func (ar *AppRef) MakeVolumeSecretName() string {
+	return names.GenerateResourceName(ar.Name + "-vol")
+}
+
+// MakeDrainSecretName returns the name of the kube secret holding the
+// log drains of the referenced application
+func (ar *AppRef) MakeDrainSecretName() string {
+	return names.GenerateResourceName(ar.Name + "-drain")
+}
+
+// MakeRouteTLSSecretName returns the name of the kube secret holding the
+// certificate configuration of the routes of the referenced application
+func (ar *AppRef) MakeRouteTLSSecretName() string {
+	return names.GenerateResourceName(ar.Name + "-routetls")
+}
+
 // MakeScaleSecretName returns the name of the kube secret holding the number
 // of desired instances for referenced application
 func (ar *AppRef) MakeScaleSecretName() string {
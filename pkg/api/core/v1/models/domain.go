@@ -0,0 +1,21 @@
+package models
+
+// Domain is an entry of the domain registry. A domain is either a plain domain,
+// e.g. `example.com`, or a wildcard domain, e.g. `*.example.com`. A domain
+// assigned to a namespace can be used by the routes of the applications in
+// that namespace. At most one of the domains of a namespace is its default.
+type Domain struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Default   bool   `json:"default,omitempty"`
+}
+
+// DomainList is a collection of registered domains
+type DomainList []Domain
+
+// DomainAssignRequest represents and contains the data needed to assign a
+// registered domain to a namespace. An empty namespace releases the domain.
+type DomainAssignRequest struct {
+	Namespace string `json:"namespace,omitempty"`
+	Default   bool   `json:"default,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Drain describes a log drain, i.e. an endpoint the logs of an application, or
+// of all applications of a namespace, are forwarded to, and the state of the
+// forwarding.
+// It is used in the CLI and API responses.
+type Drain struct {
+	ID        string      `json:"id"`
+	URL       string      `json:"url"`
+	Namespace string      `json:"namespace"`
+	App       string      `json:"app,omitempty"` // empty for the drains of a namespace
+	Status    DrainStatus `json:"status"`
+}
+
+// DrainStatus describes the state of the forwarding of logs to a drain.
+type DrainStatus struct {
+	Active       bool   `json:"active"`                 // drain is followed by the server
+	Delivered    int64  `json:"delivered"`              // number of log lines delivered
+	Dropped      int64  `json:"dropped"`                // number of log lines lost to a full queue
+	Pending      int    `json:"pending"`                // number of log lines waiting for delivery
+	Failures     int64  `json:"failures"`               // number of failed delivery attempts
+	LastDelivery string `json:"lastdelivery,omitempty"` // RFC3339 time of the last successful delivery
+	LastError    string `json:"lasterror,omitempty"`    // error of the last failed delivery or tailing, cleared on delivery
+}
+
+// DrainList is a collection of drains
+type DrainList []Drain
+
+// DrainCreateRequest represents and contains the data needed to add a log
+// drain to an application or namespace.
+type DrainCreateRequest struct {
+	URL string `json:"url"`
+}
+
+// DrainID returns the identifier of the drain for the url. It is stable, and
+// valid as path element, and as key of a kube secret.
+func DrainID(url string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(url)))[:12]
+}
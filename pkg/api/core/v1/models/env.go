@@ -70,21 +70,65 @@ func (evl EnvVariableList) ToEnvVarArray(appRef AppRef) []v1.EnvVar {
 		},
 	}
 
+	return append(deploymentEnvironment, evl.ToSecretRefArray(appRef.MakeEnvSecretName())...)
+}
+
+// ToSecretRefArray converts the collection of environment variables into
+// references to the same-named keys of the named secret.
+func (evl EnvVariableList) ToSecretRefArray(secretName string) []v1.EnvVar {
+	result := []v1.EnvVar{}
+
 	for _, ev := range evl {
-		deploymentEnvironment = append(deploymentEnvironment, v1.EnvVar{
+		result = append(result, v1.EnvVar{
 			Name: ev.Name,
 			ValueFrom: &v1.EnvVarSource{
 				SecretKeyRef: &v1.SecretKeySelector{
 					Key: ev.Name,
 					LocalObjectReference: v1.LocalObjectReference{
-						Name: appRef.MakeEnvSecretName(),
+						Name: secretName,
 					},
 				},
 			},
 		})
 	}
 
-	return deploymentEnvironment
+	return result
+}
+
+// Names returns the sorted names of the variables in the map.
+func (evm EnvVariableMap) Names() []string {
+	result := []string{}
+	for name := range evm {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Overlay returns a new map containing the variables of both maps. Where both
+// maps have a variable the value of the overlay wins.
+func (evm EnvVariableMap) Overlay(overlay EnvVariableMap) EnvVariableMap {
+	result := EnvVariableMap{}
+	for name, value := range evm {
+		result[name] = value
+	}
+	for name, value := range overlay {
+		result[name] = value
+	}
+	return result
+}
+
+// Without returns a new map containing the variables of the map which are
+// not named in the exclusion list.
+func (evm EnvVariableMap) Without(names []string) EnvVariableMap {
+	result := EnvVariableMap{}
+	for name, value := range evm {
+		result[name] = value
+	}
+	for _, name := range names {
+		delete(result, name)
+	}
+	return result
 }
 
 // StagingEnvArray returns the collection of environment variables and
@@ -0,0 +1,68 @@
+package models_test
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EnvVariableMap", func() {
+	DescribeTable("Overlay",
+		func(base, overlay, expected models.EnvVariableMap) {
+			baseCopy := base.Overlay(nil)
+
+			Expect(base.Overlay(overlay)).To(Equal(expected))
+			Expect(base).To(Equal(baseCopy), "the map is not modified")
+		},
+		Entry("of nothing",
+			models.EnvVariableMap{"A": "1"}, nil,
+			models.EnvVariableMap{"A": "1"}),
+		Entry("onto nothing",
+			models.EnvVariableMap{}, models.EnvVariableMap{"A": "1"},
+			models.EnvVariableMap{"A": "1"}),
+		Entry("merges distinct variables",
+			models.EnvVariableMap{"A": "1"}, models.EnvVariableMap{"B": "2"},
+			models.EnvVariableMap{"A": "1", "B": "2"}),
+		Entry("lets the overlay win",
+			models.EnvVariableMap{"A": "1", "B": "2"}, models.EnvVariableMap{"B": "app", "C": "3"},
+			models.EnvVariableMap{"A": "1", "B": "app", "C": "3"}),
+		Entry("keeps empty values of the overlay",
+			models.EnvVariableMap{"A": "1"}, models.EnvVariableMap{"A": ""},
+			models.EnvVariableMap{"A": ""}),
+	)
+
+	DescribeTable("Without",
+		func(base models.EnvVariableMap, names []string, expected models.EnvVariableMap) {
+			baseCopy := base.Overlay(nil)
+
+			Expect(base.Without(names)).To(Equal(expected))
+			Expect(base).To(Equal(baseCopy), "the map is not modified")
+		},
+		Entry("excludes nothing",
+			models.EnvVariableMap{"A": "1"}, nil,
+			models.EnvVariableMap{"A": "1"}),
+		Entry("excludes the named variables",
+			models.EnvVariableMap{"A": "1", "B": "2", "C": "3"}, []string{"A", "C"},
+			models.EnvVariableMap{"B": "2"}),
+		Entry("ignores unknown names",
+			models.EnvVariableMap{"A": "1"}, []string{"B"},
+			models.EnvVariableMap{"A": "1"}),
+		Entry("compares names exactly",
+			models.EnvVariableMap{"A": "1", "a": "2"}, []string{"a"},
+			models.EnvVariableMap{"A": "1"}),
+		Entry("excludes everything",
+			models.EnvVariableMap{"A": "1"}, []string{"A"},
+			models.EnvVariableMap{}),
+	)
+
+	It("injects the namespace defaults not set by the application", func() {
+		defaults := models.EnvVariableMap{"LOG_LEVEL": "info", "REGION": "eu"}
+		app := models.EnvVariableMap{"LOG_LEVEL": "debug"}
+
+		Expect(defaults.Without(app.Names()).Overlay(app)).To(Equal(models.EnvVariableMap{
+			"LOG_LEVEL": "debug",
+			"REGION":    "eu",
+		}))
+	})
+})
@@ -0,0 +1,47 @@
+package models
+
+// AppEvent describes a kube event of one of the resources of an application,
+// i.e. its deployment, replicasets, pods, and ingresses.
+type AppEvent struct {
+	Type      string `json:"type"`   // Normal or Warning
+	Reason    string `json:"reason"` // e.g. BackOff, FailedScheduling
+	Message   string `json:"message"`
+	Kind      string `json:"kind"`   // kind of the resource the event is about
+	Object    string `json:"object"` // name of the resource the event is about
+	Count     int32  `json:"count"`
+	FirstSeen string `json:"firstSeen,omitempty"` // RFC3339
+	LastSeen  string `json:"lastSeen,omitempty"`  // RFC3339
+}
+
+// AppEventList is a collection of events, ordered by the time they were last seen
+type AppEventList []AppEvent
+
+// InstanceDiagnostics describes the state of an instance of an application,
+// i.e. a pod, and the last termination of its application container.
+type InstanceDiagnostics struct {
+	Name            string                `json:"name"`
+	Phase           string                `json:"phase"`
+	Ready           bool                  `json:"ready"`
+	Restarts        int32                 `json:"restarts"`
+	Waiting         string                `json:"waiting,omitempty"` // reason the container is waiting, e.g. CrashLoopBackOff
+	LastTermination *ContainerTermination `json:"lastTermination,omitempty"`
+}
+
+// ContainerTermination describes how a container terminated.
+type ContainerTermination struct {
+	Reason     string `json:"reason"` // e.g. Error, OOMKilled, Completed
+	ExitCode   int32  `json:"exitCode"`
+	Signal     int32  `json:"signal,omitempty"`
+	Message    string `json:"message,omitempty"`
+	StartedAt  string `json:"startedAt,omitempty"`  // RFC3339
+	FinishedAt string `json:"finishedAt,omitempty"` // RFC3339
+	OOMKilled  bool   `json:"oomKilled"`            // container exceeded its memory limit
+}
+
+// AppEventsResponse is the response of the application events endpoint. It
+// contains the events of the application's resources, and the diagnostics of
+// its instances.
+type AppEventsResponse struct {
+	Events    AppEventList          `json:"events"`
+	Instances []InstanceDiagnostics `json:"instances"`
+}
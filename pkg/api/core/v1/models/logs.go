@@ -0,0 +1,18 @@
+package models
+
+// LogParameters represents the query parameters of the log endpoints, AppLogs
+// and StagingLogs, restricting the log lines streamed over the websocket.
+// Since is a duration, e.g. `1h`, reaching into the past. Empty means the
+// default history. A nil Tail means all lines, otherwise the number of last
+// lines to return per container. Include and Exclude are regular expressions a
+// log line has to match, respectively not match. Instance and Container select
+// the pod by name, and its containers by regular expression.
+type LogParameters struct {
+	Since      string   `json:"since,omitempty"`
+	Tail       *int64   `json:"tail,omitempty"`
+	Include    []string `json:"include,omitempty"`
+	Exclude    []string `json:"exclude,omitempty"`
+	Instance   string   `json:"instance,omitempty"`
+	Container  string   `json:"container,omitempty"`
+	Timestamps bool     `json:"timestamps,omitempty"`
+}
@@ -34,15 +34,33 @@ type ServiceResponseList []ServiceResponse
 // ServiceCreateRequest represents and contains the data needed to
 // create a service instance
 type ServiceCreateRequest struct {
-	Name string            `json:"name"`
-	Data map[string]string `json:"data"`
+	Name        string            `json:"name"                  yaml:"name"`
+	Data        map[string]string `json:"data"                  yaml:"data,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"      yaml:"labels,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// ServiceExport represents a single service instance in an export document.
+// When Encrypted is set the values in Data are sealed with a local key.
+type ServiceExport struct {
+	ServiceCreateRequest `yaml:",inline"`
+	Encrypted            bool `json:"encrypted,omitempty" yaml:"encrypted,omitempty"`
+}
+
+// ServiceExportDocument represents the contents of a file written by `epinio service export`
+// and read by `epinio service import`.
+type ServiceExportDocument struct {
+	Services []ServiceExport `json:"services" yaml:"services"`
 }
 
 // ServiceUpdateRequest represents and contains the data needed to
 // update a service instance (add/change, and remove keys)
 type ServiceUpdateRequest struct {
-	Remove []string          `json:"remove,omitempty"`
-	Set    map[string]string `json:"edit,omitempty"`
+	Remove       []string          `json:"remove,omitempty"`
+	Set          map[string]string `json:"edit,omitempty"`
+	RemoveLabels []string          `json:"removelabels,omitempty"`
+	SetLabels    map[string]string `json:"setlabels,omitempty"`
+	Description  *string           `json:"description,omitempty"`
 }
 
 // ServiceReplaceRequest represents and contains the data needed to
@@ -80,6 +98,15 @@ type ApplicationManifest struct {
 	Staging                  ApplicationStage  `yaml:"staging,omitempty"`
 }
 
+// StackManifest represents and contains the data of a stack file, as applied by `epinio
+// apply`. It declares several services and applications. The file's location is never
+// (un)marshaled.
+type StackManifest struct {
+	Self         string                 `yaml:"-"` // Hidden from yaml. The file's location.
+	Services     []ServiceCreateRequest `yaml:"services,omitempty"`
+	Applications []ApplicationManifest  `yaml:"applications,omitempty"`
+}
+
 // ApplicationStaging is the part of the manifest holding information relevant to staging
 // the application's sources. This is, currently, only the reference to the Paketo builder
 // image to use.
@@ -137,11 +164,20 @@ type ApplicationCreateRequest struct {
 // run, and the services bound to it.
 // Note: Instances is a pointer to give us a nil value separate from
 // actual integers, as means of communicating `default`/`no change`.
+// Routes are strings of the form `[grpc://]DOMAIN[/PATH][?OPTIONS]`, e.g.
+// `mydomain.org/api?https-redirect&timeout=30s`, or `tcp://PORT[?OPTIONS]`,
+// e.g. `tcp://1883?expose=nodeport&target=1884`.
+// Internal is a pointer for the same reason as Instances. An internal
+// application has no routes, and is reachable from within the cluster only.
+// Volumes maps the names of the bound volumes to their mount paths. Nil
+// means `no change`.
 type ApplicationUpdateRequest struct {
 	Instances   *int32         `json:"instances"   yaml:"instances,omitempty"`
 	Services    []string       `json:"services"    yaml:"services,omitempty"`
 	Environment EnvVariableMap `json:"environment" yaml:"environment,omitempty"`
 	Routes      []string       `json:"routes" yaml:"routes,omitempty"`
+	Internal    *bool          `json:"internal,omitempty" yaml:"internal,omitempty"`
+	Volumes     VolumeMounts   `json:"volumes,omitempty" yaml:"volumes,omitempty"`
 }
 
 type ImportGitResponse struct {
@@ -197,9 +233,11 @@ type EnvMatchResponse struct {
 
 // ServiceShowResponse contains details about a service
 type ServiceShowResponse struct {
-	Username  string            `json:"user"`
-	Details   map[string]string `json:"details,omitempty"`
-	BoundApps []string          `json:"boundapps"`
+	Username    string            `json:"user"`
+	Details     map[string]string `json:"details,omitempty"`
+	BoundApps   []string          `json:"boundapps"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Description string            `json:"description,omitempty"`
 }
 
 // InfoResponse contains information about Epinio and its components
@@ -8,6 +8,38 @@ type Namespace struct {
 	Services []string `json:"services,omitempty"`
 }
 
+// NamespaceQuota contains the resource limits of a namespace. Empty
+// quantities and zero counts stand for `no limit`. The instance
+// quantities are the limits applied to each application instance when
+// the namespace has cpu or memory limits.
+// It is used in the CLI and API requests and responses.
+type NamespaceQuota struct {
+	CPU            string `json:"cpu,omitempty"`
+	Memory         string `json:"memory,omitempty"`
+	Apps           int32  `json:"apps,omitempty"`
+	Services       int32  `json:"services,omitempty"`
+	Instances      int32  `json:"instances,omitempty"`
+	InstanceCPU    string `json:"instancecpu,omitempty"`
+	InstanceMemory string `json:"instancememory,omitempty"`
+}
+
+// NamespaceQuotaResponse contains the quota of a namespace, and the current usage
+// of the limited resources.
+type NamespaceQuotaResponse struct {
+	Namespace string         `json:"namespace"`
+	Quota     NamespaceQuota `json:"quota"`
+	Usage     NamespaceQuota `json:"usage"`
+}
+
+// NamespaceNetwork contains the network isolation of a namespace. An isolated
+// namespace denies traffic from other namespaces, except for the ingress
+// controller, and the namespaces listed in AllowFrom.
+// It is used in the CLI and API requests and responses.
+type NamespaceNetwork struct {
+	Isolated  bool     `json:"isolated"`
+	AllowFrom []string `json:"allowfrom,omitempty"`
+}
+
 // NamespaceList is a collection of namespaces
 type NamespaceList []Namespace
 
@@ -0,0 +1,68 @@
+package models
+
+// RouteTLS describes where the certificate of an application route comes from.
+// At most one of the fields is set. Without either the certificate is issued
+// by the server-wide default issuer.
+type RouteTLS struct {
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"` // existing kube TLS secret in the app's namespace
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"` // cert-manager ClusterIssuer
+}
+
+// String returns a compact representation of the TLS configuration, i.e.
+// `secret=NAME`, `issuer=NAME`, or the empty string for the default.
+func (t RouteTLS) String() string {
+	if t.Secret != "" {
+		return "secret=" + t.Secret
+	}
+	if t.Issuer != "" {
+		return "issuer=" + t.Issuer
+	}
+	return ""
+}
+
+// RouteCertRequest represents and contains the data needed to configure the
+// certificate of an application route. At most one of Secret, Issuer, and the
+// Certificate/Key pair may be specified. Specifying none of them returns the
+// route to the default issuer.
+type RouteCertRequest struct {
+	Route       string `json:"route"`
+	Secret      string `json:"secret,omitempty"`
+	Issuer      string `json:"issuer,omitempty"`
+	Certificate string `json:"certificate,omitempty"` // PEM encoded
+	Key         string `json:"key,omitempty"`         // PEM encoded
+}
+
+// RouteCertificate describes the certificate currently used by an active
+// application route.
+type RouteCertificate struct {
+	Route   string `json:"route"`
+	Secret  string `json:"secret"`
+	Source  string `json:"source"`            // see RouteTLS.String, empty for the default issuer
+	Expires string `json:"expires,omitempty"` // RFC3339, empty when the certificate is not (yet) available
+}
+
+// RouteMoveRequest represents and contains the data needed to move a route
+// between two applications of the same namespace.
+type RouteMoveRequest struct {
+	Route string `json:"route"`
+	From  string `json:"from"` // application currently using the route
+	To    string `json:"to"`   // application to use the route
+}
+
+// RouteEndpoint describes where clients connect to, for an active gRPC or TCP
+// application route.
+type RouteEndpoint struct {
+	Route    string `json:"route"`
+	Endpoint string `json:"endpoint"`
+}
+
+// RouteInfo describes an active application route, and the application owning it
+type RouteInfo struct {
+	Route       string            `json:"route"`
+	Namespace   string            `json:"namespace"`
+	App         string            `json:"app"`
+	Certificate *RouteCertificate `json:"certificate,omitempty"`
+}
+
+// RouteList is a collection of active routes
+type RouteList []RouteInfo
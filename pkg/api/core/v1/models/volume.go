@@ -0,0 +1,48 @@
+package models
+
+import "sort"
+
+// Volume describes a persistent volume of a namespace, and the applications it
+// is bound to.
+// It is used in the CLI and API responses.
+type Volume struct {
+	Name         string   `json:"name"`
+	Namespace    string   `json:"namespace"`
+	Size         string   `json:"size"`
+	StorageClass string   `json:"storageclass,omitempty"`
+	Status       string   `json:"status"` // phase of the claim, i.e. Pending, Bound, or Lost
+	BoundApps    []string `json:"boundapps,omitempty"`
+}
+
+// VolumeList is a collection of volumes
+type VolumeList []Volume
+
+// VolumeMounts maps the names of the volumes bound to an application to their
+// mount paths.
+type VolumeMounts map[string]string
+
+// Names returns the names of the volumes, ordered lexicographically.
+func (vm VolumeMounts) Names() []string {
+	result := []string{}
+	for name := range vm {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// VolumeCreateRequest represents and contains the data needed to create a
+// volume. An empty size requests the default size, an empty storage class the
+// default storage class of the cluster.
+type VolumeCreateRequest struct {
+	Name         string `json:"name"`
+	Size         string `json:"size,omitempty"`
+	StorageClass string `json:"storageclass,omitempty"`
+}
+
+// VolumeBindRequest represents and contains the data needed to bind a volume
+// to an application, at the mount path.
+type VolumeBindRequest struct {
+	Volume string `json:"volume"`
+	Path   string `json:"path"`
+}